@@ -0,0 +1,195 @@
+// Package apispec reads the query parameters of the GET operations in the
+// Operaton OpenAPI document (resources/operaton-rest-api.json) for filter
+// completion and validation.
+package apispec
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DateTimeLayout is the engine's date format of date-time query parameters.
+const DateTimeLayout = "2006-01-02T15:04:05.000-0700"
+
+// Param is a query parameter of a GET operation.
+type Param struct {
+	Name        string
+	Type        string // "string", "integer", "number", "boolean" or "array"
+	Format      string // e.g. "date-time", "int32"
+	Enum        []string
+	Description string
+}
+
+// Spec holds the query parameters of every GET operation by path.
+type Spec struct {
+	get map[string][]Param
+}
+
+// pagingParams are set by the table pager and never offered as filters.
+var pagingParams = map[string]bool{"firstResult": true, "maxResults": true}
+
+var placeholder = regexp.MustCompile(`\{[^}]*\}`)
+
+// normalizePath makes paths comparable regardless of placeholder names, e.g.
+// "/process-instance/{id}/variables" and "/process-instance/{parentId}/variables".
+func normalizePath(p string) string {
+	p = "/" + strings.Trim(p, "/")
+	return placeholder.ReplaceAllString(p, "{}")
+}
+
+// Load reads and parses the OpenAPI document at path.
+func Load(path string) (*Spec, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return Parse(data)
+}
+
+type schemaDoc struct {
+	Type   string        `json:"type"`
+	Format string        `json:"format"`
+	Enum   []interface{} `json:"enum"`
+	Items  *schemaDoc    `json:"items"`
+}
+
+type paramDoc struct {
+	Ref         string     `json:"$ref"`
+	Name        string     `json:"name"`
+	In          string     `json:"in"`
+	Description string     `json:"description"`
+	Schema      *schemaDoc `json:"schema"`
+}
+
+// Parse parses an OpenAPI 3 document.
+func Parse(data []byte) (*Spec, error) {
+	var doc struct {
+		Paths map[string]struct {
+			Get *struct {
+				Parameters []paramDoc `json:"parameters"`
+			} `json:"get"`
+		} `json:"paths"`
+		Components struct {
+			Parameters map[string]paramDoc `json:"parameters"`
+		} `json:"components"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("openapi: %w", err)
+	}
+	s := &Spec{get: map[string][]Param{}}
+	for path, item := range doc.Paths {
+		if item.Get == nil {
+			continue
+		}
+		var params []Param
+		for _, pd := range item.Get.Parameters {
+			if pd.Ref != "" {
+				pd = doc.Components.Parameters[strings.TrimPrefix(pd.Ref, "#/components/parameters/")]
+			}
+			if pd.In != "query" || pd.Name == "" || pagingParams[pd.Name] {
+				continue
+			}
+			p := Param{Name: pd.Name, Type: "string", Description: pd.Description}
+			if sc := pd.Schema; sc != nil {
+				if sc.Type != "" {
+					p.Type = sc.Type
+				}
+				p.Format = sc.Format
+				enum := sc.Enum
+				if sc.Type == "array" && sc.Items != nil {
+					enum = sc.Items.Enum
+				}
+				for _, v := range enum {
+					p.Enum = append(p.Enum, fmt.Sprint(v))
+				}
+			}
+			params = append(params, p)
+		}
+		sort.Slice(params, func(i, j int) bool { return params[i].Name < params[j].Name })
+		s.get[normalizePath(path)] = params
+	}
+	return s, nil
+}
+
+// QueryParams returns the query parameters of GET apiPath sorted by name; ok is
+// false when the document has no such operation.
+func (s *Spec) QueryParams(apiPath string) (params []Param, ok bool) {
+	if s == nil {
+		return nil, false
+	}
+	params, ok = s.get[normalizePath(apiPath)]
+	return params, ok
+}
+
+// Values returns the values completion offers for p: its enum or true/false.
+func (p Param) Values() []string {
+	if len(p.Enum) > 0 {
+		return p.Enum
+	}
+	if p.Type == "boolean" {
+		return []string{"true", "false"}
+	}
+	return nil
+}
+
+// TypeLabel describes the expected value, e.g. "boolean", "date-time" or "enum".
+func (p Param) TypeLabel() string {
+	switch {
+	case len(p.Enum) > 0:
+		return "enum"
+	case p.Format == "date-time":
+		return "date-time"
+	default:
+		return p.Type
+	}
+}
+
+// Validate checks value against the type of p.
+func (p Param) Validate(value string) error {
+	if value == "" {
+		return fmt.Errorf("%s: value is empty", p.Name)
+	}
+	if len(p.Enum) > 0 {
+		// arrays accept a comma-separated list of enum values
+		for _, v := range strings.Split(value, ",") {
+			if !contains(p.Enum, v) {
+				return fmt.Errorf("%s: %q is not one of %s", p.Name, v, strings.Join(p.Enum, ", "))
+			}
+		}
+		return nil
+	}
+	switch {
+	case p.Type == "boolean":
+		if value != "true" && value != "false" {
+			return fmt.Errorf("%s: expected true or false, got %q", p.Name, value)
+		}
+	case p.Type == "integer":
+		if _, err := strconv.ParseInt(value, 10, 64); err != nil {
+			return fmt.Errorf("%s: expected an integer, got %q", p.Name, value)
+		}
+	case p.Type == "number":
+		if _, err := strconv.ParseFloat(value, 64); err != nil {
+			return fmt.Errorf("%s: expected a number, got %q", p.Name, value)
+		}
+	case p.Format == "date-time":
+		if _, err := time.Parse(DateTimeLayout, value); err != nil {
+			return fmt.Errorf("%s: expected a date like 2026-01-31T00:00:00.000+0000, got %q", p.Name, value)
+		}
+	}
+	return nil
+}
+
+func contains(list []string, v string) bool {
+	for _, s := range list {
+		if s == v {
+			return true
+		}
+	}
+	return false
+}
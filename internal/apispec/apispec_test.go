@@ -0,0 +1,100 @@
+package apispec
+
+import (
+	"strings"
+	"testing"
+)
+
+const testDoc = `{
+  "openapi": "3.0.2",
+  "paths": {
+    "/process-instance": {
+      "get": {
+        "parameters": [
+          {"name": "withIncident", "in": "query", "schema": {"type": "boolean", "default": false}},
+          {"name": "firstResult", "in": "query", "schema": {"type": "integer"}},
+          {"name": "sortOrder", "in": "query", "schema": {"type": "string", "enum": ["asc", "desc"]}},
+          {"$ref": "#/components/parameters/startedAfter"}
+        ]
+      },
+      "post": {
+        "parameters": [{"name": "ignored", "in": "query", "schema": {"type": "string"}}]
+      }
+    },
+    "/process-instance/{id}/variables": {
+      "get": {
+        "parameters": [
+          {"name": "id", "in": "path", "schema": {"type": "string"}},
+          {"name": "deserializeValues", "in": "query", "schema": {"type": "boolean"}}
+        ]
+      }
+    }
+  },
+  "components": {
+    "parameters": {
+      "startedAfter": {"name": "startedAfter", "in": "query", "schema": {"type": "string", "format": "date-time"}}
+    }
+  }
+}`
+
+func TestParseQueryParams(t *testing.T) {
+	s, err := Parse([]byte(testDoc))
+	if err != nil {
+		t.Fatal(err)
+	}
+	params, ok := s.QueryParams("/process-instance")
+	if !ok {
+		t.Fatal("expected GET /process-instance")
+	}
+	var names []string
+	for _, p := range params {
+		names = append(names, p.Name)
+	}
+	if got := strings.Join(names, ","); got != "sortOrder,startedAfter,withIncident" {
+		t.Errorf("expected sorted params without paging and path params, got %s", got)
+	}
+	if params[1].TypeLabel() != "date-time" || params[0].TypeLabel() != "enum" {
+		t.Errorf("unexpected type labels %q, %q", params[1].TypeLabel(), params[0].TypeLabel())
+	}
+
+	// placeholder names do not matter
+	if params, ok := s.QueryParams("/process-instance/{parentId}/variables"); !ok || len(params) != 1 || params[0].Name != "deserializeValues" {
+		t.Errorf("expected the variables params, got %v %v", params, ok)
+	}
+	if _, ok := s.QueryParams("/unknown"); ok {
+		t.Error("expected no params for an unknown path")
+	}
+	var nilSpec *Spec
+	if _, ok := nilSpec.QueryParams("/process-instance"); ok {
+		t.Error("expected a nil spec to know no params")
+	}
+}
+
+func TestParamValidate(t *testing.T) {
+	cases := []struct {
+		p     Param
+		value string
+		ok    bool
+	}{
+		{Param{Name: "b", Type: "boolean"}, "true", true},
+		{Param{Name: "b", Type: "boolean"}, "yes", false},
+		{Param{Name: "i", Type: "integer"}, "42", true},
+		{Param{Name: "i", Type: "integer"}, "4.2", false},
+		{Param{Name: "n", Type: "number"}, "4.2", true},
+		{Param{Name: "e", Type: "string", Enum: []string{"asc", "desc"}}, "desc", true},
+		{Param{Name: "e", Type: "array", Enum: []string{"a", "b"}}, "a,b", true},
+		{Param{Name: "e", Type: "string", Enum: []string{"asc", "desc"}}, "up", false},
+		{Param{Name: "d", Type: "string", Format: "date-time"}, "2026-01-31T00:00:00.000+0000", true},
+		{Param{Name: "d", Type: "string", Format: "date-time"}, "2026-01-31", false},
+		{Param{Name: "s", Type: "string"}, "ServiceTask_1", true},
+		{Param{Name: "s", Type: "string"}, "", false},
+	}
+	for _, c := range cases {
+		if err := c.p.Validate(c.value); (err == nil) != c.ok {
+			t.Errorf("%s=%q: expected ok=%v, got %v", c.p.Name, c.value, c.ok, err)
+		}
+	}
+	if got := (Param{Type: "boolean"}).Values(); len(got) != 2 {
+		t.Errorf("expected true/false for a boolean, got %v", got)
+	}
+}
@@ -0,0 +1,332 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/kthoms/o6n/internal/apispec"
+	"github.com/kthoms/o6n/internal/client"
+	"github.com/kthoms/o6n/internal/config"
+)
+
+// alertEventsMax caps the fired alerts kept for the alerts modal.
+const alertEventsMax = 100
+
+// bellOutput receives the terminal bell rung when an alert fires.
+var bellOutput io.Writer = os.Stderr
+
+// alertEvent is an alert rule that fired in an environment.
+type alertEvent struct {
+	at    time.Time
+	env   string
+	rule  config.AlertRule
+	count int
+	prev  int // count of the evaluation before, if hadPrev
+	had   bool
+}
+
+// alertState holds the results of the alert poller and the alerts modal
+// (ModalAlerts).
+type alertState struct {
+	counts map[string]int    // last count per alertKey
+	errs   map[string]string // last evaluation error per alertKey
+	events []alertEvent      // newest first
+	unseen int               // events fired since the modal was last opened
+	cursor int
+	scroll int
+}
+
+// alertTickMsg starts an evaluation of every alert rule.
+type alertTickMsg struct{}
+
+// alertCountMsg delivers the count of a rule in an environment.
+type alertCountMsg struct {
+	env   string
+	rule  string
+	count int
+	err   error
+	at    time.Time
+}
+
+func alertKey(env, rule string) string {
+	return env + "/" + rule
+}
+
+// resolveNowParams returns params with the values "now" replaced by t.
+func resolveNowParams(in map[string]string, t time.Time) map[string]string {
+	params := make(map[string]string, len(in))
+	for k, v := range in {
+		if v == config.AlertNow {
+			v = t.Format(apispec.DateTimeLayout)
+		}
+		params[k] = v
+	}
+	return params
+}
+
+// alertTable returns the table a rule watches, resolving aliases.
+func (m *model) alertTable(rule config.AlertRule) string {
+	if t := m.resolveCommandTable(rule.Table); t != "" {
+		return t
+	}
+	return rule.Table
+}
+
+// alertIntervalOrDefault returns how often the rules are evaluated.
+func (m *model) alertIntervalOrDefault() time.Duration {
+	if m.alertInterval > 0 {
+		return m.alertInterval
+	}
+	return config.DefaultAlertInterval
+}
+
+// alertTickCmd schedules the next evaluation of the alert rules.
+func (m *model) alertTickCmd() tea.Cmd {
+	return tea.Tick(m.alertIntervalOrDefault(), func(time.Time) tea.Msg { return alertTickMsg{} })
+}
+
+// pollAlertsCmd counts the rows of every rule in every environment it watches
+// and schedules the next evaluation.
+func (m *model) pollAlertsCmd() tea.Cmd {
+	if len(m.alertRules) == 0 {
+		return nil
+	}
+	now := time.Now()
+	cmds := []tea.Cmd{m.alertTickCmd()}
+	for _, env := range m.envNames {
+		for _, rule := range m.alertRules {
+			if rule.WatchesEnv(env) {
+				cmds = append(cmds, m.countAlertCmd(env, rule, now))
+			}
+		}
+	}
+	return tea.Batch(cmds...)
+}
+
+// countAlertCmd fetches the count of the rows rule watches in env.
+func (m *model) countAlertCmd(envName string, rule config.AlertRule, now time.Time) tea.Cmd {
+	env, ok := m.config.Environments[envName]
+	if !ok {
+		return nil
+	}
+	_, countPath := m.tablePaths(m.alertTable(rule))
+	params := resolveNowParams(rule.Params, now)
+	return func() tea.Msg {
+		msg := alertCountMsg{env: envName, rule: rule.Name, at: now}
+		if strings.Contains(countPath, "{") {
+			msg.err = fmt.Errorf("%s needs a parent and cannot be counted", rule.Table)
+			return msg
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), env.RequestTimeout())
+		defer cancel()
+		urlStr := appendQueryFilters(strings.TrimRight(env.URL, "/")+"/"+strings.TrimLeft(countPath, "/"), params)
+		var body struct {
+			Count *int `json:"count"`
+		}
+		if err := client.GetJSON(ctx, env, urlStr, &body); err != nil {
+			msg.err = err
+		} else if body.Count == nil {
+			msg.err = errors.New("no count in response")
+		} else {
+			msg.count = *body.Count
+		}
+		return msg
+	}
+}
+
+// findAlertRule returns the rule called name.
+func (m *model) findAlertRule(name string) (config.AlertRule, bool) {
+	for _, r := range m.alertRules {
+		if r.Name == name {
+			return r, true
+		}
+	}
+	return config.AlertRule{}, false
+}
+
+// applyAlertCount records the count of a rule and fires the rule when its
+// condition is met: the alert is listed, counted in the header badge, shown
+// in the footer and rings the terminal bell.
+func (m *model) applyAlertCount(msg alertCountMsg) tea.Cmd {
+	rule, ok := m.findAlertRule(msg.rule)
+	if !ok {
+		return nil
+	}
+	key := alertKey(msg.env, msg.rule)
+	if m.alerts.counts == nil {
+		m.alerts.counts = map[string]int{}
+		m.alerts.errs = map[string]string{}
+	}
+	if msg.err != nil {
+		m.alerts.errs[key] = msg.err.Error()
+		log.Printf("alert %s in %s: %v", msg.rule, msg.env, msg.err)
+		return nil
+	}
+	delete(m.alerts.errs, key)
+	prev, had := m.alerts.counts[key]
+	m.alerts.counts[key] = msg.count
+	cond, err := config.ParseAlertCondition(rule.When)
+	if err != nil || !cond.Fires(prev, msg.count, had) {
+		return nil
+	}
+
+	ev := alertEvent{at: msg.at, env: msg.env, rule: rule, count: msg.count, prev: prev, had: had}
+	m.alerts.events = append([]alertEvent{ev}, m.alerts.events...)
+	if len(m.alerts.events) > alertEventsMax {
+		m.alerts.events = m.alerts.events[:alertEventsMax]
+	}
+	if m.activeModal == ModalAlerts {
+		m.alerts.cursor++
+	} else {
+		m.alerts.unseen++
+	}
+	text, kind, clear := setFooterStatus(footerStatusError, fmt.Sprintf("⚑ %s in %s: %s — ! to view", rule.Name, msg.env, describeAlertCount(ev)), 10*time.Second)
+	m.footerError, m.footerStatusKind = text, kind
+	return tea.Batch(bellCmd(), clear)
+}
+
+// bellCmd rings the terminal bell.
+func bellCmd() tea.Cmd {
+	return func() tea.Msg {
+		_, _ = io.WriteString(bellOutput, "\a")
+		return nil
+	}
+}
+
+// describeAlertCount renders the count of ev, with the previous count when
+// the rule fires on an increase.
+func describeAlertCount(ev alertEvent) string {
+	if ev.had && strings.TrimSpace(ev.rule.When) == "increased" {
+		return fmt.Sprintf("%d → %d", ev.prev, ev.count)
+	}
+	return fmt.Sprintf("count %d", ev.count)
+}
+
+// openAlerts opens the alerts modal and marks the alerts seen.
+func (m *model) openAlerts() {
+	m.alerts.unseen = 0
+	m.alerts.cursor, m.alerts.scroll = 0, 0
+	m.activeModal = ModalAlerts
+}
+
+// jumpToAlert opens the view the alert ev watches, in its environment.
+func (m *model) jumpToAlert(ev alertEvent) tea.Cmd {
+	m.activeModal = ModalNone
+	var cmds []tea.Cmd
+	if ev.env != m.currentEnv {
+		if _, ok := m.config.Environments[ev.env]; !ok {
+			return nil
+		}
+		m.switchToEnvironment(ev.env)
+		cmds = append(cmds, m.checkEnvironmentHealthCmd(ev.env))
+	}
+	cmds = append(cmds, m.openTable(m.alertTable(ev.rule), resolveNowParams(ev.rule.Params, time.Now())))
+	return tea.Batch(cmds...)
+}
+
+// handleAlertsKey processes a key press while ModalAlerts is open.
+func (m *model) handleAlertsKey(msg tea.KeyMsg) tea.Cmd {
+	n := len(m.alerts.events)
+	switch msg.String() {
+	case "esc", "q":
+		m.activeModal = ModalNone
+		return nil
+	case "enter":
+		if m.alerts.cursor >= 0 && m.alerts.cursor < n {
+			return m.jumpToAlert(m.alerts.events[m.alerts.cursor])
+		}
+		return nil
+	case "c":
+		m.alerts.events = nil
+		m.alerts.cursor, m.alerts.scroll = 0, 0
+		return nil
+	case "down", "j":
+		if m.alerts.cursor < n-1 {
+			m.alerts.cursor++
+		}
+	case "up", "k":
+		if m.alerts.cursor > 0 {
+			m.alerts.cursor--
+		}
+	case "home", "g":
+		m.alerts.cursor = 0
+	case "end", "G":
+		m.alerts.cursor = n - 1
+	}
+	if m.alerts.cursor < 0 {
+		m.alerts.cursor = 0
+	}
+	h := m.alertsViewHeight()
+	if m.alerts.cursor < m.alerts.scroll {
+		m.alerts.scroll = m.alerts.cursor
+	} else if m.alerts.cursor >= m.alerts.scroll+h {
+		m.alerts.scroll = m.alerts.cursor - h + 1
+	}
+	return nil
+}
+
+// alertsViewHeight is the number of alerts listed at once.
+func (m *model) alertsViewHeight() int {
+	h := m.lastHeight - 14
+	if h < 3 {
+		h = 3
+	}
+	return h
+}
+
+// describeAlertRule renders what a rule watches, e.g.
+// "incident processDefinitionKey=invoice: count > 0".
+func describeAlertRule(r config.AlertRule) string {
+	s := r.Table
+	for _, f := range sortedQueryFilters(r.Params) {
+		s += " " + f.name + "=" + f.value
+	}
+	return s + ": " + r.When
+}
+
+// modalAlertsBody renders the fired alerts, newest first, and the rules that
+// could not be evaluated.
+func (m *model) modalAlertsBody() string {
+	var b strings.Builder
+	b.WriteString(m.styles.Accent.Render(fmt.Sprintf("Alerts — %d rules, every %s", len(m.alertRules), m.alertIntervalOrDefault())) + "\n\n")
+	innerW := int(float64(m.lastWidth)*0.80) - 6
+	if innerW < 54 {
+		innerW = 54
+	}
+	if len(m.alertRules) == 0 {
+		b.WriteString(m.styles.FgMuted.Render("No alert rules — add them under alerts: in o6n-cfg.yaml") + "\n")
+	} else if len(m.alerts.events) == 0 {
+		b.WriteString(m.styles.FgMuted.Render("No alerts fired") + "\n")
+	}
+	end := m.alerts.scroll + m.alertsViewHeight()
+	if end > len(m.alerts.events) {
+		end = len(m.alerts.events)
+	}
+	for i := m.alerts.scroll; i < end; i++ {
+		ev := m.alerts.events[i]
+		line := fmt.Sprintf("%s  %-10s %-24s %-12s %s", ev.at.Local().Format("2006-01-02 15:04:05"),
+			ansi.Truncate(ev.env, 10, "…"), ansi.Truncate(ev.rule.Name, 24, "…"), describeAlertCount(ev), describeAlertRule(ev.rule))
+		line = ansi.Truncate(line, innerW-2, "…")
+		if i == m.alerts.cursor {
+			line = "> " + m.styles.PopupCursor.Render(line)
+		} else {
+			line = "  " + line
+		}
+		b.WriteString(line + "\n")
+	}
+	if len(m.alerts.errs) > 0 {
+		b.WriteString("\n")
+		for _, f := range sortedQueryFilters(m.alerts.errs) {
+			b.WriteString(m.styles.ValidationError.Render(ansi.Truncate("⚠ "+f.name+": "+f.value, innerW-2, "…")) + "\n")
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,108 @@
+package app
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/kthoms/o6n/internal/config"
+)
+
+func TestAlertRulesFireOncePerTransition(t *testing.T) {
+	var mu sync.Mutex
+	counts := map[string]string{"/job/count": "0", "/task/count": "0"}
+	var queries []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		queries = append(queries, r.URL.Path+"?"+r.URL.RawQuery)
+		_, _ = w.Write([]byte(`{"count":` + counts[r.URL.Path] + `}`))
+	}))
+	defer srv.Close()
+	setCount := func(path, n string) {
+		mu.Lock()
+		counts[path] = n
+		mu.Unlock()
+	}
+
+	m := modeTestModel(t, config.ModeNormal)
+	m.config.Environments["prod"] = config.Environment{URL: srv.URL}
+	m.lastWidth, m.lastHeight = 160, 40
+	stuck := config.AlertRule{Name: "stuck-jobs", Table: "job", Params: map[string]string{"noRetriesLeft": "true"}, When: "increased", Envs: []string{"prod"}}
+	overdue := config.AlertRule{Name: "overdue-tasks", Table: "task", Params: map[string]string{"dueBefore": config.AlertNow}, When: "count > 0"}
+	m.alertRules = []config.AlertRule{stuck, overdue}
+
+	eval := func(rule config.AlertRule) {
+		t.Helper()
+		res, _ := m.Update(m.countAlertCmd("prod", rule, time.Now())())
+		m = res.(model)
+	}
+	eval(stuck)
+	eval(overdue)
+	if len(m.alerts.events) != 0 || len(m.alerts.errs) != 0 {
+		t.Fatalf("expected nothing to fire on quiet counts, got %v %v", m.alerts.events, m.alerts.errs)
+	}
+
+	setCount("/job/count", "3")
+	setCount("/task/count", "1")
+	eval(stuck)
+	eval(overdue)
+	eval(overdue)
+	if len(m.alerts.events) != 2 || m.alerts.unseen != 2 {
+		t.Fatalf("expected each rule to fire once, got %d events", len(m.alerts.events))
+	}
+	if ev := m.alerts.events[1]; ev.rule.Name != "stuck-jobs" || describeAlertCount(ev) != "0 → 3" {
+		t.Errorf("expected the increase to be recorded, got %+v", ev)
+	}
+	if !strings.Contains(m.footerError, "overdue-tasks in prod") {
+		t.Errorf("expected the alert in the footer, got %q", m.footerError)
+	}
+	if out := m.View(); !strings.Contains(out, "⚑ 2") {
+		t.Errorf("expected the alert badge in the header, got:\n%s", out)
+	}
+	mu.Lock()
+	got := strings.Join(queries, "\n")
+	mu.Unlock()
+	if !strings.Contains(got, "/job/count?noRetriesLeft=true") || !strings.Contains(got, "/task/count?dueBefore=20") {
+		t.Errorf("expected the rule params in the count queries, got:\n%s", got)
+	}
+
+	m, _ = sendKeyString(m, "!")
+	if m.activeModal != ModalAlerts || m.alerts.unseen != 0 {
+		t.Fatal("expected ! to open the alerts and mark them seen")
+	}
+	if body := m.modalAlertsBody(); !strings.Contains(body, "stuck-jobs") || !strings.Contains(body, "task dueBefore=now: count > 0") {
+		t.Errorf("expected the fired alerts to be listed, got:\n%s", body)
+	}
+	m, _ = sendKeyString(m, "down")
+	m, cmd := sendKeyString(m, "enter")
+	if cmd == nil || m.activeModal != ModalNone || m.currentEnv != "prod" || m.currentRoot != "job" || m.queryFilters["noRetriesLeft"] != "true" {
+		t.Errorf("expected Enter to open the alert's view, got %s/%s %v", m.currentEnv, m.currentRoot, m.queryFilters)
+	}
+}
+
+func TestAlertErrorsAreListed(t *testing.T) {
+	m := modeTestModel(t, config.ModeNormal)
+	m.alertRules = []config.AlertRule{{Name: "broken", Table: "job", When: "count > 0"}}
+	res, _ := m.Update(alertCountMsg{env: "local", rule: "broken", err: errors.New("boom")})
+	m = res.(model)
+	if body := m.modalAlertsBody(); !strings.Contains(body, "local/broken: boom") {
+		t.Errorf("expected the evaluation error to be shown, got:\n%s", body)
+	}
+}
+
+func TestBellCmdRingsTheBell(t *testing.T) {
+	var buf bytes.Buffer
+	old := bellOutput
+	bellOutput = &buf
+	defer func() { bellOutput = old }()
+	bellCmd()()
+	if buf.String() != "\a" {
+		t.Errorf("expected a bell, got %q", buf.String())
+	}
+}
@@ -0,0 +1,298 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/kthoms/o6n/internal/audit"
+	"github.com/kthoms/o6n/internal/client"
+	"github.com/kthoms/o6n/internal/config"
+)
+
+// auditState holds the audit log viewer (ModalAudit).
+type auditState struct {
+	records    []audit.Record // oldest first, as read from the log
+	cursor     int
+	scroll     int
+	allEnvs    bool // show every environment instead of the current one
+	failedOnly bool
+	loading    bool
+	err        string
+}
+
+// auditLoadedMsg delivers the records of the audit log.
+type auditLoadedMsg struct {
+	records []audit.Record
+	err     error
+}
+
+// auditContext labels the changing requests made with the returned context in
+// the audit log with the current environment and action.
+func (m *model) auditContext(action string) context.Context {
+	return client.WithAudit(context.Background(), m.currentEnv, action)
+}
+
+// loadAuditCmd reads the audit log.
+func loadAuditCmd() tea.Cmd {
+	path := client.AuditFile
+	return func() tea.Msg {
+		if path == "" {
+			return auditLoadedMsg{err: errors.New("the audit log is disabled")}
+		}
+		records, err := audit.Read(path)
+		return auditLoadedMsg{records: records, err: err}
+	}
+}
+
+// openAudit opens the audit log viewer on the current environment.
+func (m *model) openAudit() tea.Cmd {
+	m.audit = auditState{loading: true}
+	m.activeModal = ModalAudit
+	return loadAuditCmd()
+}
+
+// applyAudit stores loaded records if the viewer is still open.
+func (m *model) applyAudit(msg auditLoadedMsg) {
+	if m.activeModal != ModalAudit {
+		return
+	}
+	m.audit.loading = false
+	m.audit.records = msg.records
+	m.audit.err = ""
+	if msg.err != nil {
+		m.audit.err = msg.err.Error()
+	}
+	m.audit.cursor, m.audit.scroll = 0, 0
+}
+
+// auditFilter returns the filter of the viewer's toggles.
+func (m *model) auditFilter() audit.Filter {
+	f := audit.Filter{Failed: m.audit.failedOnly}
+	if !m.audit.allEnvs {
+		f.Env = m.currentEnv
+	}
+	return f
+}
+
+// handleAuditKey processes a key press while ModalAudit is open.
+func (m *model) handleAuditKey(msg tea.KeyMsg) tea.Cmd {
+	n := len(m.auditFilter().Apply(m.audit.records))
+	switch msg.String() {
+	case "esc", "q":
+		m.activeModal = ModalNone
+		m.audit = auditState{}
+		return nil
+	case "down", "j":
+		if m.audit.cursor < n-1 {
+			m.audit.cursor++
+		}
+	case "up", "k":
+		if m.audit.cursor > 0 {
+			m.audit.cursor--
+		}
+	case "home", "g":
+		m.audit.cursor = 0
+	case "end", "G":
+		m.audit.cursor = n - 1
+	case "a":
+		m.audit.allEnvs = !m.audit.allEnvs
+		m.audit.cursor, m.audit.scroll = 0, 0
+	case "f":
+		m.audit.failedOnly = !m.audit.failedOnly
+		m.audit.cursor, m.audit.scroll = 0, 0
+	case "r":
+		m.audit.loading = true
+		return loadAuditCmd()
+	}
+	if m.audit.cursor < 0 {
+		m.audit.cursor = 0
+	}
+	h := m.auditViewHeight()
+	if m.audit.cursor < m.audit.scroll {
+		m.audit.scroll = m.audit.cursor
+	} else if m.audit.cursor >= m.audit.scroll+h {
+		m.audit.scroll = m.audit.cursor - h + 1
+	}
+	return nil
+}
+
+// auditViewHeight is the number of records listed at once; the rest of the
+// modal shows the details of the selected record.
+func (m *model) auditViewHeight() int {
+	h := m.lastHeight - 20
+	if h < 3 {
+		h = 3
+	}
+	return h
+}
+
+// auditResultMark renders the outcome of r, e.g. "✓ 204" or "✗ 404".
+func auditResultMark(r audit.Record) string {
+	mark := "✓"
+	if r.Result != audit.ResultOK {
+		mark = "✗"
+	}
+	if r.Status > 0 {
+		return fmt.Sprintf("%s %d", mark, r.Status)
+	}
+	return mark + " " + r.Result
+}
+
+// modalAuditBody renders the audit log, newest first, with the details of the
+// selected record below.
+func (m *model) modalAuditBody() string {
+	var b strings.Builder
+	scope := m.currentEnv
+	if m.audit.allEnvs {
+		scope = "all environments"
+	}
+	if m.audit.failedOnly {
+		scope += ", failed only"
+	}
+	b.WriteString(m.styles.Accent.Render("Audit log — "+scope) + "\n")
+	b.WriteString(m.styles.FgMuted.Render(client.AuditFile) + "\n\n")
+	if m.audit.loading {
+		b.WriteString("Loading…\n")
+	}
+	if m.audit.err != "" {
+		b.WriteString(m.styles.ValidationError.Render(m.audit.err) + "\n")
+	}
+	records := m.auditFilter().Apply(m.audit.records)
+	if len(records) == 0 {
+		if !m.audit.loading && m.audit.err == "" {
+			b.WriteString(m.styles.FgMuted.Render("No changes recorded") + "\n")
+		}
+		return b.String()
+	}
+
+	innerW := int(float64(m.lastWidth)*0.80) - 6
+	if innerW < 54 {
+		innerW = 54
+	}
+	end := m.audit.scroll + m.auditViewHeight()
+	if end > len(records) {
+		end = len(records)
+	}
+	for i := m.audit.scroll; i < end; i++ {
+		r := records[i]
+		line := fmt.Sprintf("%s  %-10s %-28s %-6s %s", r.Time.Local().Format("2006-01-02 15:04:05"),
+			ansi.Truncate(r.Env, 10, "…"), ansi.Truncate(r.Action, 28, "…"), auditResultMark(r), r.Method+" "+r.Path)
+		line = ansi.Truncate(line, innerW-2, "…")
+		switch {
+		case i == m.audit.cursor:
+			line = "> " + m.styles.PopupCursor.Render(line)
+		case r.Result != audit.ResultOK:
+			line = "  " + m.styles.RowFailed.Render(line)
+		default:
+			line = "  " + line
+		}
+		b.WriteString(line + "\n")
+	}
+
+	if m.audit.cursor < len(records) {
+		r := records[m.audit.cursor]
+		user := r.User
+		if user == "" {
+			user = "-"
+		}
+		b.WriteString("\n")
+		fmt.Fprintf(&b, "%s %s %s  (%s)\n", r.Method, r.Path, auditResultMark(r), r.Duration)
+		fmt.Fprintf(&b, "Environment: %s   User: %s   OS user: %s\n", r.Env, user, r.OSUser)
+		if r.Error != "" {
+			b.WriteString(m.styles.ValidationError.Render(ansi.Truncate("Error: "+r.Error, innerW, "…")) + "\n")
+		}
+		if r.Body != "" {
+			b.WriteString(m.styles.FgMuted.Render(ansi.Truncate("Body: "+strings.Join(strings.Fields(r.Body), " "), innerW, "…")) + "\n")
+		}
+	}
+	return b.String()
+}
+
+// runAudit implements `o6n audit`.
+func runAudit(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("audit", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	var filter audit.Filter
+	fs.StringVar(&filter.Env, "env", "", "only this environment")
+	fs.StringVar(&filter.Action, "action", "", "only actions containing this text")
+	fs.StringVar(&filter.User, "user", "", "only this Operaton or OS user (substring)")
+	fs.BoolVar(&filter.Failed, "failed", false, "only failed or refused changes")
+	since := fs.String("since", "", "only changes since a duration ago (24h, 7d) or a date (2006-01-02)")
+	limit := fs.Int("limit", 0, "maximum number of records, newest first (0 = all)")
+	output := fs.String("o", "table", "output format: table|json")
+	fs.StringVar(output, "output", "table", "output format: table|json")
+	file := fs.String("file", "", "audit log to read (default: audit_file of o6n-env.yaml)")
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: o6n audit [--env name] [--since 24h] [--action text] [--user name] [--failed] [-o table|json]")
+		fs.PrintDefaults()
+	}
+
+	positional, err := parseInterspersed(fs, args)
+	if err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		return 2
+	}
+	if len(positional) != 0 {
+		fs.Usage()
+		return 2
+	}
+	format := strings.ToLower(*output)
+	if format != "table" && format != "json" {
+		fmt.Fprintf(stderr, "unsupported output format %q\n", *output)
+		return 2
+	}
+	if filter.Since, err = audit.ParseSince(*since, time.Now()); err != nil {
+		fmt.Fprintf(stderr, "Error: %v\n", err)
+		return 2
+	}
+	path := *file
+	if path == "" {
+		envCfg, err := config.LoadEnvConfig(envConfigPath)
+		if err != nil {
+			fmt.Fprintf(stderr, "Error: %v\n", err)
+			return 1
+		}
+		path = envCfg.AuditPath()
+	}
+	records, err := audit.Read(path)
+	if err != nil {
+		fmt.Fprintf(stderr, "Error: %v\n", err)
+		return 1
+	}
+	records = filter.Apply(records)
+	if *limit > 0 && len(records) > *limit {
+		records = records[:*limit]
+	}
+
+	if format == "json" {
+		enc := json.NewEncoder(stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(records); err != nil {
+			fmt.Fprintf(stderr, "Error: %v\n", err)
+			return 1
+		}
+		return 0
+	}
+	tw := tabwriter.NewWriter(stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "TIME\tENV\tUSER\tOS USER\tACTION\tREQUEST\tRESULT")
+	for _, r := range records {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n", r.Time.Local().Format("2006-01-02 15:04:05"),
+			r.Env, r.User, r.OSUser, r.Action, r.Method+" "+r.Path, auditResultMark(r))
+	}
+	if err := tw.Flush(); err != nil {
+		fmt.Fprintf(stderr, "Error: %v\n", err)
+		return 1
+	}
+	return 0
+}
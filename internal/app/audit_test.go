@@ -0,0 +1,61 @@
+package app
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/kthoms/o6n/internal/client"
+	"github.com/kthoms/o6n/internal/config"
+)
+
+func TestAuditLogRecordsActionsAndIsViewable(t *testing.T) {
+	saved := client.AuditFile
+	client.AuditFile = filepath.Join(t.TempDir(), "audit.jsonl")
+	t.Cleanup(func() { client.AuditFile = saved })
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	m := modeTestModel(t, config.ModeNormal)
+	m.config.Environments["local"] = config.Environment{URL: srv.URL, Username: "demo"}
+	act := m.config.Tables[0].Actions[0]
+	if msg := m.executeActionCmd(act, resolveActionPath(act, "inst-1"))(); msg == nil {
+		t.Fatal("expected the action to finish")
+	} else if _, ok := msg.(actionExecutedMsg); !ok {
+		t.Fatalf("expected the action to succeed, got %#v", msg)
+	}
+
+	m2, cmd := sendKeyString(m, "H")
+	if m2.activeModal != ModalAudit || cmd == nil {
+		t.Fatalf("expected H to open the audit log, got modal %v", m2.activeModal)
+	}
+	m2.applyAudit(cmd().(auditLoadedMsg))
+	out := m2.modalAuditBody()
+	for _, want := range []string{"Audit log — local", "Suspend Instance", "PUT /process-instance/inst-1/suspended", "✓ 204", `{"suspended":true}`} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in the audit viewer, got:\n%s", want, out)
+		}
+	}
+	m3, _ := sendKeyString(m2, "f")
+	if out := m3.modalAuditBody(); !strings.Contains(out, "No changes recorded") {
+		t.Errorf("expected no failed changes, got:\n%s", out)
+	}
+
+	var stdout, stderr bytes.Buffer
+	if code := runAudit([]string{"--file", client.AuditFile, "--env", "local", "--action", "suspend"}, &stdout, &stderr); code != 0 {
+		t.Fatalf("expected exit 0, got %d (%s)", code, stderr.String())
+	}
+	if !strings.Contains(stdout.String(), "Suspend Instance") || !strings.Contains(stdout.String(), "demo") {
+		t.Errorf("expected the recorded action, got:\n%s", stdout.String())
+	}
+	stdout.Reset()
+	if code := runAudit([]string{"--file", client.AuditFile, "--env", "prod"}, &stdout, &stderr); code != 0 || strings.Count(stdout.String(), "\n") != 1 {
+		t.Errorf("expected only the header for another environment, got %d:\n%s", code, stdout.String())
+	}
+}
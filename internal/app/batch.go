@@ -0,0 +1,139 @@
+package app
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/table"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/kthoms/o6n/internal/config"
+)
+
+// batchStatisticsRoot is the table a submitted batch is tracked in.
+const batchStatisticsRoot = "batch-statistics"
+
+// confirmBatchAction opens the confirmation for submitting a batch action against
+// the whole current filter. The filter is snapshotted so that the confirmed batch
+// matches exactly what the dialog showed. A search the engine cannot apply
+// refuses the batch rather than widening it to rows the table hides.
+func (m *model) confirmBatchAction(action config.ActionDef) tea.Cmd {
+	params, ok := m.scopeParams()
+	if !ok {
+		msg, kind, cmd := setFooterStatus(footerStatusError,
+			"Clear the search first: "+m.currentRoot+" has no server-side search to scope a batch to", 4*time.Second)
+		m.footerError, m.footerStatusKind = msg, kind
+		return cmd
+	}
+	count := -1
+	if total, ok := m.pageTotals[m.currentRoot]; ok {
+		count = total
+	}
+	act := action
+	m.pendingAction = &act
+	m.pendingBatchParams = params
+	m.pendingBatchCount = count
+	m.pendingBulkIDs = nil
+	m.pendingActionID = ""
+	m.pendingActionPath = ""
+	m.activeModal = ModalConfirmDelete
+	m.confirmFocusedBtn = 1 // default to Cancel (safe)
+	return nil
+}
+
+// renderBatchConfirmBody renders the confirmation for a pending batch action.
+func (m *model) renderBatchConfirmBody() string {
+	act := m.pendingAction
+	var b strings.Builder
+	fmt.Fprintf(&b, "⚠️  %s — server-side batch\n\n", strings.ToUpper(act.Label))
+	scope := fmt.Sprintf("all %s rows matching the current filter", m.currentRoot)
+	if m.pendingBatchCount >= 0 {
+		scope = fmt.Sprintf("all %d %s rows matching the current filter", m.pendingBatchCount, m.currentRoot)
+	}
+	fmt.Fprintf(&b, "The engine will apply this action to %s:\n\n", scope)
+	if len(m.pendingBatchParams) == 0 {
+		b.WriteString("  (no filter — EVERY " + m.currentRoot + " is affected)\n")
+	} else {
+		keys := make([]string, 0, len(m.pendingBatchParams))
+		for k := range m.pendingBatchParams {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			fmt.Fprintf(&b, "  %s = %s\n", k, m.pendingBatchParams[k])
+		}
+	}
+	return b.String()
+}
+
+// startBatchTracking jumps to the batch-statistics view of a freshly submitted batch
+// and starts polling its progress.
+func (m *model) startBatchTracking(label, batchID string) tea.Cmd {
+	m.trackedBatchID = batchID
+	m.trackedBatchLabel = label
+
+	m.prepareStateTransition(TransitionDrillDown)
+	m.currentRoot = batchStatisticsRoot
+	m.viewMode = batchStatisticsRoot
+	m.genericParams = map[string]string{"batchId": batchID}
+	m.breadcrumb = append(m.breadcrumb, batchStatisticsRoot)
+	m.contentHeader = fmt.Sprintf("%s — %s", batchStatisticsRoot, batchID)
+
+	cols := m.buildColumnsFor(batchStatisticsRoot, m.paneWidth-4)
+	m.table.SetRows([]table.Row{})
+	if len(cols) > 0 {
+		m.table.SetColumns(cols)
+		m.table.SetRows(normalizeRows(nil, len(cols)))
+	}
+	m.table.SetCursor(0)
+
+	m.footerError, m.footerStatusKind, _ = setFooterStatus(footerStatusLoading,
+		fmt.Sprintf("%s: batch %s submitted", label, batchID), 0)
+	return tea.Batch(m.fetchGenericCmd(batchStatisticsRoot), m.pollBatchCmd(batchID), flashOnCmd())
+}
+
+// updateBatchProgress applies a poll result of the tracked batch: it updates the
+// footer, refreshes the batch-statistics view and keeps polling until the batch is done.
+func (m *model) updateBatchProgress(msg batchProgressMsg) tea.Cmd {
+	if msg.batchID != m.trackedBatchID {
+		return nil // tracking was replaced by a newer batch
+	}
+	var refresh tea.Cmd
+	if m.currentRoot == batchStatisticsRoot {
+		refresh = m.fetchGenericCmd(batchStatisticsRoot)
+	}
+	// Finished batches disappear from the statistics; a batch whose remaining jobs
+	// all failed (no retries left) will not make further progress either.
+	if !msg.found || (msg.err == nil && msg.total > 0 && msg.remaining <= msg.failed) {
+		label := m.trackedBatchLabel
+		m.trackedBatchID = ""
+		m.trackedBatchLabel = ""
+		var statusCmd tea.Cmd
+		if msg.found && msg.failed > 0 {
+			m.footerError, m.footerStatusKind, statusCmd = setFooterStatus(footerStatusError,
+				fmt.Sprintf("%s: batch %s finished, %d jobs failed", label, msg.batchID, msg.failed), 5*time.Second)
+		} else {
+			m.footerError, m.footerStatusKind, statusCmd = setFooterStatus(footerStatusSuccess,
+				fmt.Sprintf("%s: batch %s completed", label, msg.batchID), 5*time.Second)
+		}
+		return tea.Batch(statusCmd, refresh)
+	}
+	if msg.err != nil {
+		m.footerError, m.footerStatusKind, _ = setFooterStatus(footerStatusError,
+			fmt.Sprintf("%s: batch %s: %s", m.trackedBatchLabel, msg.batchID, friendlyError(m.currentEnv, msg.err)), 0)
+	} else {
+		m.footerError, m.footerStatusKind, _ = setFooterStatus(footerStatusLoading, m.batchProgressText(msg), 0)
+	}
+	return tea.Batch(refresh, m.pollBatchCmd(msg.batchID))
+}
+
+// batchProgressText renders the footer progress of a tracked batch,
+// e.g. "Retry All Matching: batch b-1 30/40 jobs (2 failed)".
+func (m *model) batchProgressText(msg batchProgressMsg) string {
+	text := fmt.Sprintf("%s: batch %s %d/%d jobs", m.trackedBatchLabel, msg.batchID, msg.completed, msg.total)
+	if msg.failed > 0 {
+		text += fmt.Sprintf(" (%d failed)", msg.failed)
+	}
+	return text
+}
@@ -0,0 +1,175 @@
+package app
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/kthoms/o6n/internal/config"
+)
+
+// batchRetryAction is the job "Retry All Matching" action as configured in o6n-cfg.yaml.
+var batchRetryAction = config.ActionDef{Key: "R", Label: "Retry All Matching", Type: "batch", Batch: "job-retries"}
+
+func TestBatchActionConfirmShowsFilter(t *testing.T) {
+	m := tableTestModel(t, "http://localhost:0", "job", []string{"id"}, bulkTestRows)
+	m.config.Tables[1].Actions = append(m.config.Tables[1].Actions, batchRetryAction)
+	m.genericParams = map[string]string{"withException": "true"}
+	m.pageTotals = map[string]int{"job": 1234}
+
+	var item actionItem
+	for _, it := range m.buildActionsForRoot() {
+		if it.key == "R" {
+			item = it
+		}
+	}
+	if item.cmd == nil {
+		t.Fatal("expected batch action in the actions menu")
+	}
+	if cmd := item.cmd(&m); cmd != nil {
+		t.Error("expected no command before confirmation")
+	}
+	if m.activeModal != ModalConfirmDelete || m.pendingBatchParams["withException"] != "true" {
+		t.Fatalf("expected batch confirmation with filter snapshot, got modal %v params %v", m.activeModal, m.pendingBatchParams)
+	}
+	body := m.renderConfirmDeleteModal(0, 0)
+	if !strings.Contains(body, "1234 job rows") || !strings.Contains(body, "withException = true") {
+		t.Errorf("expected count and filter in confirmation, got %q", body)
+	}
+
+	m2, _ := sendKeyString(m, "esc")
+	if m2.pendingBatchParams != nil || m2.pendingAction != nil {
+		t.Error("expected pending batch cleared on cancel")
+	}
+}
+
+func TestBatchActionScopedToSearch(t *testing.T) {
+	m := tableTestModel(t, "http://localhost:0", "job", []string{"id"}, bulkTestRows)
+	m.genericParams = map[string]string{"withException": "true"}
+	m.searchTerm = "job-3"
+
+	if cmd := m.confirmBatchAction(batchRetryAction); cmd == nil || m.activeModal != ModalNone {
+		t.Fatal("expected a search without search_param to refuse the batch")
+	}
+	if m.footerStatusKind != footerStatusError || !strings.Contains(m.footerError, "Clear the search") {
+		t.Errorf("expected footer error, got %q", m.footerError)
+	}
+
+	m.config.Tables[1].SearchParam = "idLike"
+	m.confirmBatchAction(batchRetryAction)
+	if m.activeModal != ModalConfirmDelete || m.pendingBatchParams["idLike"] != "job-3" || m.pendingBatchParams["withException"] != "true" {
+		t.Errorf("expected the search term in the batch filter, got modal %v params %v", m.activeModal, m.pendingBatchParams)
+	}
+}
+
+func TestBatchActionSubmitsAndTracksProgress(t *testing.T) {
+	polls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/job/retries":
+			var body map[string]interface{}
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			query, _ := body["jobQuery"].(map[string]interface{})
+			if query["withException"] != true || body["retries"] != 1.0 {
+				t.Errorf("unexpected batch body %v", body)
+			}
+			_, _ = w.Write([]byte(`{"id":"b-1","totalJobs":4}`))
+		case r.URL.Path == "/batch/statistics":
+			if r.URL.Query().Get("batchId") != "b-1" {
+				t.Errorf("expected batchId filter, got %v", r.URL.Query())
+			}
+			polls++
+			if polls == 1 {
+				_, _ = w.Write([]byte(`[{"id":"b-1","totalJobs":4,"completedJobs":1,"remainingJobs":3,"failedJobs":1}]`))
+				return
+			}
+			_, _ = w.Write([]byte(`[]`))
+		default:
+			_, _ = w.Write([]byte(`[]`))
+		}
+	}))
+	defer srv.Close()
+
+	m := tableTestModel(t, srv.URL, "job", []string{"id"}, bulkTestRows)
+	m.genericParams = map[string]string{"withException": "true"}
+	m.confirmBatchAction(batchRetryAction)
+
+	m2, cmd := sendKeyString(m, "ctrl+d")
+	if m2.activeModal != ModalNone || m2.pendingBatchParams != nil {
+		t.Fatal("expected confirmation closed")
+	}
+	submitted := findMsg[batchSubmittedMsg](t, cmd)
+	if submitted.batchID != "b-1" {
+		t.Fatalf("expected batch b-1, got %q", submitted.batchID)
+	}
+
+	res, _ := m2.Update(submitted)
+	m3 := res.(model)
+	if m3.currentRoot != "batch-statistics" || m3.genericParams["batchId"] != "b-1" {
+		t.Fatalf("expected jump to batch-statistics for b-1, got %s %v", m3.currentRoot, m3.genericParams)
+	}
+	if len(m3.navigationStack) != 1 {
+		t.Error("expected the job view on the navigation stack so Esc returns to it")
+	}
+
+	defer func(d time.Duration) { batchPollInterval = d }(batchPollInterval)
+	batchPollInterval = 0
+	progress := findMsg[batchProgressMsg](t, m3.pollBatchCmd("b-1"))
+	res, _ = m3.Update(progress)
+	m4 := res.(model)
+	if m4.footerStatusKind != footerStatusLoading || !strings.Contains(m4.footerError, "1/4 jobs (1 failed)") {
+		t.Errorf("expected footer progress, got %q", m4.footerError)
+	}
+
+	done := findMsg[batchProgressMsg](t, m4.pollBatchCmd("b-1"))
+	res, _ = m4.Update(done)
+	m5 := res.(model)
+	if m5.trackedBatchID != "" {
+		t.Error("expected tracking to stop once the batch is gone")
+	}
+	if m5.footerStatusKind != footerStatusSuccess || !strings.Contains(m5.footerError, "completed") {
+		t.Errorf("expected completion footer, got %q", m5.footerError)
+	}
+}
+
+func TestBatchProgressStopsWhenOnlyFailedJobsRemain(t *testing.T) {
+	m := tableTestModel(t, "http://localhost:0", "job", []string{"id"}, bulkTestRows)
+	m.trackedBatchID = "b-1"
+	m.trackedBatchLabel = "Retry All Matching"
+	m.updateBatchProgress(batchProgressMsg{batchID: "b-1", found: true, total: 4, completed: 2, remaining: 2, failed: 2})
+	if m.trackedBatchID != "" {
+		t.Error("expected tracking to stop when all remaining jobs failed")
+	}
+	if m.footerStatusKind != footerStatusError || !strings.Contains(m.footerError, "2 jobs failed") {
+		t.Errorf("expected failure summary, got %q", m.footerError)
+	}
+}
+
+// findMsg runs cmd (following tea.Batch) and returns the first message of type T.
+func findMsg[T any](t *testing.T, cmd tea.Cmd) T {
+	t.Helper()
+	var zero T
+	if cmd == nil {
+		t.Fatalf("expected a command producing %T", zero)
+	}
+	switch v := cmd().(type) {
+	case T:
+		return v
+	case tea.BatchMsg:
+		for _, c := range v {
+			if c == nil {
+				continue
+			}
+			if out, ok := c().(T); ok {
+				return out
+			}
+		}
+	}
+	t.Fatalf("no %T produced", zero)
+	return zero
+}
@@ -0,0 +1,259 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/table"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/kthoms/o6n/internal/config"
+)
+
+// markIndicator prefixes the first cell of marked rows when the table is rendered.
+const markIndicator = "● "
+
+// rowMarkKey returns the key used to mark the table row at idx: its "id" value.
+func (m *model) rowMarkKey(idx int) string {
+	return m.resolveActionIDAt(config.ActionDef{}, idx)
+}
+
+// toggleMark flips the mark of the row under the cursor.
+func (m *model) toggleMark() {
+	key := m.rowMarkKey(m.table.Cursor())
+	if key == "" {
+		return
+	}
+	if m.markedRows == nil {
+		m.markedRows = make(map[string]bool)
+	}
+	if m.markedRows[key] {
+		delete(m.markedRows, key)
+	} else {
+		m.markedRows[key] = true
+	}
+}
+
+// toggleMarkAll marks every visible (filtered) row. If all of them are marked
+// already, all marks are cleared instead.
+func (m *model) toggleMarkAll() {
+	rows := m.table.Rows()
+	keys := make([]string, 0, len(rows))
+	allMarked := true
+	for i := range rows {
+		key := m.rowMarkKey(i)
+		if key == "" {
+			continue
+		}
+		keys = append(keys, key)
+		if !m.markedRows[key] {
+			allMarked = false
+		}
+	}
+	if allMarked {
+		m.markedRows = nil
+		return
+	}
+	if m.markedRows == nil {
+		m.markedRows = make(map[string]bool)
+	}
+	for _, key := range keys {
+		m.markedRows[key] = true
+	}
+}
+
+// markedIndexes returns the indexes of the visible rows that are marked.
+func (m *model) markedIndexes() []int {
+	if len(m.markedRows) == 0 {
+		return nil
+	}
+	var idxs []int
+	for i := range m.table.Rows() {
+		if m.markedRows[m.rowMarkKey(i)] {
+			idxs = append(idxs, i)
+		}
+	}
+	return idxs
+}
+
+// markedActionIDs resolves the action ID (honouring IDColumn) of every marked visible row.
+func (m *model) markedActionIDs(action config.ActionDef) []string {
+	var ids []string
+	for _, i := range m.markedIndexes() {
+		if id := m.resolveActionIDAt(action, i); id != "" {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// refuseWhileBulkRunning reports in the footer that a bulk action is still in
+// flight. Only one runs at a time: a second would mix its progress and
+// results into the first.
+func (m *model) refuseWhileBulkRunning() tea.Cmd {
+	msg, kind, cmd := setFooterStatus(footerStatusError, m.bulkLabel+" is still running — wait for it to finish", 4*time.Second)
+	m.footerError, m.footerStatusKind = msg, kind
+	return cmd
+}
+
+// confirmBulkAction opens the aggregated confirmation for running action on ids,
+// unless a bulk action is still running.
+func (m *model) confirmBulkAction(action config.ActionDef, ids []string) tea.Cmd {
+	if m.bulkRunning {
+		return m.refuseWhileBulkRunning()
+	}
+	act := action
+	m.pendingAction = &act
+	m.pendingBulkIDs = ids
+	m.pendingActionID = ""
+	m.pendingActionPath = ""
+	m.activeModal = ModalConfirmDelete
+	m.confirmFocusedBtn = 1 // default to Cancel (safe)
+	return nil
+}
+
+// startBulkAction resets the bulk progress state and starts executing action on
+// ids, unless a bulk action is still running.
+func (m *model) startBulkAction(action config.ActionDef, ids []string) tea.Cmd {
+	if m.bulkRunning {
+		return m.refuseWhileBulkRunning()
+	}
+	m.bulkRunning = true
+	m.bulkLabel = action.Label
+	m.bulkTotal = len(ids)
+	m.bulkResults = nil
+	m.bulkResultScroll = 0
+	m.footerError, m.footerStatusKind, _ = setFooterStatus(footerStatusLoading, m.bulkProgressText(), 0)
+	return tea.Batch(m.executeBulkActionCmd(action, ids), flashOnCmd())
+}
+
+// bulkFailures returns the number of failed rows of the running or last bulk action.
+func (m *model) bulkFailures() int {
+	n := 0
+	for _, r := range m.bulkResults {
+		if r.err != nil {
+			n++
+		}
+	}
+	return n
+}
+
+// bulkProgressText renders the footer progress of a running bulk action,
+// e.g. "Retry [████░░░░░░] 16/40 (2 failed)".
+func (m *model) bulkProgressText() string {
+	const barWidth = 10
+	done := len(m.bulkResults)
+	filled := 0
+	if m.bulkTotal > 0 {
+		filled = done * barWidth / m.bulkTotal
+	}
+	bar := strings.Repeat("█", filled) + strings.Repeat("░", barWidth-filled)
+	text := fmt.Sprintf("%s [%s] %d/%d", m.bulkLabel, bar, done, m.bulkTotal)
+	if failed := m.bulkFailures(); failed > 0 {
+		text += fmt.Sprintf(" (%d failed)", failed)
+	}
+	return text
+}
+
+// finishBulkAction records the end of a bulk action: clears marks, shows the footer
+// summary and opens ModalBulkResult with the per-row outcomes.
+func (m *model) finishBulkAction() tea.Cmd {
+	m.bulkRunning = false
+	m.markedRows = nil
+	failed := m.bulkFailures()
+	ok := len(m.bulkResults) - failed
+	var statusCmd tea.Cmd
+	if failed > 0 {
+		m.footerError, m.footerStatusKind, statusCmd = setFooterStatus(footerStatusError,
+			fmt.Sprintf("%s: %d succeeded, %d failed", m.bulkLabel, ok, failed), 5*time.Second)
+	} else {
+		m.footerError, m.footerStatusKind, statusCmd = setFooterStatus(footerStatusSuccess,
+			fmt.Sprintf("%s: %d succeeded", m.bulkLabel, ok), 3*time.Second)
+	}
+	m.activeModal = ModalBulkResult
+	m.bulkResultScroll = 0
+	m.isLoading = true
+	m.apiCallStarted = time.Now()
+	fetchCmd := m.fetchForRoot(m.currentRoot)
+	if fetchCmd == nil {
+		fetchCmd = m.fetchDefinitionsCmd()
+	}
+	return tea.Batch(statusCmd, fetchCmd, spinnerTickCmd())
+}
+
+// renderTableWithMarks renders the main table with markIndicator on marked rows.
+// Marks are a pure view decoration: the table rows themselves are never modified.
+func (m *model) renderTableWithMarks() string {
+	if len(m.markedRows) == 0 {
+		return m.table.View()
+	}
+	rows := m.table.Rows()
+	decorated := make([]table.Row, len(rows))
+	copy(decorated, rows)
+	for _, i := range m.markedIndexes() {
+		if len(rows[i]) == 0 {
+			continue
+		}
+		r := append(table.Row{}, rows[i]...)
+		r[0] = m.styles.Accent.Render(markIndicator) + r[0]
+		decorated[i] = r
+	}
+	t := m.table
+	t.SetRows(decorated)
+	return t.View()
+}
+
+// renderBulkConfirmBody renders the aggregated confirmation for a pending bulk action.
+func (m *model) renderBulkConfirmBody() string {
+	const maxListed = 8
+	act := m.pendingAction
+	var b strings.Builder
+	fmt.Fprintf(&b, "⚠️  %s — %d items\n\n", strings.ToUpper(act.Label), len(m.pendingBulkIDs))
+	fmt.Fprintf(&b, "You are about to run %s %s on %d marked %s rows:\n\n", act.Method, act.Path, len(m.pendingBulkIDs), m.currentRoot)
+	for i, id := range m.pendingBulkIDs {
+		if i == maxListed {
+			fmt.Fprintf(&b, "  … and %d more\n", len(m.pendingBulkIDs)-maxListed)
+			break
+		}
+		fmt.Fprintf(&b, "  %s\n", id)
+	}
+	return b.String()
+}
+
+// modalBulkResultBody renders the per-row summary of the last bulk action.
+func (m *model) modalBulkResultBody() string {
+	viewHeight := m.lastHeight - 10
+	if viewHeight < 3 {
+		viewHeight = 3
+	}
+	failed := m.bulkFailures()
+	lines := make([]string, 0, len(m.bulkResults))
+	// Failures first — they are what needs attention.
+	for _, r := range m.bulkResults {
+		if r.err != nil {
+			lines = append(lines, m.styles.ErrorFooter.Render("✗ "+r.id)+"  "+friendlyError(m.currentEnv, r.err))
+		}
+	}
+	for _, r := range m.bulkResults {
+		if r.err == nil {
+			lines = append(lines, m.styles.SuccessFooter.Render("✓ "+r.id))
+		}
+	}
+	maxScroll := len(lines) - viewHeight
+	if maxScroll < 0 {
+		maxScroll = 0
+	}
+	scroll := m.bulkResultScroll
+	if scroll > maxScroll {
+		scroll = maxScroll
+	}
+	if scroll < 0 {
+		scroll = 0
+	}
+	end := scroll + viewHeight
+	if end > len(lines) {
+		end = len(lines)
+	}
+	title := fmt.Sprintf("%s — %d succeeded, %d failed", m.bulkLabel, len(m.bulkResults)-failed, failed)
+	return title + "\n\n" + strings.Join(lines[scroll:end], "\n")
+}
@@ -0,0 +1,510 @@
+package app
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/kthoms/o6n/internal/client"
+	"github.com/kthoms/o6n/internal/config"
+	"gopkg.in/yaml.v3"
+)
+
+// cliPageSize is the page size used when a headless query pages through a collection.
+const cliPageSize = 100
+
+// cliUsage is printed for `o6n help` and on unknown subcommands.
+const cliUsage = `Usage:
+  o6n [flags]                      start the terminal UI
+  o6n get <table> [flags]          query a configured table and print the result
+  o6n exec <table> <action> [flags] run a configured action (key or label) for --id rows
+  o6n deploy <path>... [flags]     deploy BPMN/DMN/CMMN/form files or directories
+  o6n creds encrypt|list           manage the encrypted credentials file
+  o6n audit [flags]                list changes recorded in the audit log
+  o6n views [list|export|import]   list, share or import saved views
+
+Run 'o6n <command> -h' for command flags.
+`
+
+// paramFlags collects repeatable --param key=value flags.
+type paramFlags map[string]string
+
+func (p paramFlags) String() string {
+	keys := make([]string, 0, len(p))
+	for k := range p {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	parts := make([]string, 0, len(keys))
+	for _, k := range keys {
+		parts = append(parts, k+"="+p[k])
+	}
+	return strings.Join(parts, ",")
+}
+
+func (p paramFlags) Set(v string) error {
+	k, val, ok := strings.Cut(v, "=")
+	if !ok || strings.TrimSpace(k) == "" {
+		return fmt.Errorf("expected key=value, got %q", v)
+	}
+	p[strings.TrimSpace(k)] = val
+	return nil
+}
+
+// idFlags collects repeatable --id flags.
+type idFlags []string
+
+func (f *idFlags) String() string { return strings.Join(*f, ",") }
+
+func (f *idFlags) Set(v string) error {
+	if strings.TrimSpace(v) == "" {
+		return errors.New("id must not be empty")
+	}
+	*f = append(*f, v)
+	return nil
+}
+
+// runCLI dispatches a headless subcommand. args are the positional arguments left
+// after global flag parsing (args[0] is the subcommand). Returns the process exit code.
+func runCLI(args []string, stdout, stderr io.Writer) int {
+	switch args[0] {
+	case "get":
+		return runGet(args[1:], stdout, stderr)
+	case "exec":
+		return runExec(args[1:], stdout, stderr)
+	case "deploy":
+		return runDeploy(args[1:], stdout, stderr)
+	case "creds":
+		return runCreds(args[1:], stdout, stderr)
+	case "audit":
+		return runAudit(args[1:], stdout, stderr)
+	case "views":
+		return runViews(args[1:], stdout, stderr)
+	case "help":
+		fmt.Fprint(stdout, cliUsage)
+		return 0
+	default:
+		fmt.Fprintf(stderr, "unknown command %q\n\n%s", args[0], cliUsage)
+		return 2
+	}
+}
+
+// parseInterspersed parses flags that may appear before or after positional arguments
+// (e.g. `get process-instance --env prod`). It returns the positional arguments in order.
+func parseInterspersed(fs *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, err
+		}
+		if fs.NArg() == 0 {
+			return positional, nil
+		}
+		positional = append(positional, fs.Arg(0))
+		args = fs.Args()[1:]
+	}
+}
+
+// loadCLIModel loads the env and app config files and returns a model bound to envName.
+// An empty envName selects the same environment the TUI would start with.
+func loadCLIModel(envName string) (model, error) {
+	envCfg, err := config.LoadEnvConfig(envConfigPath)
+	if err != nil {
+		return model{}, err
+	}
+	if err := envCfg.ResolveSecrets(credentialsPassphrase); err != nil {
+		return model{}, err
+	}
+	client.AuditFile = envCfg.AuditPath()
+	appCfg, err := config.LoadAppConfig(appConfigPath)
+	if err != nil {
+		return model{}, err
+	}
+	if len(envCfg.Environments) == 0 {
+		return model{}, fmt.Errorf("no environments configured in %s", envConfigPath)
+	}
+	m := newModelEnvApp(envCfg, appCfg, "")
+	if envName != "" {
+		if _, ok := m.config.Environments[envName]; !ok {
+			return model{}, fmt.Errorf("unknown environment %q (available: %s)", envName, strings.Join(m.envNames, ", "))
+		}
+		m.currentEnv = envName
+	}
+	return m, nil
+}
+
+// runGet implements `o6n get <table>`.
+func runGet(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("get", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	envName := fs.String("env", "", "environment from o6n-env.yaml (default: active environment)")
+	output := fs.String("o", "table", "output format: json|yaml|table|csv")
+	fs.StringVar(output, "output", "table", "output format: json|yaml|table|csv")
+	offset := fs.Int("offset", 0, "index of the first result (firstResult)")
+	limit := fs.Int("limit", 0, "maximum number of results (0 = all)")
+	params := paramFlags{}
+	fs.Var(params, "param", "query or path parameter key=value (repeatable)")
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: o6n get <table> [--env name] [--param key=value]... [-o json|yaml|table|csv]")
+		fs.PrintDefaults()
+	}
+
+	positional, err := parseInterspersed(fs, args)
+	if err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		return 2
+	}
+	if len(positional) != 1 {
+		fs.Usage()
+		return 2
+	}
+	format := strings.ToLower(*output)
+	switch format {
+	case "json", "yaml", "table", "csv":
+	default:
+		fmt.Fprintf(stderr, "unsupported output format %q\n", *output)
+		return 2
+	}
+
+	m, err := loadCLIModel(*envName)
+	if err != nil {
+		fmt.Fprintf(stderr, "Error: %v\n", err)
+		return 1
+	}
+	root := positional[0]
+	def := m.findTableDef(root)
+	if def == nil {
+		fmt.Fprintf(stderr, "Error: unknown table %q\n", root)
+		return 1
+	}
+
+	env := m.config.Environments[m.currentEnv]
+	apiPath, _ := m.tablePaths(def.Name)
+	items, err := fetchAllRows(env, apiPath, params, *offset, *limit)
+	if err != nil {
+		fmt.Fprintf(stderr, "Error: %v\n", err)
+		return 1
+	}
+	if err := writeRows(stdout, format, def, items); err != nil {
+		fmt.Fprintf(stderr, "Error: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// findCLIAction returns the HTTP action of def whose key or label (case-insensitive) matches name.
+func findCLIAction(def *config.TableDef, name string) (config.ActionDef, error) {
+	for _, act := range def.Actions {
+		if act.Key == name || strings.EqualFold(act.Label, name) {
+			if act.Type == "navigate" {
+				return config.ActionDef{}, fmt.Errorf("action %q of table %s is a navigate action and cannot be executed", act.Label, def.Name)
+			}
+			return act, nil
+		}
+	}
+	names := make([]string, 0, len(def.Actions))
+	for _, act := range def.Actions {
+		if act.Type != "navigate" {
+			names = append(names, fmt.Sprintf("%s (%s)", act.Key, act.Label))
+		}
+	}
+	if len(names) == 0 {
+		return config.ActionDef{}, fmt.Errorf("table %s has no actions", def.Name)
+	}
+	return config.ActionDef{}, fmt.Errorf("unknown action %q for table %s (available: %s)", name, def.Name, strings.Join(names, ", "))
+}
+
+// runExec implements `o6n exec <table> <action>`.
+func runExec(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("exec", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	envName := fs.String("env", "", "environment from o6n-env.yaml (default: active environment)")
+	yes := fs.Bool("yes", false, "confirm actions configured with confirm: true")
+	fs.BoolVar(yes, "y", false, "shorthand for --yes")
+	var ids idFlags
+	fs.Var(&ids, "id", "row ID the action is applied to (repeatable)")
+	params := paramFlags{}
+	fs.Var(params, "param", "filter key=value for batch actions (repeatable)")
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: o6n exec <table> <action-key|label> --id <id> [--id <id>]... [--env name] [--yes]")
+		fmt.Fprintln(stderr, "       o6n exec <table> <batch-action> [--param key=value]... [--env name] --yes")
+		fs.PrintDefaults()
+	}
+
+	positional, err := parseInterspersed(fs, args)
+	if err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		return 2
+	}
+	if len(positional) != 2 {
+		fs.Usage()
+		return 2
+	}
+
+	m, err := loadCLIModel(*envName)
+	if err != nil {
+		fmt.Fprintf(stderr, "Error: %v\n", err)
+		return 1
+	}
+	def := m.findTableDef(positional[0])
+	if def == nil {
+		fmt.Fprintf(stderr, "Error: unknown table %q\n", positional[0])
+		return 1
+	}
+	act, err := findCLIAction(def, positional[1])
+	if err != nil {
+		fmt.Fprintf(stderr, "Error: %v\n", err)
+		return 1
+	}
+	env := m.config.Environments[m.currentEnv]
+	if env.ReadOnly() {
+		fmt.Fprintf(stderr, "Error: environment %s is read-only\n", m.currentEnv)
+		return 1
+	}
+	if env.Protected() && !*yes {
+		fmt.Fprintf(stderr, "Error: environment %s is protected; re-run with --yes\n", m.currentEnv)
+		return 1
+	}
+	if act.Type == "batch" {
+		// Batch actions affect every matching row and are always confirmed
+		if !*yes {
+			fmt.Fprintf(stderr, "Error: batch action %q applies to every matching row; re-run with --yes\n", act.Label)
+			return 1
+		}
+		return execCLIBatch(m.currentEnv, env, act, params, stdout, stderr)
+	}
+	if len(ids) == 0 {
+		fs.Usage()
+		return 2
+	}
+	if act.Confirm && !*yes {
+		fmt.Fprintf(stderr, "Error: %q requires confirmation; re-run with --yes\n", act.Label)
+		return 1
+	}
+
+	return execCLIAction(m.currentEnv, env, act, ids, stdout, stderr)
+}
+
+// execCLIAction runs act once per ID in the environment envName, reporting each
+// result on stdout (success) or stderr (failure, including the HTTP error body).
+// Returns 1 if any execution failed.
+func execCLIAction(envName string, env config.Environment, act config.ActionDef, ids []string, stdout, stderr io.Writer) int {
+	c := client.NewClient(env, false).WithContext(client.WithAudit(context.Background(), envName, act.Label))
+	body := resolveActionBody(act, env)
+	failed := 0
+	for _, id := range ids {
+		resolvedPath := resolveActionPath(act, id)
+		if err := c.ExecuteAction(act.Method, resolvedPath, body); err != nil {
+			failed++
+			fmt.Fprintf(stderr, "✗ %s %s: %v\n", act.Label, id, err)
+			continue
+		}
+		fmt.Fprintf(stdout, "✓ %s %s\n", act.Label, id)
+	}
+	if failed > 0 {
+		return 1
+	}
+	return 0
+}
+
+// execCLIBatch submits the server-side batch of act for the filter params and prints its ID.
+func execCLIBatch(envName string, env config.Environment, act config.ActionDef, params map[string]string, stdout, stderr io.Writer) int {
+	c := client.NewClient(env, false).WithContext(client.WithAudit(context.Background(), envName, act.Label))
+	id, err := c.SubmitBatch(act.Batch, params)
+	if err != nil {
+		fmt.Fprintf(stderr, "✗ %s: %v\n", act.Label, err)
+		return 1
+	}
+	fmt.Fprintf(stdout, "✓ %s: batch %s submitted\n", act.Label, id)
+	return 0
+}
+
+// runDeploy implements `o6n deploy <path>...`.
+func runDeploy(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("deploy", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	envName := fs.String("env", "", "environment from o6n-env.yaml (default: active environment)")
+	name := fs.String("name", "", "deployment name (default: first file or directory name)")
+	tenant := fs.String("tenant", "", "tenant id of the deployment")
+	duplicates := fs.Bool("duplicate-filtering", true, "skip the deployment if no resource changed (enable-duplicate-filtering); =false to always deploy")
+	changedOnly := fs.Bool("changed-only", false, "deploy only changed resources (deploy-changed-only)")
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: o6n deploy <file|dir>... [--env name] [--name name] [--tenant id] [--duplicate-filtering=false] [--changed-only]")
+		fs.PrintDefaults()
+	}
+
+	paths, err := parseInterspersed(fs, args)
+	if err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		return 2
+	}
+	if len(paths) == 0 {
+		fs.Usage()
+		return 2
+	}
+
+	m, err := loadCLIModel(*envName)
+	if err != nil {
+		fmt.Fprintf(stderr, "Error: %v\n", err)
+		return 1
+	}
+	files, err := client.CollectDeploymentFiles(paths)
+	if err != nil {
+		fmt.Fprintf(stderr, "Error: %v\n", err)
+		return 1
+	}
+	opts := client.DeployOptions{
+		Name:                     *name,
+		TenantID:                 *tenant,
+		Source:                   deploymentSource,
+		EnableDuplicateFiltering: *duplicates || *changedOnly,
+		DeployChangedOnly:        *changedOnly,
+	}
+	if opts.Name == "" {
+		opts.Name = defaultDeploymentName(paths)
+	}
+	c := client.NewClient(m.config.Environments[m.currentEnv], false).
+		WithContext(client.WithAudit(context.Background(), m.currentEnv, "Deploy "+opts.Name))
+	dep, err := c.CreateDeployment(files, opts)
+	if err != nil {
+		fmt.Fprintf(stderr, "✗ deploy %s: %v\n", opts.Name, err)
+		return 1
+	}
+	fmt.Fprintf(stdout, "✓ Deployed %s (%s, %d files)\n", client.GetStringValue(dep.Name), client.GetStringValue(dep.Id), len(files))
+	for _, line := range deployedDefinitionLines(dep) {
+		fmt.Fprintf(stdout, "  %s\n", line)
+	}
+	return 0
+}
+
+// fetchAllRows pages through apiPath starting at offset until limit rows were read
+// (limit <= 0 reads everything) or the server returns a short page.
+func fetchAllRows(env config.Environment, apiPath string, params map[string]string, offset, limit int) ([]map[string]interface{}, error) {
+	items := []map[string]interface{}{}
+	for {
+		pageSize := cliPageSize
+		if limit > 0 && limit-len(items) < pageSize {
+			pageSize = limit - len(items)
+		}
+		urlStr := buildGenericURL(env.URL, apiPath, params, offset, pageSize)
+		page, err := getJSONArray(env, urlStr)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, page...)
+		offset += len(page)
+		if len(page) < pageSize || (limit > 0 && len(items) >= limit) {
+			return items, nil
+		}
+	}
+}
+
+// getJSONArray performs an authenticated GET and decodes a JSON array of objects.
+func getJSONArray(env config.Environment, urlStr string) ([]map[string]interface{}, error) {
+	var items []map[string]interface{}
+	if err := client.GetJSON(context.Background(), env, urlStr, &items); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// outputColumns returns the column names used for table and csv output: the visible
+// TableDef columns in configured order, or the sorted keys of the first row as fallback.
+func outputColumns(def *config.TableDef, items []map[string]interface{}) []string {
+	var cols []string
+	if def != nil {
+		for _, c := range def.Columns {
+			if c.IsVisible() {
+				cols = append(cols, c.Name)
+			}
+		}
+	}
+	if len(cols) == 0 && len(items) > 0 {
+		for k := range items[0] {
+			cols = append(cols, k)
+		}
+		sort.Strings(cols)
+	}
+	return cols
+}
+
+// cellString formats a raw JSON value for table and csv output.
+func cellString(v interface{}) string {
+	switch t := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return t
+	case map[string]interface{}, []interface{}:
+		b, err := json.Marshal(t)
+		if err != nil {
+			return fmt.Sprintf("%v", t)
+		}
+		return string(b)
+	default:
+		return fmt.Sprintf("%v", t)
+	}
+}
+
+// writeRows renders items in the requested output format.
+func writeRows(w io.Writer, format string, def *config.TableDef, items []map[string]interface{}) error {
+	switch format {
+	case "json":
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(items)
+	case "yaml":
+		enc := yaml.NewEncoder(w)
+		enc.SetIndent(2)
+		if err := enc.Encode(items); err != nil {
+			return err
+		}
+		return enc.Close()
+	case "csv":
+		cols := outputColumns(def, items)
+		cw := csv.NewWriter(w)
+		if err := cw.Write(cols); err != nil {
+			return err
+		}
+		for _, it := range items {
+			rec := make([]string, len(cols))
+			for i, c := range cols {
+				rec[i] = cellString(it[c])
+			}
+			if err := cw.Write(rec); err != nil {
+				return err
+			}
+		}
+		cw.Flush()
+		return cw.Error()
+	default:
+		cols := outputColumns(def, items)
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		header := make([]string, len(cols))
+		for i, c := range cols {
+			header[i] = strings.ToUpper(c)
+		}
+		fmt.Fprintln(tw, strings.Join(header, "\t"))
+		for _, it := range items {
+			rec := make([]string, len(cols))
+			for i, c := range cols {
+				rec[i] = strings.ReplaceAll(cellString(it[c]), "\t", " ")
+			}
+			fmt.Fprintln(tw, strings.Join(rec, "\t"))
+		}
+		return tw.Flush()
+	}
+}
@@ -0,0 +1,293 @@
+package app
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/kthoms/o6n/internal/config"
+)
+
+func TestParseInterspersedFlagsAfterPositional(t *testing.T) {
+	fs := flag.NewFlagSet("get", flag.ContinueOnError)
+	env := fs.String("env", "", "")
+	params := paramFlags{}
+	fs.Var(params, "param", "")
+
+	pos, err := parseInterspersed(fs, []string{"process-instance", "--env", "staging", "--param", "processDefinitionKey=invoice", "--param", "active=true"})
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if len(pos) != 1 || pos[0] != "process-instance" {
+		t.Fatalf("expected positional [process-instance], got %v", pos)
+	}
+	if *env != "staging" {
+		t.Errorf("expected env staging, got %q", *env)
+	}
+	if params["processDefinitionKey"] != "invoice" || params["active"] != "true" {
+		t.Errorf("unexpected params: %v", params)
+	}
+}
+
+func TestParamFlagsRejectsMissingValue(t *testing.T) {
+	p := paramFlags{}
+	if err := p.Set("noequals"); err == nil {
+		t.Error("expected error for param without '='")
+	}
+}
+
+func TestBuildGenericURLSubstitutesPathParamsAndPaging(t *testing.T) {
+	got := buildGenericURL("http://h/engine-rest/", "/process-instance/{parentId}/variables", map[string]string{"processInstanceId": "pi-1"}, 20, 10)
+	want := "http://h/engine-rest/process-instance/pi-1/variables?firstResult=20&maxResults=10"
+	if got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	got = buildGenericURL("http://h", "/job/count", map[string]string{"withException": "true"}, 0, 0)
+	if got != "http://h/job/count?withException=true" {
+		t.Errorf("unexpected count URL %q", got)
+	}
+	got = buildGenericURL("http://h", "/process-instance", map[string]string{"businessKeyLike": "%a&b%", "tenantIdIn": "t 1"}, 0, 0)
+	if got != "http://h/process-instance?businessKeyLike=%25a%26b%25&tenantIdIn=t+1" {
+		t.Errorf("expected escaped params, got %q", got)
+	}
+}
+
+func TestFetchAllRowsPagesUntilShortPage(t *testing.T) {
+	const total = 230
+	var calls int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if r.URL.Path != "/process-instance" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		if r.URL.Query().Get("processDefinitionKey") != "invoice" {
+			t.Errorf("missing query param: %v", r.URL.Query())
+		}
+		if u, p, ok := r.BasicAuth(); !ok || u != "demo" || p != "secret" {
+			t.Errorf("expected basic auth demo/secret")
+		}
+		first, _ := strconv.Atoi(r.URL.Query().Get("firstResult"))
+		max, _ := strconv.Atoi(r.URL.Query().Get("maxResults"))
+		out := []map[string]interface{}{}
+		for i := first; i < first+max && i < total; i++ {
+			out = append(out, map[string]interface{}{"id": strconv.Itoa(i)})
+		}
+		_ = json.NewEncoder(w).Encode(out)
+	}))
+	defer srv.Close()
+
+	env := config.Environment{URL: srv.URL, Username: "demo", Password: "secret"}
+	items, err := fetchAllRows(env, "/process-instance", map[string]string{"processDefinitionKey": "invoice"}, 0, 0)
+	if err != nil {
+		t.Fatalf("fetchAllRows: %v", err)
+	}
+	if len(items) != total {
+		t.Fatalf("expected %d items, got %d", total, len(items))
+	}
+	if calls != 3 {
+		t.Errorf("expected 3 page requests, got %d", calls)
+	}
+
+	items, err = fetchAllRows(env, "/process-instance", map[string]string{"processDefinitionKey": "invoice"}, 5, 7)
+	if err != nil {
+		t.Fatalf("fetchAllRows with limit: %v", err)
+	}
+	if len(items) != 7 || items[0]["id"] != "5" {
+		t.Fatalf("expected 7 items starting at 5, got %d (%v)", len(items), items)
+	}
+}
+
+func TestFetchAllRowsReturnsHTTPErrorBody(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = io.WriteString(w, `{"message":"bad sortBy"}`)
+	}))
+	defer srv.Close()
+
+	_, err := fetchAllRows(config.Environment{URL: srv.URL}, "/job", nil, 0, 0)
+	if err == nil || !strings.Contains(err.Error(), "bad sortBy") {
+		t.Fatalf("expected error containing body, got %v", err)
+	}
+}
+
+func TestWriteRowsTableRespectsColumnOrderAndVisibility(t *testing.T) {
+	hidden := false
+	def := &config.TableDef{
+		Name: "process-instance",
+		Columns: []config.ColumnDef{
+			{Name: "businessKey"},
+			{Name: "id"},
+			{Name: "tenantId", Visible: &hidden},
+			{Name: "suspended"},
+		},
+	}
+	items := []map[string]interface{}{
+		{"id": "pi-1", "businessKey": "BK-1", "tenantId": "t1", "suspended": false},
+	}
+
+	var buf bytes.Buffer
+	if err := writeRows(&buf, "table", def, items); err != nil {
+		t.Fatalf("writeRows: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected header + 1 row, got %q", buf.String())
+	}
+	header := strings.Fields(lines[0])
+	if strings.Join(header, ",") != "BUSINESSKEY,ID,SUSPENDED" {
+		t.Errorf("unexpected header %v", header)
+	}
+	if strings.Join(strings.Fields(lines[1]), ",") != "BK-1,pi-1,false" {
+		t.Errorf("unexpected row %q", lines[1])
+	}
+}
+
+func TestWriteRowsCSVAndJSON(t *testing.T) {
+	def := &config.TableDef{Columns: []config.ColumnDef{{Name: "id"}, {Name: "name"}}}
+	items := []map[string]interface{}{{"id": "1", "name": "a,b", "extra": 1.0}}
+
+	var buf bytes.Buffer
+	if err := writeRows(&buf, "csv", def, items); err != nil {
+		t.Fatalf("csv: %v", err)
+	}
+	if buf.String() != "id,name\n1,\"a,b\"\n" {
+		t.Errorf("unexpected csv %q", buf.String())
+	}
+
+	buf.Reset()
+	if err := writeRows(&buf, "json", def, items); err != nil {
+		t.Fatalf("json: %v", err)
+	}
+	var decoded []map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+		t.Fatalf("invalid json output: %v", err)
+	}
+	if decoded[0]["extra"] != 1.0 {
+		t.Errorf("json output should contain the full raw object, got %v", decoded[0])
+	}
+}
+
+func TestRunCLIUnknownCommand(t *testing.T) {
+	var out, errOut bytes.Buffer
+	if code := runCLI([]string{"frobnicate"}, &out, &errOut); code != 2 {
+		t.Errorf("expected exit code 2, got %d", code)
+	}
+	if !strings.Contains(errOut.String(), "unknown command") {
+		t.Errorf("expected usage error, got %q", errOut.String())
+	}
+}
+
+func TestRunCredsEncryptAndList(t *testing.T) {
+	t.Setenv(passphraseEnvVar, "correct horse")
+	dir := t.TempDir()
+	plain := filepath.Join(dir, "creds.yaml")
+	sealed := filepath.Join(dir, "creds.enc")
+	if err := os.WriteFile(plain, []byte("prod:\n  username: admin\n  password: s3cret\nshared:\n  client_secret: cs\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	var out, errOut bytes.Buffer
+	if code := runCLI([]string{"creds", "encrypt", plain, "--out", sealed}, &out, &errOut); code != 0 {
+		t.Fatalf("encrypt failed (%d): %s", code, errOut.String())
+	}
+	data, err := os.ReadFile(sealed)
+	if err != nil || bytes.Contains(data, []byte("s3cret")) {
+		t.Fatalf("expected an encrypted file, got %q %v", data, err)
+	}
+	out.Reset()
+	if code := runCLI([]string{"creds", "list", sealed}, &out, &errOut); code != 0 {
+		t.Fatalf("list failed (%d): %s", code, errOut.String())
+	}
+	if out.String() != "prod: username, password\nshared: client_secret\n" {
+		t.Errorf("unexpected listing %q", out.String())
+	}
+}
+
+func TestFindCLIActionByKeyOrLabel(t *testing.T) {
+	def := &testConfigWithActions().Tables[0]
+
+	act, err := findCLIAction(def, "s")
+	if err != nil || act.Label != "Suspend Instance" {
+		t.Fatalf("expected Suspend Instance by key, got %v (%v)", act.Label, err)
+	}
+	act, err = findCLIAction(def, "delete instance")
+	if err != nil || act.Key != "ctrl+d" {
+		t.Fatalf("expected Delete Instance by label, got %v (%v)", act.Key, err)
+	}
+	if _, err := findCLIAction(def, "nope"); err == nil || !strings.Contains(err.Error(), "Suspend Instance") {
+		t.Errorf("expected error listing available actions, got %v", err)
+	}
+}
+
+func TestFindCLIActionRejectsNavigate(t *testing.T) {
+	def := &config.TableDef{Name: "job", Actions: []config.ActionDef{{Key: "h", Label: "History", Type: "navigate", Target: "history-job-log"}}}
+	if _, err := findCLIAction(def, "h"); err == nil {
+		t.Error("expected navigate action to be rejected")
+	}
+}
+
+func TestExecCLIActionResolvesPathAndBody(t *testing.T) {
+	var got []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		got = append(got, r.Method+" "+r.URL.Path+" "+string(b))
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	env := config.Environment{URL: srv.URL, Username: "demo", Password: "demo"}
+	act := config.ActionDef{Key: "c", Label: "Claim Task", Method: "POST", Path: "/task/{id}/claim", Body: `{"userId": "{currentUser}"}`}
+	var out, errOut bytes.Buffer
+	if code := execCLIAction("local", env, act, []string{"t1", "t2"}, &out, &errOut); code != 0 {
+		t.Fatalf("expected exit 0, got %d (stderr %q)", code, errOut.String())
+	}
+	want := []string{
+		`POST /task/t1/claim {"userId": "demo"}`,
+		`POST /task/t2/claim {"userId": "demo"}`,
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("unexpected requests:\n%s", strings.Join(got, "\n"))
+	}
+	if !strings.Contains(out.String(), "✓ Claim Task t2") {
+		t.Errorf("expected success line, got %q", out.String())
+	}
+}
+
+func TestExecCLIActionFailureReturnsNonZeroWithBody(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.Contains(r.URL.Path, "bad") {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = io.WriteString(w, `{"type":"NotFoundException","message":"Job bad does not exist"}`)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	act := config.ActionDef{Key: "r", Label: "Retry", Method: "PUT", Path: "/job/{id}/retries", Body: `{"retries":1}`}
+	var out, errOut bytes.Buffer
+	code := execCLIAction("local", config.Environment{URL: srv.URL}, act, []string{"ok", "bad"}, &out, &errOut)
+	if code == 0 {
+		t.Fatal("expected non-zero exit code when an action fails")
+	}
+	if !strings.Contains(errOut.String(), "Job bad does not exist") {
+		t.Errorf("expected HTTP error body on stderr, got %q", errOut.String())
+	}
+	if !strings.Contains(out.String(), "✓ Retry ok") {
+		t.Errorf("expected remaining IDs to be processed, got %q", out.String())
+	}
+}
+
+func TestRunExecRequiresActionAndID(t *testing.T) {
+	var out, errOut bytes.Buffer
+	if code := runExec([]string{"process-instance"}, &out, &errOut); code != 2 {
+		t.Errorf("expected usage exit code 2 without action and --id, got %d", code)
+	}
+}
@@ -0,0 +1,341 @@
+package app
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/kthoms/o6n/internal/apispec"
+	"github.com/kthoms/o6n/internal/config"
+)
+
+// cmdHistoryMax caps the command history kept in o6n-stat.yml.
+const cmdHistoryMax = 50
+
+// commandBuiltins are the commands of the : command line besides table names.
+// env, skin and view take a name as argument.
+var commandBuiltins = []string{"env", "skin", "view", dashboardContext, "metrics", "triage", "quit"}
+
+// builtinTakesArg reports whether the built-in command name needs an argument.
+func builtinTakesArg(name string) bool {
+	return name == "env" || name == "skin" || name == "view"
+}
+
+// resolveCommandTable returns the table a command names, either by its name or
+// by one of the aliases configured in o6n-cfg.yaml, or "".
+func (m *model) resolveCommandTable(name string) string {
+	for _, rc := range m.rootContexts {
+		if rc == name {
+			return rc
+		}
+	}
+	if m.config == nil {
+		return ""
+	}
+	for _, t := range m.config.Tables {
+		if t.Name == name {
+			return t.Name
+		}
+		for _, a := range t.Aliases {
+			if a == name {
+				return t.Name
+			}
+		}
+	}
+	return ""
+}
+
+// tableAliases returns the aliases configured for table.
+func (m *model) tableAliases(table string) []string {
+	if def := m.findTableDef(table); def != nil {
+		return def.Aliases
+	}
+	return nil
+}
+
+// commandItems returns the entries the context switcher offers for the input:
+// the tables containing it, tables and built-ins with an alias starting with it
+// and saved views; after a space, completions of the command's argument.
+func (m *model) commandItems() []string {
+	input := m.popup.input
+	if strings.Contains(input, " ") {
+		return m.commandArgItems(input)
+	}
+	var out []string
+	seen := map[string]bool{}
+	add := func(item string) {
+		if !seen[item] {
+			seen[item] = true
+			out = append(out, item)
+		}
+	}
+	for _, rc := range m.rootContexts {
+		if input == "" || strings.Contains(rc, input) {
+			add(rc)
+		}
+	}
+	if input != "" {
+		for _, rc := range m.rootContexts {
+			for _, a := range m.tableAliases(rc) {
+				if strings.HasPrefix(a, input) {
+					add(rc)
+				}
+			}
+		}
+		for _, b := range commandBuiltins {
+			if strings.HasPrefix(b, input) {
+				add(b)
+			}
+		}
+	}
+	for _, v := range m.savedViews {
+		if item := viewPrefix + v.Name; input == "" || strings.Contains(item, input) {
+			add(item)
+		}
+	}
+	return out
+}
+
+// commandArgItems completes the argument being typed after a command, as full
+// command lines: environment, skin and view names for the built-ins, query
+// parameters and their values for a table.
+func (m *model) commandArgItems(input string) []string {
+	head, rest, _ := strings.Cut(input, " ")
+	rest = strings.TrimLeft(rest, " ")
+	var names []string
+	switch head {
+	case "env":
+		for name := range m.config.Environments {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+	case "skin":
+		names = m.availableSkins
+	case "view":
+		for _, v := range m.savedViews {
+			names = append(names, v.Name)
+		}
+	default:
+		table := m.resolveCommandTable(head)
+		if table == "" {
+			return nil
+		}
+		params, _ := m.tableQueryParams(table)
+		stem := input[:len(input)-len(currentQueryToken(input))]
+		var out []string
+		for _, s := range paramSuggestions(params, rest, nil) {
+			out = append(out, stem+s.insert)
+		}
+		return out
+	}
+	var out []string
+	for _, name := range names {
+		if strings.HasPrefix(name, rest) {
+			out = append(out, head+" "+name)
+		}
+	}
+	return out
+}
+
+// tableQueryParams returns the GET query parameters of table from the OpenAPI
+// document; ok is false when the document does not describe it.
+func (m *model) tableQueryParams(table string) ([]apispec.Param, bool) {
+	apiPath, _ := m.tablePaths(table)
+	return m.apiSpec().QueryParams(apiPath)
+}
+
+// commandKeyParam returns the query parameter a bare `:table value` filters by:
+// the table's key_param from o6n-cfg.yaml, else processDefinitionKey where the
+// table supports it.
+func (m *model) commandKeyParam(table string, params []apispec.Param) string {
+	if def := m.findTableDef(table); def != nil && def.KeyParam != "" {
+		return def.KeyParam
+	}
+	if _, ok := findParam(params, "processDefinitionKey"); ok {
+		return "processDefinitionKey"
+	}
+	return ""
+}
+
+// commandFilters turns the arguments of a table command into query filters.
+// Arguments are name=value pairs or one bare value for the key parameter, and
+// are checked against the OpenAPI document.
+func (m *model) commandFilters(table string, args []string) (map[string]string, error) {
+	if len(args) == 0 {
+		return nil, nil
+	}
+	params, known := m.tableQueryParams(table)
+	filters := make(map[string]string, len(args))
+	for _, arg := range args {
+		name, value, ok := strings.Cut(arg, "=")
+		if !ok {
+			name, value = m.commandKeyParam(table, params), arg
+			if name == "" {
+				return nil, fmt.Errorf("%s has no key parameter, use name=value", table)
+			}
+		}
+		if name == "" {
+			return nil, fmt.Errorf("expected name=value, got %q", arg)
+		}
+		if _, dup := filters[name]; dup {
+			return nil, fmt.Errorf("%s given twice", name)
+		}
+		if err := validateFilter(params, known, table, name, value); err != nil {
+			return nil, err
+		}
+		filters[name] = value
+	}
+	return filters, nil
+}
+
+// runCommand executes a command line: a table name or alias with optional
+// filters, @view, or one of the built-ins env, skin, view and q. Nothing changes
+// when it returns an error.
+func (m *model) runCommand(line string) (tea.Cmd, error) {
+	tokens := splitQueryTokens(line)
+	if len(tokens) == 0 {
+		return nil, nil
+	}
+	head, args := tokens[0], tokens[1:]
+	if name, ok := strings.CutPrefix(head, viewPrefix); ok {
+		head, args = "view", append([]string{name}, args...)
+	}
+
+	switch head {
+	case "q", "q!", "quit":
+		m.quitting = true
+		return tea.Quit, nil
+	case "env", "skin", "view":
+		if len(args) != 1 {
+			return nil, fmt.Errorf("usage: %s <name>", head)
+		}
+		return m.runBuiltin(head, args[0])
+	case dashboardContext:
+		return m.openDashboard(), nil
+	case "metrics":
+		return m.openMetrics(), nil
+	case "triage":
+		return m.openTriage(), nil
+	}
+
+	table := m.resolveCommandTable(head)
+	if table == "" {
+		return nil, fmt.Errorf("unknown table or command %q", head)
+	}
+	filters, err := m.commandFilters(table, args)
+	if err != nil {
+		return nil, err
+	}
+	return m.openTable(table, filters), nil
+}
+
+// runBuiltin executes the built-in command that takes the argument name.
+func (m *model) runBuiltin(cmd, name string) (tea.Cmd, error) {
+	switch cmd {
+	case "env":
+		if _, ok := m.config.Environments[name]; !ok {
+			return nil, fmt.Errorf("unknown environment %q", name)
+		}
+		if name == m.currentEnv {
+			return nil, nil
+		}
+		return m.changeEnvironment(name), nil
+	case "skin":
+		skin, err := loadSkin(name + ".yaml")
+		if err != nil {
+			return nil, fmt.Errorf("unknown skin %q", name)
+		}
+		m.skin = skin
+		m.activeSkin = name
+		m.applyStyle()
+		return m.saveStateCmd(), nil
+	default:
+		v, ok := config.FindView(m.savedViews, name)
+		if !ok {
+			return nil, fmt.Errorf("unknown view %q", name)
+		}
+		return m.openSavedView(v), nil
+	}
+}
+
+// recordCommand appends line to the command history, skipping a repeat of the
+// last command.
+func (m *model) recordCommand(line string) {
+	if n := len(m.cmdHistory); n > 0 && m.cmdHistory[n-1] == line {
+		return
+	}
+	m.cmdHistory = append(m.cmdHistory, line)
+	if len(m.cmdHistory) > cmdHistoryMax {
+		m.cmdHistory = append([]string(nil), m.cmdHistory[len(m.cmdHistory)-cmdHistoryMax:]...)
+	}
+}
+
+// browseCommandHistory moves through the command history by delta (-1 older,
+// +1 newer) and puts the entry into the input; past the newest it is empty.
+func (m *model) browseCommandHistory(delta int) {
+	n := len(m.cmdHistory)
+	if n == 0 {
+		return
+	}
+	pos := m.cmdHistoryPos + delta
+	if pos < 0 {
+		pos = 0
+	}
+	if pos >= n {
+		m.cmdHistoryPos = n
+		m.popup.input = ""
+	} else {
+		m.cmdHistoryPos = pos
+		m.popup.input = m.cmdHistory[pos]
+	}
+	m.popup.cursor = -1
+	m.popup.offset = 0
+	m.commandErr = ""
+}
+
+// submitCommand runs the command line of the context switcher: the entry under
+// the cursor, or else the typed input. A built-in without its argument, or a
+// parameter without its value, is put into the input to complete instead.
+func (m *model) submitCommand() tea.Cmd {
+	items := m.popupItems()
+	line := strings.TrimSpace(m.popup.input)
+	if m.popup.cursor >= 0 && m.popup.cursor < len(items) {
+		line = items[m.popup.cursor]
+	}
+	if line == "" {
+		return nil
+	}
+	if builtinTakesArg(line) || strings.HasSuffix(line, "=") {
+		m.completeCommand(line)
+		return nil
+	}
+
+	prev := m.cmdHistory
+	m.recordCommand(line)
+	m.activeModal = ModalNone
+	cmd, err := m.runCommand(line)
+	if err != nil {
+		m.cmdHistory = prev
+		m.activeModal = ModalContextSwitcher
+		m.commandErr = err.Error()
+		return nil
+	}
+	m.popup.input = ""
+	m.popup.cursor = -1
+	m.popup.offset = 0
+	m.commandErr = ""
+	return cmd
+}
+
+// completeCommand puts item into the input, followed by a space when a
+// built-in's argument comes next.
+func (m *model) completeCommand(item string) {
+	if builtinTakesArg(item) {
+		item += " "
+	}
+	m.popup.input = item
+	m.popup.cursor = -1
+	m.popup.offset = 0
+	m.commandErr = ""
+}
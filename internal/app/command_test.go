@@ -0,0 +1,155 @@
+package app
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/kthoms/o6n/internal/config"
+	"github.com/kthoms/o6n/internal/dao"
+)
+
+func commandTestModel(t *testing.T) model {
+	t.Helper()
+	m := queryFilterTestModel(t)
+	m.rootContexts = []string{"process-instance", "job", "task"}
+	for i := range m.config.Tables {
+		switch m.config.Tables[i].Name {
+		case "process-instance":
+			m.config.Tables[i].Aliases = []string{"pi"}
+		case "job":
+			m.config.Tables[i].Aliases = []string{"jobs"}
+			m.config.Tables[i].KeyParam = "processDefinitionKey"
+		}
+	}
+	m.config.Environments["prod"] = config.Environment{URL: "http://prod:8080"}
+	m.currentRoot = "task"
+	m.paneWidth, m.lastWidth, m.lastHeight = 120, 120, 40
+	return m
+}
+
+func runCommandLine(m model, line string) (model, tea.Cmd) {
+	m, _ = sendKeyString(m, ":")
+	m = typeQuery(m, line)
+	return sendKeyString(m, "enter")
+}
+
+func TestCommandOpensTableByAliasWithFilters(t *testing.T) {
+	m := commandTestModel(t)
+	m, cmd := runCommandLine(m, "pi suspended=true")
+	if cmd == nil || m.activeModal != ModalNone || m.currentRoot != "process-instance" {
+		t.Fatalf("expected :pi to open process-instance, got %s (err %q)", m.currentRoot, m.commandErr)
+	}
+	if len(m.queryFilters) != 1 || m.queryFilters["suspended"] != "true" {
+		t.Errorf("expected the inline filter, got %v", m.queryFilters)
+	}
+
+	// a bare value filters by the table's key param
+	m, _ = runCommandLine(m, "jobs invoice")
+	if m.currentRoot != "job" || m.queryFilters["processDefinitionKey"] != "invoice" {
+		t.Errorf("expected :jobs invoice to filter by key, got %s %v", m.currentRoot, m.queryFilters)
+	}
+	if got := strings.Join(m.cmdHistory, "|"); got != "pi suspended=true|jobs invoice" {
+		t.Errorf("expected both commands in the history, got %q", got)
+	}
+	if state := m.currentAppState(); len(state.CommandHistory) != 2 {
+		t.Errorf("expected the history to be persisted, got %v", state.CommandHistory)
+	}
+}
+
+func TestCommandRejectsInvalidInput(t *testing.T) {
+	m := commandTestModel(t)
+	for _, line := range []string{"pi suspended=maybe", "pi nope=1", "nosuch", "task demo", "env nowhere", "skin nope"} {
+		var cmd tea.Cmd
+		m, cmd = runCommandLine(m, line)
+		if cmd != nil || m.activeModal != ModalContextSwitcher || m.commandErr == "" {
+			t.Errorf("%q: expected an error in the command line, got modal %v err %q", line, m.activeModal, m.commandErr)
+		}
+		if !strings.Contains(m.renderContextSwitcherBody(), m.commandErr) {
+			t.Errorf("%q: expected the error to be shown", line)
+		}
+		m, _ = sendKeyString(m, "esc")
+	}
+	if m.currentRoot != "task" || len(m.cmdHistory) != 0 {
+		t.Errorf("expected rejected commands to change nothing, got %s %v", m.currentRoot, m.cmdHistory)
+	}
+}
+
+func TestCommandBuiltins(t *testing.T) {
+	m := commandTestModel(t)
+	m, cmd := runCommandLine(m, "env prod")
+	if cmd == nil || m.currentEnv != "prod" || m.currentRoot != dao.ResourceProcessDefinitions {
+		t.Fatalf("expected :env prod to switch environment, got %s/%s", m.currentEnv, m.currentRoot)
+	}
+	m, cmd = runCommandLine(m, "q")
+	if !m.quitting || cmd == nil {
+		t.Fatal("expected :q to quit")
+	}
+	if _, ok := cmd().(tea.QuitMsg); !ok {
+		t.Error("expected :q to return tea.Quit")
+	}
+}
+
+func TestCommandCompletionAndHistory(t *testing.T) {
+	m := commandTestModel(t)
+	m.cmdHistory = []string{"task", "pi"}
+
+	m, _ = sendKeyString(m, ":")
+	m = typeQuery(m, "p")
+	if items := m.popupItems(); !containsItem(items, "process-instance") {
+		t.Errorf("expected the table to be offered, got %v", items)
+	}
+	m, _ = sendKeyString(m, "backspace")
+	m = typeQuery(m, "en")
+	m, _ = sendKeyString(m, "tab")
+	if m.popup.input != "env " {
+		t.Fatalf("expected the built-in to complete with a space, got %q", m.popup.input)
+	}
+	if items := m.popupItems(); len(items) != 2 || items[0] != "env local" || items[1] != "env prod" {
+		t.Errorf("expected the environments to be offered, got %v", items)
+	}
+
+	m.popup.input = "pi with"
+	m, _ = sendKeyString(m, "tab")
+	if m.popup.input != "pi withIncident=" {
+		t.Fatalf("expected the parameter to complete, got %q", m.popup.input)
+	}
+	if items := m.popupItems(); len(items) != 2 || items[0] != "pi withIncident=true " {
+		t.Errorf("expected the parameter values to be offered, got %v", items)
+	}
+
+	m, _ = sendKeyString(m, "ctrl+p")
+	if m.popup.input != "pi" {
+		t.Errorf("expected Ctrl+P to recall the last command, got %q", m.popup.input)
+	}
+	m, _ = sendKeyString(m, "ctrl+p")
+	m, _ = sendKeyString(m, "ctrl+p")
+	if m.popup.input != "task" {
+		t.Errorf("expected Ctrl+P to stop at the oldest command, got %q", m.popup.input)
+	}
+	m, _ = sendKeyString(m, "ctrl+n")
+	m, _ = sendKeyString(m, "ctrl+n")
+	if m.popup.input != "" {
+		t.Errorf("expected Ctrl+N past the newest command to clear the input, got %q", m.popup.input)
+	}
+}
+
+func TestRecordCommandCapsHistory(t *testing.T) {
+	m := commandTestModel(t)
+	for i := 0; i < cmdHistoryMax+5; i++ {
+		m.recordCommand(strings.Repeat("x", i+1))
+	}
+	m.recordCommand(m.cmdHistory[len(m.cmdHistory)-1])
+	if len(m.cmdHistory) != cmdHistoryMax || m.cmdHistory[0] != strings.Repeat("x", 6) {
+		t.Errorf("expected the last %d distinct commands, got %d starting %q", cmdHistoryMax, len(m.cmdHistory), m.cmdHistory[0])
+	}
+}
+
+func containsItem(items []string, want string) bool {
+	for _, it := range items {
+		if it == want {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,160 @@
+package app
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/kthoms/o6n/internal/config"
+	"golang.org/x/term"
+	"gopkg.in/yaml.v3"
+)
+
+// passphraseEnvVar supplies the credentials file passphrase without a prompt,
+// e.g. for scripts and CI.
+const passphraseEnvVar = "O6N_PASSPHRASE"
+
+// readPassphrase returns $O6N_PASSPHRASE or prompts for a passphrase on the terminal.
+func readPassphrase(prompt string) (string, error) {
+	if p := os.Getenv(passphraseEnvVar); p != "" {
+		return p, nil
+	}
+	fd := int(os.Stdin.Fd())
+	if !term.IsTerminal(fd) {
+		return "", fmt.Errorf("no terminal for the passphrase prompt; set %s", passphraseEnvVar)
+	}
+	fmt.Fprint(os.Stderr, prompt)
+	b, err := term.ReadPassword(fd)
+	fmt.Fprintln(os.Stderr)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+// credentialsPassphrase is the passphrase callback of EnvConfig.ResolveSecrets.
+func credentialsPassphrase() (string, error) {
+	return readPassphrase("Passphrase for the o6n credentials file: ")
+}
+
+// runCreds implements `o6n creds encrypt|list`.
+func runCreds(args []string, stdout, stderr io.Writer) int {
+	usage := func() {
+		fmt.Fprintln(stderr, "Usage: o6n creds encrypt <plain.yaml> --out <file>   encrypt env name → {username, password, token, refresh_token, client_secret}")
+		fmt.Fprintln(stderr, "       o6n creds list [file]                        list the environments and fields of a credentials file")
+	}
+	if len(args) == 0 {
+		usage()
+		return 2
+	}
+	switch args[0] {
+	case "encrypt":
+		fs := flag.NewFlagSet("creds encrypt", flag.ContinueOnError)
+		fs.SetOutput(stderr)
+		out := fs.String("out", "", "credentials file to write (0600); reference it as credentials_file in o6n-env.yaml")
+		fs.Usage = usage
+		paths, err := parseInterspersed(fs, args[1:])
+		if err != nil {
+			if errors.Is(err, flag.ErrHelp) {
+				return 0
+			}
+			return 2
+		}
+		if len(paths) != 1 || *out == "" {
+			usage()
+			return 2
+		}
+		data, err := os.ReadFile(paths[0])
+		if err != nil {
+			fmt.Fprintf(stderr, "Error: %v\n", err)
+			return 1
+		}
+		creds := map[string]config.Credentials{}
+		if err := yaml.Unmarshal(data, &creds); err != nil {
+			fmt.Fprintf(stderr, "Error: %s: %v\n", paths[0], err)
+			return 1
+		}
+		pass, err := readPassphrase("New passphrase: ")
+		if err == nil && os.Getenv(passphraseEnvVar) == "" {
+			var again string
+			if again, err = readPassphrase("Repeat passphrase: "); err == nil && again != pass {
+				err = fmt.Errorf("passphrases do not match")
+			}
+		}
+		if err != nil {
+			fmt.Fprintf(stderr, "Error: %v\n", err)
+			return 1
+		}
+		sealed, err := config.EncryptCredentials(creds, pass)
+		if err == nil {
+			err = os.WriteFile(*out, sealed, 0o600)
+		}
+		if err != nil {
+			fmt.Fprintf(stderr, "Error: %v\n", err)
+			return 1
+		}
+		fmt.Fprintf(stdout, "✓ Encrypted credentials for %d environments to %s — delete %s now\n", len(creds), *out, paths[0])
+		return 0
+	case "list":
+		path := ""
+		if len(args) > 1 {
+			path = args[1]
+		} else if envCfg, err := config.LoadEnvConfig(envConfigPath); err == nil {
+			path = envCfg.CredentialsFile
+		}
+		if path == "" {
+			fmt.Fprintf(stderr, "Error: no credentials_file in %s\n", envConfigPath)
+			return 1
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			fmt.Fprintf(stderr, "Error: %v\n", err)
+			return 1
+		}
+		pass, err := credentialsPassphrase()
+		if err != nil {
+			fmt.Fprintf(stderr, "Error: %v\n", err)
+			return 1
+		}
+		creds, err := config.DecryptCredentials(data, pass)
+		if err != nil {
+			fmt.Fprintf(stderr, "Error: %s: %v\n", path, err)
+			return 1
+		}
+		fmt.Fprint(stdout, credentialsSummary(creds))
+		return 0
+	default:
+		usage()
+		return 2
+	}
+}
+
+// credentialsSummary lists which secrets each environment has, never the values.
+func credentialsSummary(creds map[string]config.Credentials) string {
+	names := make([]string, 0, len(creds))
+	for name := range creds {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var b strings.Builder
+	for _, name := range names {
+		c := creds[name]
+		var fields []string
+		for _, f := range []struct {
+			name, value string
+		}{
+			{"username", c.Username}, {"password", c.Password}, {"token", c.Token},
+			{"refresh_token", c.RefreshToken}, {"client_secret", c.ClientSecret},
+		} {
+			if f.value != "" {
+				fields = append(fields, f.name)
+			}
+		}
+		fmt.Fprintf(&b, "%s: %s\n", name, strings.Join(fields, ", "))
+	}
+	return b.String()
+}
@@ -0,0 +1,351 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/kthoms/o6n/internal/client"
+	"github.com/kthoms/o6n/internal/config"
+)
+
+// dashboardContext is the home context that opens the dashboard instead of a
+// table; it is offered by ModalFirstRun and the : command line.
+const dashboardContext = "dashboard"
+
+// dashboardTileWidth is the minimum width of a dashboard tile.
+const dashboardTileWidth = 30
+
+// dashboardTile is one count of the dashboard, drillable into its table with
+// params as drilldown params. Params valued config.AlertNow are replaced by the
+// current time.
+type dashboardTile struct {
+	title  string
+	table  string
+	params map[string]string
+	count  int
+	detail []string
+	alarm  bool // count > 0 is a problem and is shown as such
+	err    string
+}
+
+// dashboardState holds the engine dashboard (ModalDashboard).
+type dashboardState struct {
+	home    bool // chosen as home context: opened at startup
+	onStart bool // open the dashboard once startup is done
+	env     string
+	tiles   []dashboardTile
+	cursor  int
+	loading bool
+	err     string
+	at      time.Time
+}
+
+// openDashboardMsg opens the dashboard after startup when it is the home context.
+type openDashboardMsg struct{}
+
+// dashboardLoadedMsg delivers the tiles of the dashboard of env.
+type dashboardLoadedMsg struct {
+	env   string
+	tiles []dashboardTile
+	at    time.Time
+	err   error
+}
+
+// dashboardDefinitionStats is the part of /process-definition/statistics the
+// dashboard uses.
+type dashboardDefinitionStats struct {
+	Instances  int `json:"instances"`
+	FailedJobs int `json:"failedJobs"`
+	Incidents  []struct {
+		IncidentType  string `json:"incidentType"`
+		IncidentCount int    `json:"incidentCount"`
+	} `json:"incidents"`
+	Definition struct {
+		Key string `json:"key"`
+	} `json:"definition"`
+}
+
+// dashboardBatchStats is the part of /batch/statistics the dashboard uses.
+type dashboardBatchStats struct {
+	TotalJobs     int `json:"totalJobs"`
+	CompletedJobs int `json:"completedJobs"`
+	FailedJobs    int `json:"failedJobs"`
+}
+
+// openDashboard opens the dashboard of the current environment and loads it.
+func (m *model) openDashboard() tea.Cmd {
+	m.dashboard.cursor = 0
+	m.dashboard.err = ""
+	m.dashboard.loading = true
+	m.activeModal = ModalDashboard
+	return m.fetchDashboardCmd()
+}
+
+// fetchDashboardCmd loads the counts of the dashboard tiles in the current
+// environment.
+func (m *model) fetchDashboardCmd() tea.Cmd {
+	envName := m.currentEnv
+	env, ok := m.config.Environments[envName]
+	if !ok {
+		return nil
+	}
+	base := strings.TrimRight(env.URL, "/")
+	count := func(table string, params map[string]string) func(context.Context) (int, error) {
+		_, countPath := m.tablePaths(table)
+		return func(ctx context.Context) (int, error) {
+			var body struct {
+				Count *int `json:"count"`
+			}
+			urlStr := appendQueryFilters(base+"/"+strings.TrimLeft(countPath, "/"), params)
+			if err := client.GetJSON(ctx, env, urlStr, &body); err != nil {
+				return 0, err
+			}
+			if body.Count == nil {
+				return 0, errors.New("no count in response")
+			}
+			return *body.Count, nil
+		}
+	}
+	tiles := []dashboardTile{
+		{title: "Running instances", table: "process-instance"},
+		{title: "Open incidents", table: "incident", alarm: true},
+		{title: "Failed jobs", table: "job", params: map[string]string{"noRetriesLeft": "true"}, alarm: true},
+		{title: "Unassigned tasks", table: "task", params: map[string]string{"unassigned": "true"}},
+		{title: "Expired external task locks", table: "external-task", params: map[string]string{"lockExpirationBefore": config.AlertNow}, alarm: true},
+	}
+	counts := make([]func(context.Context) (int, error), len(tiles))
+	for i, t := range tiles {
+		counts[i] = count(t.table, resolveNowParams(t.params, time.Now()))
+	}
+	batchPath, _ := m.tablePaths("batch-statistics")
+
+	return func() tea.Msg {
+		now := time.Now()
+		msg := dashboardLoadedMsg{env: envName, at: now}
+		get := func(fn func(ctx context.Context) error) error {
+			ctx, cancel := context.WithTimeout(context.Background(), env.RequestTimeout())
+			defer cancel()
+			return fn(ctx)
+		}
+
+		for i := range tiles {
+			err := get(func(ctx context.Context) (err error) {
+				tiles[i].count, err = counts[i](ctx)
+				return err
+			})
+			if err != nil {
+				tiles[i].err = err.Error()
+			}
+		}
+
+		var stats []dashboardDefinitionStats
+		if err := get(func(ctx context.Context) error {
+			return client.GetJSON(ctx, env, base+"/process-definition/statistics?failedJobs=true&incidents=true", &stats)
+		}); err != nil {
+			msg.err = fmt.Errorf("process definition statistics: %w", err)
+		}
+		byType := map[string]int{}
+		var failing []dashboardDefinitionStats
+		for _, s := range stats {
+			for _, inc := range s.Incidents {
+				byType[inc.IncidentType] += inc.IncidentCount
+			}
+			if s.FailedJobs > 0 {
+				failing = append(failing, s)
+			}
+		}
+		sort.SliceStable(failing, func(i, j int) bool { return failing[i].FailedJobs > failing[j].FailedJobs })
+		for i, s := range failing {
+			if i == 3 {
+				break
+			}
+			tiles[2].detail = append(tiles[2].detail, fmt.Sprintf("%s: %d", s.Definition.Key, s.FailedJobs))
+		}
+		types := make([]string, 0, len(byType))
+		for t := range byType {
+			types = append(types, t)
+		}
+		sort.Strings(types)
+		var typeTiles []dashboardTile
+		for _, t := range types {
+			tiles[1].detail = append(tiles[1].detail, fmt.Sprintf("%s: %d", t, byType[t]))
+			typeTiles = append(typeTiles, dashboardTile{title: "Incidents: " + t, table: "incident", params: map[string]string{"incidentType": t}, count: byType[t], alarm: true})
+		}
+
+		batch := dashboardTile{title: "Batches", table: "batch-statistics"}
+		var batches []dashboardBatchStats
+		if err := get(func(ctx context.Context) error {
+			return client.GetJSON(ctx, env, base+"/"+strings.TrimLeft(batchPath, "/"), &batches)
+		}); err != nil {
+			batch.err = err.Error()
+		} else {
+			batch.count = len(batches)
+			total, completed, failed := 0, 0, 0
+			for _, b := range batches {
+				total += b.TotalJobs
+				completed += b.CompletedJobs
+				failed += b.FailedJobs
+			}
+			if total > 0 {
+				batch.detail = append(batch.detail, fmt.Sprintf("%s %d/%d jobs", progressBar(completed, total, 10), completed, total))
+			}
+			if failed > 0 {
+				batch.detail = append(batch.detail, fmt.Sprintf("%d failed jobs", failed))
+			}
+		}
+
+		msg.tiles = append(append(tiles[:2:2], typeTiles...), append(tiles[2:], batch)...)
+		return msg
+	}
+}
+
+// progressBar renders done of total as a bar of width cells and a percentage.
+func progressBar(done, total, width int) string {
+	if total <= 0 {
+		return strings.Repeat("░", width)
+	}
+	filled := done * width / total
+	if filled > width {
+		filled = width
+	}
+	return fmt.Sprintf("%s%s %d%%", strings.Repeat("█", filled), strings.Repeat("░", width-filled), done*100/total)
+}
+
+// applyDashboard stores loaded tiles if they belong to the environment shown.
+func (m *model) applyDashboard(msg dashboardLoadedMsg) {
+	if msg.env != m.currentEnv {
+		return
+	}
+	m.dashboard.loading = false
+	m.dashboard.env = msg.env
+	m.dashboard.tiles = msg.tiles
+	m.dashboard.at = msg.at
+	m.dashboard.err = ""
+	if msg.err != nil {
+		m.dashboard.err = friendlyError(m.currentEnv, msg.err)
+	}
+	if m.dashboard.cursor >= len(msg.tiles) {
+		m.dashboard.cursor = 0
+	}
+}
+
+// dashboardColumns is the number of tiles per row of the dashboard.
+func (m *model) dashboardColumns() int {
+	cols := m.dashboardInnerWidth() / dashboardTileWidth
+	if cols < 1 {
+		cols = 1
+	}
+	return cols
+}
+
+func (m *model) dashboardInnerWidth() int {
+	w := int(float64(m.lastWidth)*0.80) - 6
+	if w < dashboardTileWidth {
+		w = dashboardTileWidth
+	}
+	return w
+}
+
+// drillDashboardTile opens the table of tile with its params as drilldown params.
+func (m *model) drillDashboardTile(tile dashboardTile) tea.Cmd {
+	m.activeModal = ModalNone
+	return m.openTableWithParams(tile.table, resolveNowParams(tile.params, time.Now()), nil)
+}
+
+// handleDashboardKey processes a key press while ModalDashboard is open.
+func (m *model) handleDashboardKey(msg tea.KeyMsg) tea.Cmd {
+	n := len(m.dashboard.tiles)
+	cols := m.dashboardColumns()
+	switch msg.String() {
+	case "esc", "q":
+		m.activeModal = ModalNone
+	case "r":
+		m.dashboard.loading = true
+		return m.fetchDashboardCmd()
+	case "enter":
+		if m.dashboard.cursor >= 0 && m.dashboard.cursor < n {
+			return m.drillDashboardTile(m.dashboard.tiles[m.dashboard.cursor])
+		}
+	case "right", "l", "tab":
+		if m.dashboard.cursor < n-1 {
+			m.dashboard.cursor++
+		}
+	case "left", "h", "shift+tab":
+		if m.dashboard.cursor > 0 {
+			m.dashboard.cursor--
+		}
+	case "down", "j":
+		if m.dashboard.cursor+cols < n {
+			m.dashboard.cursor += cols
+		}
+	case "up", "k":
+		if m.dashboard.cursor-cols >= 0 {
+			m.dashboard.cursor -= cols
+		}
+	}
+	return nil
+}
+
+// renderDashboardTile renders tile as a bordered box of width w, highlighted
+// when selected.
+func (m *model) renderDashboardTile(tile dashboardTile, w int, selected bool) string {
+	box := m.styles.ModalBorderFg
+	if selected {
+		box = m.styles.ModalBorderFocus
+	}
+	inner := w - 4
+	var lines []string
+	lines = append(lines, ansi.Truncate(tile.title, inner, "…"))
+	switch {
+	case tile.err != "":
+		lines = append(lines, m.styles.ValidationError.Render(ansi.Truncate("⚠ "+tile.err, inner, "…")))
+	case tile.alarm && tile.count > 0:
+		lines = append(lines, m.styles.RowFailed.Bold(true).Render(fmt.Sprintf("%d", tile.count)))
+	default:
+		lines = append(lines, m.styles.Accent.Bold(true).Render(fmt.Sprintf("%d", tile.count)))
+	}
+	for i := 0; i < 3; i++ {
+		line := ""
+		if i < len(tile.detail) {
+			line = ansi.Truncate(tile.detail[i], inner, "…")
+		}
+		lines = append(lines, m.styles.FgMuted.Render(line))
+	}
+	return box.Padding(0, 1).Width(w - 2).Render(strings.Join(lines, "\n"))
+}
+
+// modalDashboardBody renders the dashboard tiles in rows.
+func (m *model) modalDashboardBody() string {
+	var b strings.Builder
+	title := "Engine dashboard — " + m.currentEnv
+	if !m.dashboard.at.IsZero() {
+		title += "  " + m.dashboard.at.Local().Format("15:04:05")
+	}
+	b.WriteString(m.styles.Accent.Render(title) + "\n\n")
+	if m.dashboard.loading && len(m.dashboard.tiles) == 0 {
+		b.WriteString(m.styles.FgMuted.Render("Loading…") + "\n")
+		return b.String()
+	}
+	cols := m.dashboardColumns()
+	w := m.dashboardInnerWidth() / cols
+	var rows []string
+	for start := 0; start < len(m.dashboard.tiles); start += cols {
+		var row []string
+		for i := start; i < start+cols && i < len(m.dashboard.tiles); i++ {
+			row = append(row, m.renderDashboardTile(m.dashboard.tiles[i], w, i == m.dashboard.cursor))
+		}
+		rows = append(rows, lipgloss.JoinHorizontal(lipgloss.Top, row...))
+	}
+	b.WriteString(strings.Join(rows, "\n"))
+	if m.dashboard.err != "" {
+		b.WriteString("\n" + m.styles.ValidationError.Render("⚠ "+m.dashboard.err))
+	}
+	return b.String()
+}
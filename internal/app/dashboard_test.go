@@ -0,0 +1,104 @@
+package app
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/kthoms/o6n/internal/config"
+)
+
+func TestDashboardLoadsTilesAndDrillsIntoTables(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/process-instance/count":
+			_, _ = w.Write([]byte(`{"count":12}`))
+		case "/job/count":
+			if r.URL.Query().Get("noRetriesLeft") != "true" {
+				t.Errorf("expected the failed jobs to be counted, got %s", r.URL.RawQuery)
+			}
+			_, _ = w.Write([]byte(`{"count":4}`))
+		case "/external-task/count":
+			if r.URL.Query().Get("lockExpirationBefore") == "" {
+				t.Errorf("expected the expired locks to be counted, got %s", r.URL.RawQuery)
+			}
+			_, _ = w.Write([]byte(`{"count":0}`))
+		case "/process-definition/statistics":
+			_, _ = w.Write([]byte(`[
+				{"instances":7,"failedJobs":3,"definition":{"key":"invoice"},"incidents":[{"incidentType":"failedJob","incidentCount":3}]},
+				{"instances":5,"failedJobs":1,"definition":{"key":"order"},"incidents":[{"incidentType":"failedExternalTask","incidentCount":2}]}]`))
+		case "/batch-statistics":
+			_, _ = w.Write([]byte(`[{"totalJobs":10,"completedJobs":5,"failedJobs":1}]`))
+		default:
+			_, _ = w.Write([]byte(`{"count":1}`))
+		}
+	}))
+	defer srv.Close()
+
+	m := modeTestModel(t, config.ModeNormal)
+	m.config.Environments["local"] = config.Environment{URL: srv.URL}
+	m.lastWidth, m.lastHeight = 160, 40
+	cmd := m.openDashboard()
+	if m.activeModal != ModalDashboard || cmd == nil {
+		t.Fatal("expected the dashboard to open and load")
+	}
+	res, _ := m.Update(cmd())
+	m = res.(model)
+
+	var titles []string
+	for _, tile := range m.dashboard.tiles {
+		titles = append(titles, tile.title)
+	}
+	want := "Running instances,Open incidents,Incidents: failedExternalTask,Incidents: failedJob,Failed jobs,Unassigned tasks,Expired external task locks,Batches"
+	if got := strings.Join(titles, ","); got != want {
+		t.Fatalf("unexpected tiles %s", got)
+	}
+	if tile := m.dashboard.tiles[4]; tile.count != 4 || len(tile.detail) != 2 || tile.detail[0] != "invoice: 3" {
+		t.Errorf("expected the failed jobs with their definitions, got %+v", tile)
+	}
+	if tile := m.dashboard.tiles[7]; tile.count != 1 || !strings.Contains(tile.detail[0], "5/10 jobs") {
+		t.Errorf("expected the batch progress, got %+v", tile)
+	}
+	if body := m.modalDashboardBody(); !strings.Contains(body, "Running instances") || !strings.Contains(body, "12") {
+		t.Errorf("expected the tiles to be rendered, got:\n%s", body)
+	}
+
+	m, _ = sendKeyString(m, "right")
+	m, _ = sendKeyString(m, "right")
+	m, _ = sendKeyString(m, "right")
+	m, _ = sendKeyString(m, "right")
+	m, cmd = sendKeyString(m, "enter")
+	if cmd == nil || m.activeModal != ModalNone || m.currentRoot != "job" || m.genericParams["noRetriesLeft"] != "true" {
+		t.Errorf("expected Enter to open the failed jobs, got %s %v", m.currentRoot, m.genericParams)
+	}
+}
+
+func TestDashboardIsAHomeContext(t *testing.T) {
+	m := testModelWithContexts(t)
+	m.activeModal = ModalFirstRun
+	m, _ = sendKeyString(m, "dash")
+	m, cmd := sendKeyString(m, "enter")
+	if cmd == nil || m.activeModal != ModalDashboard || !m.dashboard.home {
+		t.Fatal("expected the dashboard to open as home context")
+	}
+	if !m.currentAppState().HomeDashboard {
+		t.Error("expected the home context to be saved")
+	}
+
+	m.activeModal = ModalFirstRun
+	m.firstRunInput = ""
+	m, _ = sendKeyString(m, "enter")
+	if m.dashboard.home {
+		t.Error("expected a table home context to replace the dashboard")
+	}
+}
+
+func TestProgressBar(t *testing.T) {
+	if got := progressBar(5, 10, 10); got != "█████░░░░░ 50%" {
+		t.Errorf("unexpected bar %q", got)
+	}
+	if got := progressBar(0, 0, 4); got != "░░░░" {
+		t.Errorf("unexpected bar for no jobs %q", got)
+	}
+}
@@ -0,0 +1,286 @@
+package app
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/table"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/kthoms/o6n/internal/client"
+	"github.com/kthoms/o6n/internal/operaton"
+)
+
+// deploymentSource is sent as deployment-source with every deployment o6n creates.
+const deploymentSource = "o6n"
+
+// Focusable fields of the deploy form (ModalDeploy), in Tab order.
+const (
+	deployFieldFiles = iota
+	deployFieldName
+	deployFieldTenant
+	deployFieldDuplicates
+	deployFieldChangedOnly
+	deployFieldCount
+)
+
+// deployState holds the deploy form.
+type deployState struct {
+	files       textinput.Model
+	name        textinput.Model
+	tenant      textinput.Model
+	duplicates  bool // enable-duplicate-filtering
+	changedOnly bool // deploy-changed-only
+	focus       int
+	running     bool
+	err         string
+}
+
+// deployDoneMsg delivers the result of a deployment started from the deploy form.
+type deployDoneMsg struct {
+	deployment *operaton.DeploymentWithDefinitionsDto
+	err        error
+}
+
+// splitDeployPaths splits the files input into paths (separated by commas or
+// newlines, so paths may contain spaces) and expands a leading ~ to the home
+// directory.
+func splitDeployPaths(s string) []string {
+	home, _ := os.UserHomeDir()
+	var paths []string
+	for _, p := range strings.FieldsFunc(s, func(r rune) bool { return r == ',' || r == '\n' }) {
+		if p = strings.TrimSpace(p); p == "" {
+			continue
+		}
+		if home != "" && (p == "~" || strings.HasPrefix(p, "~/")) {
+			p = filepath.Join(home, strings.TrimPrefix(p, "~"))
+		}
+		paths = append(paths, p)
+	}
+	return paths
+}
+
+// defaultDeploymentName derives a deployment name from the first path, e.g.
+// "models/invoice.bpmn" → "invoice".
+func defaultDeploymentName(paths []string) string {
+	if len(paths) == 0 {
+		return ""
+	}
+	name := filepath.Base(filepath.Clean(paths[0]))
+	if i := strings.Index(name, "."); i > 0 {
+		name = name[:i]
+	}
+	return name
+}
+
+// deployedDefinitionLines lists the definitions of a deployment, sorted by kind and id.
+func deployedDefinitionLines(dep *operaton.DeploymentWithDefinitionsDto) []string {
+	var lines []string
+	for id, d := range dep.DeployedProcessDefinitions {
+		lines = append(lines, fmt.Sprintf("process   %s (%s v%d)", id, client.GetStringValue(d.Key), client.GetInt32Value(d.Version)))
+	}
+	for id, d := range dep.DeployedDecisionDefinitions {
+		lines = append(lines, fmt.Sprintf("decision  %s (%s v%d)", id, client.GetStringValue(d.Key), client.GetInt32Value(d.Version)))
+	}
+	for id, d := range dep.DeployedDecisionRequirementsDefinitions {
+		lines = append(lines, fmt.Sprintf("drd       %s (%s v%d)", id, client.GetStringValue(d.Key), client.GetInt32Value(d.Version)))
+	}
+	for id, d := range dep.DeployedCaseDefinitions {
+		lines = append(lines, fmt.Sprintf("case      %s (%s v%d)", id, client.GetStringValue(d.Key), client.GetInt32Value(d.Version)))
+	}
+	sort.Strings(lines)
+	return lines
+}
+
+// openDeployForm opens the deploy form (ModalDeploy).
+func (m *model) openDeployForm() tea.Cmd {
+	newInput := func(placeholder string) textinput.Model {
+		in := textinput.New()
+		in.Prompt = ""
+		in.CharLimit = 0
+		in.Width = 50
+		in.Placeholder = placeholder
+		return in
+	}
+	m.deploy = deployState{
+		files:      newInput("models/ or invoice.bpmn, rules.dmn"),
+		name:       newInput("default: first file name"),
+		tenant:     newInput("none"),
+		duplicates: true,
+	}
+	m.activeModal = ModalDeploy
+	return m.deploy.files.Focus()
+}
+
+// setDeployFocus moves the form focus to field, focusing its text input if any.
+func (m *model) setDeployFocus(field int) tea.Cmd {
+	m.deploy.focus = (field + deployFieldCount) % deployFieldCount
+	m.deploy.files.Blur()
+	m.deploy.name.Blur()
+	m.deploy.tenant.Blur()
+	if in := m.deployInput(); in != nil {
+		return in.Focus()
+	}
+	return nil
+}
+
+// deployInput returns the text input of the focused field, or nil for a checkbox.
+func (m *model) deployInput() *textinput.Model {
+	switch m.deploy.focus {
+	case deployFieldFiles:
+		return &m.deploy.files
+	case deployFieldName:
+		return &m.deploy.name
+	case deployFieldTenant:
+		return &m.deploy.tenant
+	}
+	return nil
+}
+
+// deployCmd collects the files and creates the deployment.
+func (m model) deployCmd(paths []string, opts client.DeployOptions) tea.Cmd {
+	env, ok := m.config.Environments[m.currentEnv]
+	if !ok {
+		return nil
+	}
+	c := client.NewClient(env, m.debugEnabled).WithContext(m.auditContext("Deploy " + opts.Name))
+	return func() tea.Msg {
+		files, err := client.CollectDeploymentFiles(paths)
+		if err != nil {
+			return deployDoneMsg{err: err}
+		}
+		dep, err := c.CreateDeployment(files, opts)
+		return deployDoneMsg{deployment: dep, err: err}
+	}
+}
+
+// submitDeployForm validates the form and starts the deployment.
+func (m *model) submitDeployForm() tea.Cmd {
+	paths := splitDeployPaths(m.deploy.files.Value())
+	if len(paths) == 0 {
+		m.deploy.err = "at least one file or directory is required"
+		return m.setDeployFocus(deployFieldFiles)
+	}
+	opts := client.DeployOptions{
+		Name:                     strings.TrimSpace(m.deploy.name.Value()),
+		TenantID:                 strings.TrimSpace(m.deploy.tenant.Value()),
+		Source:                   deploymentSource,
+		EnableDuplicateFiltering: m.deploy.duplicates || m.deploy.changedOnly,
+		DeployChangedOnly:        m.deploy.changedOnly,
+	}
+	if opts.Name == "" {
+		opts.Name = defaultDeploymentName(paths)
+	}
+	m.deploy.err = ""
+	return m.guardMutation("Deploy "+opts.Name, opts.Name, ModalDeploy, func(m *model) tea.Cmd {
+		m.deploy.running = true
+		return m.deployCmd(paths, opts)
+	})
+}
+
+// handleDeployKey processes a key press while ModalDeploy is open.
+func (m *model) handleDeployKey(msg tea.KeyMsg) tea.Cmd {
+	if m.deploy.running {
+		return nil
+	}
+	switch msg.String() {
+	case "esc":
+		m.activeModal = ModalNone
+		m.deploy = deployState{}
+		return nil
+	case "tab", "down":
+		return m.setDeployFocus(m.deploy.focus + 1)
+	case "shift+tab", "up":
+		return m.setDeployFocus(m.deploy.focus - 1)
+	case "enter":
+		return m.submitDeployForm()
+	case " ":
+		switch m.deploy.focus {
+		case deployFieldDuplicates:
+			m.deploy.duplicates = !m.deploy.duplicates
+			return nil
+		case deployFieldChangedOnly:
+			m.deploy.changedOnly = !m.deploy.changedOnly
+			return nil
+		}
+	}
+	if in := m.deployInput(); in != nil {
+		var cmd tea.Cmd
+		*in, cmd = in.Update(msg)
+		return cmd
+	}
+	return nil
+}
+
+// finishDeploy handles a deployDoneMsg: on success it closes the form and shows the
+// deployed definitions in the process-definition view.
+func (m *model) finishDeploy(msg deployDoneMsg) tea.Cmd {
+	if m.activeModal != ModalDeploy {
+		return nil
+	}
+	m.deploy.running = false
+	if msg.err != nil {
+		m.deploy.err = friendlyError(m.currentEnv, msg.err)
+		return nil
+	}
+	m.activeModal = ModalNone
+	m.deploy = deployState{}
+
+	dep := msg.deployment
+	id := client.GetStringValue(dep.Id)
+	n := len(deployedDefinitionLines(dep))
+	const target = "process-definition"
+	m.prepareStateTransition(TransitionDrillDown)
+	m.currentRoot = target
+	m.viewMode = target
+	m.genericParams = map[string]string{"deploymentId": id}
+	m.breadcrumb = append(m.breadcrumb, target)
+	m.contentHeader = fmt.Sprintf("%s — %s", target, client.GetStringValue(dep.Name))
+
+	cols := m.buildColumnsFor(target, m.paneWidth-4)
+	m.table.SetRows([]table.Row{})
+	if len(cols) > 0 {
+		m.table.SetColumns(cols)
+		m.table.SetRows(normalizeRows(nil, len(cols)))
+	}
+	m.table.SetCursor(0)
+
+	var statusCmd tea.Cmd
+	m.footerError, m.footerStatusKind, statusCmd = setFooterStatus(footerStatusSuccess,
+		fmt.Sprintf("✓ Deployed %s: %d definitions", client.GetStringValue(dep.Name), n), 5*time.Second)
+	return tea.Batch(statusCmd, m.fetchGenericCmd(target), flashOnCmd(), m.saveStateCmd())
+}
+
+// modalDeployBody renders the deploy form.
+func (m *model) modalDeployBody() string {
+	label := func(field int, text string) string {
+		if m.deploy.focus == field {
+			return m.styles.Accent.Render("> " + text)
+		}
+		return "  " + text
+	}
+	check := func(on bool) string {
+		if on {
+			return "[x]"
+		}
+		return "[ ]"
+	}
+	var b strings.Builder
+	b.WriteString("Deploy BPMN / DMN / CMMN / form files\n\n")
+	fmt.Fprintf(&b, "%s\n    %s\n", label(deployFieldFiles, "Files or directories"), m.deploy.files.View())
+	fmt.Fprintf(&b, "%s\n    %s\n", label(deployFieldName, "Deployment name"), m.deploy.name.View())
+	fmt.Fprintf(&b, "%s\n    %s\n\n", label(deployFieldTenant, "Tenant"), m.deploy.tenant.View())
+	fmt.Fprintf(&b, "%s\n", label(deployFieldDuplicates, check(m.deploy.duplicates)+" enable duplicate filtering"))
+	fmt.Fprintf(&b, "%s\n", label(deployFieldChangedOnly, check(m.deploy.changedOnly)+" deploy changed resources only"))
+	if m.deploy.running {
+		b.WriteString("\n" + m.styles.FgMuted.Render("Deploying…") + "\n")
+	}
+	if m.deploy.err != "" {
+		b.WriteString("\n" + m.styles.ValidationError.Render(m.deploy.err) + "\n")
+	}
+	return b.String()
+}
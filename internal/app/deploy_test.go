@@ -0,0 +1,109 @@
+package app
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/bubbles/table"
+)
+
+func TestDefaultDeploymentName(t *testing.T) {
+	cases := map[string]string{
+		"models/invoice.bpmn":   "invoice",
+		"models/":               "models",
+		"rules.dmn11.xml":       "rules",
+		"/tmp/hotfix-2024.bpmn": "hotfix-2024",
+	}
+	for in, want := range cases {
+		if got := defaultDeploymentName([]string{in}); got != want {
+			t.Errorf("defaultDeploymentName(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestSplitDeployPathsKeepsSpacesInPaths(t *testing.T) {
+	home, _ := os.UserHomeDir()
+	got := splitDeployPaths("My Models/invoice v2.bpmn, rules.dmn\n~/forms ,")
+	want := []string{"My Models/invoice v2.bpmn", "rules.dmn", filepath.Join(home, "forms")}
+	if strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Errorf("splitDeployPaths = %q, want %q", got, want)
+	}
+}
+
+func TestDeployFormDeploysAndShowsDefinitions(t *testing.T) {
+	dir := t.TempDir()
+	_ = os.WriteFile(filepath.Join(dir, "invoice.bpmn"), []byte("<definitions/>"), 0o644)
+
+	var gotName, gotChangedOnly string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/deployment/create" {
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`[]`))
+			return
+		}
+		_ = r.ParseMultipartForm(1 << 20)
+		gotName, gotChangedOnly = r.FormValue("deployment-name"), r.FormValue("deploy-changed-only")
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":"dep-1","name":"invoice","deployedProcessDefinitions":{"invoice:2:x":{"key":"invoice","version":2}}}`))
+	}))
+	defer srv.Close()
+
+	m := tableTestModel(t, srv.URL, "deployment", []string{"id"}, []table.Row{{"dep-0"}})
+
+	var item actionItem
+	for _, it := range m.buildActionsForRoot() {
+		if it.key == "n" {
+			item = it
+		}
+	}
+	if item.cmd == nil {
+		t.Fatal("expected Deploy Files in the deployment actions")
+	}
+	item.cmd(&m)
+	if m.activeModal != ModalDeploy {
+		t.Fatalf("expected deploy form, got modal %v", m.activeModal)
+	}
+
+	m, _ = sendKeyString(m, "enter")
+	if !strings.Contains(m.deploy.err, "required") {
+		t.Errorf("expected empty files to be refused, got %q", m.deploy.err)
+	}
+	m, _ = sendKeyString(m, dir)
+	for i := 0; i < deployFieldChangedOnly; i++ {
+		m, _ = sendKeyString(m, "tab")
+	}
+	m, _ = sendKeyString(m, " ")
+	if !m.deploy.changedOnly {
+		t.Fatal("expected Space to toggle deploy-changed-only")
+	}
+
+	m, cmd := sendKeyString(m, "enter")
+	done := findMsg[deployDoneMsg](t, cmd)
+	if done.err != nil {
+		t.Fatalf("deploy failed: %v", done.err)
+	}
+	if gotName != filepath.Base(dir) || gotChangedOnly != "true" {
+		t.Errorf("unexpected form fields name=%q changed-only=%q", gotName, gotChangedOnly)
+	}
+
+	res, _ := m.Update(done)
+	m = res.(model)
+	if m.activeModal != ModalNone || m.currentRoot != "process-definition" || m.genericParams["deploymentId"] != "dep-1" {
+		t.Fatalf("expected process-definition view of dep-1, got modal %v root %s %v", m.activeModal, m.currentRoot, m.genericParams)
+	}
+	if !strings.Contains(m.footerError, "1 definitions") {
+		t.Errorf("expected deploy summary in footer, got %q", m.footerError)
+	}
+}
+
+func TestRunDeployRequiresPath(t *testing.T) {
+	var out, errOut bytes.Buffer
+	if code := runDeploy(nil, &out, &errOut); code != 2 {
+		t.Errorf("expected usage exit code 2 without paths, got %d", code)
+	}
+}
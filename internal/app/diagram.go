@@ -0,0 +1,249 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/kthoms/o6n/internal/bpmn"
+	"github.com/kthoms/o6n/internal/client"
+	"github.com/kthoms/o6n/internal/config"
+	"github.com/kthoms/o6n/internal/operaton"
+)
+
+// diagramState holds the BPMN diagram view (ModalDiagram).
+type diagramState struct {
+	definitionID string
+	instanceID   string // "" = definition-wide statistics
+	title        string
+	lines        []bpmn.Line
+	active       map[string]int // running activity instances by activity id
+	incidents    map[string]int // open incidents by activity id
+	loading      bool
+	err          string
+	scroll       int
+}
+
+// diagramLoadedMsg delivers a laid out diagram with its runtime overlay.
+type diagramLoadedMsg struct {
+	definitionID string
+	instanceID   string
+	title        string
+	lines        []bpmn.Line
+	active       map[string]int
+	incidents    map[string]int
+	err          error
+}
+
+// countActivityInstances adds the running activity and transition instances of an
+// activity instance tree, and their incidents, to active and incidents.
+func countActivityInstances(ai operaton.ActivityInstanceDto, active, incidents map[string]int) {
+	for _, child := range ai.ChildActivityInstances {
+		id := client.GetStringValue(child.ActivityId)
+		active[id]++
+		incidents[id] += len(child.IncidentIds)
+		countActivityInstances(child, active, incidents)
+	}
+	for _, ti := range ai.ChildTransitionInstances {
+		id := client.GetStringValue(ti.ActivityId)
+		active[id]++
+		incidents[id] += len(ti.IncidentIds)
+	}
+}
+
+// fetchDiagramCmd loads the BPMN XML of definitionID, lays it out and overlays the
+// activity instances of instanceID or, without an instance, the definition's
+// activity statistics.
+func (m model) fetchDiagramCmd(definitionID, instanceID string) tea.Cmd {
+	env, ok := m.config.Environments[m.currentEnv]
+	if !ok {
+		return nil
+	}
+	c := client.NewClient(env, m.debugEnabled)
+	return func() tea.Msg {
+		msg := diagramLoadedMsg{definitionID: definitionID, instanceID: instanceID, active: map[string]int{}, incidents: map[string]int{}}
+		api := c.OperatonAPI().ProcessDefinitionAPI
+		def, _, err := api.GetProcessDefinition(c.AuthContext(), definitionID).Execute()
+		if err != nil {
+			msg.err = fmt.Errorf("process definition: %w", err)
+			return msg
+		}
+		key := client.GetStringValue(def.Key)
+		msg.title = fmt.Sprintf("%s v%d", key, client.GetInt32Value(def.Version))
+		diagram, _, err := api.GetProcessDefinitionBpmn20Xml(c.AuthContext(), definitionID).Execute()
+		if err != nil {
+			msg.err = fmt.Errorf("BPMN XML: %w", err)
+			return msg
+		}
+		proc, err := bpmn.Parse([]byte(client.GetStringValue(diagram.Bpmn20Xml)), key)
+		if err != nil {
+			msg.err = err
+			return msg
+		}
+		msg.lines = proc.Lines()
+
+		if instanceID != "" {
+			msg.title += " — instance " + instanceID
+			tree, _, err := c.OperatonAPI().ProcessInstanceAPI.GetActivityInstanceTree(c.AuthContext(), instanceID).Execute()
+			if err != nil {
+				msg.err = fmt.Errorf("activity instances: %w", err)
+				return msg
+			}
+			countActivityInstances(*tree, msg.active, msg.incidents)
+			return msg
+		}
+		stats, _, err := api.GetActivityStatistics(c.AuthContext(), definitionID).Incidents(true).Execute()
+		if err != nil {
+			msg.err = fmt.Errorf("activity statistics: %w", err)
+			return msg
+		}
+		for _, s := range stats {
+			id := client.GetStringValue(s.Id)
+			if s.Instances != nil {
+				msg.active[id] += int(*s.Instances)
+			}
+			for _, inc := range s.Incidents {
+				msg.incidents[id] += int(client.GetInt32Value(inc.IncidentCount))
+			}
+		}
+		return msg
+	}
+}
+
+// openDiagram opens the diagram of the selected process definition, or of the
+// selected process instance's definition with that instance's activities highlighted.
+func (m *model) openDiagram() tea.Cmd {
+	definitionID, instanceID := m.resolveActionID(config.ActionDef{}), ""
+	switch m.currentRoot {
+	case "process-instance", "process-instances":
+		instanceID = definitionID
+		definitionID = m.resolveActionID(config.ActionDef{IDColumn: "definitionId"})
+	}
+	if definitionID == "" {
+		return nil
+	}
+	m.diagram = diagramState{definitionID: definitionID, instanceID: instanceID, title: definitionID, loading: true}
+	m.activeModal = ModalDiagram
+	return m.fetchDiagramCmd(definitionID, instanceID)
+}
+
+// applyDiagram stores a loaded diagram if it is still the one being shown.
+func (m *model) applyDiagram(msg diagramLoadedMsg) {
+	if m.activeModal != ModalDiagram || msg.definitionID != m.diagram.definitionID || msg.instanceID != m.diagram.instanceID {
+		return
+	}
+	m.diagram.loading = false
+	if msg.title != "" {
+		m.diagram.title = msg.title
+	}
+	m.diagram.lines = msg.lines
+	m.diagram.active = msg.active
+	m.diagram.incidents = msg.incidents
+	if msg.err != nil {
+		m.diagram.err = friendlyError(m.currentEnv, msg.err)
+	}
+	// Start at the first active activity so the waiting position is visible
+	m.diagram.scroll = 0
+	for i, l := range msg.lines {
+		if l.NodeID != "" && msg.active[l.NodeID] > 0 {
+			m.diagram.scroll = i
+			break
+		}
+	}
+}
+
+// handleDiagramKey processes a key press while ModalDiagram is open.
+func (m *model) handleDiagramKey(msg tea.KeyMsg) tea.Cmd {
+	page := m.diagramViewHeight()
+	switch msg.String() {
+	case "esc", "q":
+		m.activeModal = ModalNone
+		m.diagram = diagramState{}
+	case "down", "j":
+		m.diagram.scroll++
+	case "up", "k":
+		m.diagram.scroll--
+	case "pgdown", "ctrl+d":
+		m.diagram.scroll += page
+	case "pgup", "ctrl+u":
+		m.diagram.scroll -= page
+	case "home", "g":
+		m.diagram.scroll = 0
+	case "end", "G":
+		m.diagram.scroll = len(m.diagram.lines)
+	case "r":
+		m.diagram.loading = true
+		m.diagram.err = ""
+		return m.fetchDiagramCmd(m.diagram.definitionID, m.diagram.instanceID)
+	}
+	m.clampDiagramScroll()
+	return nil
+}
+
+// diagramViewHeight is the number of diagram lines shown at once.
+func (m *model) diagramViewHeight() int {
+	h := m.lastHeight - 12
+	if h < 3 {
+		h = 3
+	}
+	return h
+}
+
+func (m *model) clampDiagramScroll() {
+	maxScroll := len(m.diagram.lines) - m.diagramViewHeight()
+	if m.diagram.scroll > maxScroll {
+		m.diagram.scroll = maxScroll
+	}
+	if m.diagram.scroll < 0 {
+		m.diagram.scroll = 0
+	}
+}
+
+// modalDiagramBody renders the diagram with active activities highlighted and
+// their instance and incident counts appended.
+func (m *model) modalDiagramBody() string {
+	var b strings.Builder
+	b.WriteString(m.styles.Accent.Render(m.diagram.title) + "\n")
+	legend := "● active instances  ⚠ incidents"
+	if m.diagram.instanceID != "" {
+		legend = "● active in this instance  ⚠ incidents"
+	}
+	b.WriteString(m.styles.FgMuted.Render(legend) + "\n\n")
+	if m.diagram.loading {
+		b.WriteString("Loading diagram…\n")
+	}
+	if m.diagram.err != "" {
+		b.WriteString(m.styles.ValidationError.Render(m.diagram.err) + "\n")
+	}
+
+	m.clampDiagramScroll()
+	end := m.diagram.scroll + m.diagramViewHeight()
+	if end > len(m.diagram.lines) {
+		end = len(m.diagram.lines)
+	}
+	for _, l := range m.diagram.lines[m.diagram.scroll:end] {
+		if l.NodeID == "" {
+			b.WriteString(m.styles.FgMuted.Render(l.Text) + "\n")
+			continue
+		}
+		line := l.Text
+		active, incidents := m.diagram.active[l.NodeID], m.diagram.incidents[l.NodeID]
+		switch {
+		case incidents > 0:
+			line = m.styles.ValidationError.Render(line)
+		case active > 0:
+			line = m.styles.PopupCursor.Render(line)
+		}
+		if active > 0 {
+			line += "  " + m.styles.Accent.Render(fmt.Sprintf("● %d", active))
+		}
+		if incidents > 0 {
+			line += "  " + m.styles.ValidationError.Render(fmt.Sprintf("⚠ %d", incidents))
+		}
+		b.WriteString(line + "\n")
+	}
+	if len(m.diagram.lines) > m.diagramViewHeight() {
+		b.WriteString(m.styles.FgMuted.Render(fmt.Sprintf("\n%d–%d of %d lines", m.diagram.scroll+1, end, len(m.diagram.lines))))
+	}
+	return b.String()
+}
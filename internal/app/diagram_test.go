@@ -0,0 +1,103 @@
+package app
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/bubbles/table"
+)
+
+const diagramTestXML = `<definitions xmlns="http://www.omg.org/spec/BPMN/20100524/MODEL">
+  <process id="invoice" isExecutable="true">
+    <startEvent id="start"/>
+    <sequenceFlow id="f1" sourceRef="start" targetRef="review"/>
+    <userTask id="review" name="Review"/>
+    <sequenceFlow id="f2" sourceRef="review" targetRef="book"/>
+    <serviceTask id="book" name="Book"/>
+    <sequenceFlow id="f3" sourceRef="book" targetRef="end"/>
+    <endEvent id="end"/>
+  </process>
+</definitions>`
+
+func diagramServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/process-definition/invoice:1":
+			_, _ = w.Write([]byte(`{"id":"invoice:1","key":"invoice","version":1}`))
+		case "/process-definition/invoice:1/xml":
+			body, _ := json.Marshal(map[string]string{"id": "invoice:1", "bpmn20Xml": diagramTestXML})
+			_, _ = w.Write(body)
+		case "/process-definition/invoice:1/statistics":
+			if r.URL.Query().Get("incidents") != "true" {
+				t.Errorf("expected incidents=true, got %v", r.URL.Query())
+			}
+			_, _ = w.Write([]byte(`[{"id":"review","instances":3,"incidents":[]},{"id":"book","instances":2,"incidents":[{"incidentType":"failedJob","incidentCount":2}]}]`))
+		case "/process-instance/pi-1/activity-instances":
+			_, _ = w.Write([]byte(`{"id":"pi-1","activityId":"invoice:1","childActivityInstances":[{"id":"review:1","activityId":"review"}],
+				"childTransitionInstances":[{"id":"ti-1","activityId":"book","incidentIds":["inc-1"]}]}`))
+		default:
+			t.Errorf("unexpected request %s", r.URL.Path)
+		}
+	}))
+}
+
+func TestDiagramOverlaysDefinitionStatistics(t *testing.T) {
+	srv := diagramServer(t)
+	defer srv.Close()
+
+	m := tableTestModel(t, srv.URL, "process-definition", []string{"id"}, []table.Row{{"invoice:1"}})
+
+	var item actionItem
+	for _, it := range m.buildActionsForRoot() {
+		if it.key == "g" {
+			item = it
+		}
+	}
+	if item.cmd == nil {
+		t.Fatal("expected Diagram in the process-definition actions")
+	}
+	loaded := findMsg[diagramLoadedMsg](t, item.cmd(&m))
+	if loaded.err != nil {
+		t.Fatalf("load failed: %v", loaded.err)
+	}
+	res, _ := m.Update(loaded)
+	m = res.(model)
+	if m.activeModal != ModalDiagram || m.diagram.active["review"] != 3 || m.diagram.incidents["book"] != 2 {
+		t.Fatalf("unexpected overlay active=%v incidents=%v", m.diagram.active, m.diagram.incidents)
+	}
+	body := m.modalDiagramBody()
+	for _, want := range []string{"invoice v1", "▭ Review", "● 3", "⚠ 2"} {
+		if !strings.Contains(body, want) {
+			t.Errorf("expected %q in diagram, got %q", want, body)
+		}
+	}
+
+	m, _ = sendKeyString(m, "esc")
+	if m.activeModal != ModalNone {
+		t.Error("expected Esc to close the diagram")
+	}
+}
+
+func TestDiagramOverlaysInstanceActivities(t *testing.T) {
+	srv := diagramServer(t)
+	defer srv.Close()
+
+	m := tableTestModel(t, srv.URL, "process-instance", []string{"id", "definitionId"}, migrationTestRows)
+	m.openDiagram()
+	if m.diagram.instanceID != "pi-1" || m.diagram.definitionID != "invoice:1" {
+		t.Fatalf("expected instance diagram of invoice:1, got %+v", m.diagram)
+	}
+	loaded := findMsg[diagramLoadedMsg](t, m.fetchDiagramCmd("invoice:1", "pi-1"))
+	m.applyDiagram(loaded)
+	if m.diagram.active["review"] != 1 || m.diagram.active["book"] != 1 || m.diagram.incidents["book"] != 1 {
+		t.Errorf("unexpected overlay active=%v incidents=%v", m.diagram.active, m.diagram.incidents)
+	}
+	if !strings.Contains(m.diagram.title, "instance pi-1") {
+		t.Errorf("expected instance in title, got %q", m.diagram.title)
+	}
+}
@@ -9,8 +9,10 @@ import (
 	"os"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/charmbracelet/bubbles/table"
+	tea "github.com/charmbracelet/bubbletea"
 	"github.com/kthoms/o6n/internal/config"
 )
 
@@ -306,3 +308,32 @@ func TestFetchGenericCmd_NoDebugNoLog(t *testing.T) {
 		t.Errorf("AC-9: expected no [http] log when debugEnabled=false, got: %s", buf.String())
 	}
 }
+
+func TestFetchGenericCmd_CancelledByNavigation(t *testing.T) {
+	started := make(chan struct{}, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		started <- struct{}{}
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+
+	cfg := &config.Config{
+		Environments: map[string]config.Environment{"local": {URL: server.URL}},
+		Tables:       []config.TableDef{{Name: "widget", Columns: []config.ColumnDef{{Name: "id"}}}},
+	}
+	m := newModel(cfg)
+	cmd := m.fetchGenericCmd("widget")
+	done := make(chan tea.Msg, 1)
+	go func() { done <- cmd() }()
+
+	<-started
+	m.prepareStateTransition(TransitionFull)
+	select {
+	case msg := <-done:
+		if msg != nil {
+			t.Errorf("expected no message for a fetch cancelled by navigation, got %T", msg)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("fetch was not cancelled by navigation")
+	}
+}
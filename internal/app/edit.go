@@ -1,10 +1,13 @@
 package app
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
 	"strings"
 
 	"github.com/charmbracelet/bubbles/table"
+	tea "github.com/charmbracelet/bubbletea"
 	"github.com/kthoms/o6n/internal/config"
 	"github.com/kthoms/o6n/internal/validation"
 )
@@ -34,6 +37,9 @@ func (m *model) hasEditableColumns() bool {
 
 func inputTypeFromVariableType(variableType string) string {
 	lower := strings.ToLower(variableType)
+	if lower == "json" || lower == "xml" {
+		return lower
+	}
 	if strings.Contains(lower, "bool") {
 		return "bool"
 	}
@@ -57,6 +63,10 @@ func typeNameForInputType(inputType string, variableType string) string {
 		return "Integer"
 	case "number":
 		return "Double"
+	case "json":
+		return "Json"
+	case "xml":
+		return "Xml"
 	default:
 		return "String"
 	}
@@ -133,6 +143,7 @@ func (m *model) setEditColumn(pos int) {
 	}
 	m.editColumnPos = pos
 	m.editError = ""
+	m.editExternal = ""
 
 	row := m.currentEditRow()
 	col := m.currentEditColumn()
@@ -177,3 +188,97 @@ func (m *model) startEdit(tableKey string) string {
 	m.activeModal = ModalEdit
 	return ""
 }
+
+// saveEdit validates raw for the current edit cell and returns the command that
+// saves it through the table's EditAction or, for variable tables, through
+// SetProcessInstanceVariable. The modal is closed on success; on failure
+// m.editError is set and nil is returned.
+func (m *model) saveEdit(raw string) tea.Cmd {
+	row := m.currentEditRow()
+	col := m.currentEditColumn()
+	if row == nil || col == nil {
+		m.editError = "No selection"
+		return nil
+	}
+	inputType, typeName := m.resolveEditTypes(col.def, m.editTableKey, row)
+	parsedValue, err := parseInputValue(raw, inputType)
+	if err != nil {
+		m.editError = err.Error()
+		return nil
+	}
+	if inputType == "json" || inputType == "xml" {
+		// the engine stores Json and Xml values as serialized strings
+		parsedValue = strings.TrimSpace(raw)
+	}
+	rowIndex := m.editRowIndex
+	colIndex := col.index
+	displayValue := singleLineValue(raw, inputType)
+	varName := m.variableNameForRow(m.editTableKey, row)
+
+	// Generic save via EditAction config (preferred)
+	if def := m.findTableDef(m.editTableKey); def != nil && def.EditAction != nil {
+		act := *def.EditAction
+		idCol := act.IDColumn
+		if idCol == "" {
+			idCol = "id"
+		}
+		nameCol := act.NameColumn
+		if nameCol == "" {
+			nameCol = "name"
+		}
+		id := m.resolveRowValue(row, idCol)
+		name := m.resolveRowValue(row, nameCol)
+		parentID := m.selectedInstanceID
+		return m.guardMutation("Save "+col.def.Name, id, ModalEdit, func(m *model) tea.Cmd {
+			m.closeEdit()
+			return tea.Batch(m.executeEditActionCmd(act, id, name, parentID, fmt.Sprintf("%v", parsedValue), typeName, rowIndex, colIndex, displayValue, varName), flashOnCmd())
+		})
+	}
+	// Fallback: legacy variable table save
+	if isVariableTable(m.editTableKey) {
+		if varName == "" {
+			m.editError = "Variable name not found"
+			return nil
+		}
+		instanceID := m.selectedInstanceID
+		return m.guardMutation("Save variable "+varName, varName, ModalEdit, func(m *model) tea.Cmd {
+			m.closeEdit()
+			return tea.Batch(m.setVariableCmd(instanceID, varName, parsedValue, typeName, rowIndex, colIndex, displayValue, varName), flashOnCmd())
+		})
+	}
+	m.editError = "Editing not supported for this table"
+	return nil
+}
+
+// currentEditValue is the value being edited: the input, or the full value from
+// the external editor as long as the input still shows it unchanged.
+func (m *model) currentEditValue(inputType string) string {
+	value := m.editInput.Value()
+	if m.editExternal != "" && value == singleLineValue(m.editExternal, inputType) {
+		return m.editExternal
+	}
+	return value
+}
+
+// closeEdit dismisses the edit modal.
+func (m *model) closeEdit() {
+	m.activeModal = ModalNone
+	m.editError = ""
+	m.editExternal = ""
+	m.editInput.Blur()
+}
+
+// singleLineValue flattens a multi-line value for the table cell and the
+// single-line input; JSON is compacted.
+func singleLineValue(raw, inputType string) string {
+	if !strings.Contains(raw, "\n") {
+		return raw
+	}
+	if inputType == "json" {
+		var buf bytes.Buffer
+		if err := json.Compact(&buf, []byte(raw)); err == nil {
+			return buf.String()
+		}
+	}
+	return strings.Join(strings.Fields(raw), " ")
+}
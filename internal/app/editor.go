@@ -0,0 +1,116 @@
+package app
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// editorFinishedMsg is sent when the external editor started from ModalEdit exits.
+type editorFinishedMsg struct {
+	path     string
+	original string
+	err      error
+}
+
+// editorCommand returns the user's editor from $VISUAL or $EDITOR, falling back
+// to vi. Arguments are allowed, e.g. EDITOR="code --wait".
+func editorCommand() []string {
+	for _, name := range []string{"VISUAL", "EDITOR"} {
+		if fields := strings.Fields(os.Getenv(name)); len(fields) > 0 {
+			return fields
+		}
+	}
+	return []string{"vi"}
+}
+
+// editorFileSuffix picks a file extension so the editor highlights the value.
+func editorFileSuffix(inputType string) string {
+	switch inputType {
+	case "json":
+		return ".json"
+	case "xml":
+		return ".xml"
+	default:
+		return ".txt"
+	}
+}
+
+// openExternalEditor writes the value being edited to a temp file and suspends
+// the TUI to edit it in the external editor. JSON is pretty-printed first.
+func (m *model) openExternalEditor() tea.Cmd {
+	row := m.currentEditRow()
+	col := m.currentEditColumn()
+	if row == nil || col == nil {
+		m.editError = "No selection"
+		return nil
+	}
+	inputType, _ := m.resolveEditTypes(col.def, m.editTableKey, row)
+	value := m.currentEditValue(inputType)
+	if inputType == "json" {
+		var buf bytes.Buffer
+		if err := json.Indent(&buf, []byte(value), "", "  "); err == nil {
+			value = buf.String()
+		}
+	}
+	f, err := os.CreateTemp("", "o6n-edit-*"+editorFileSuffix(inputType))
+	if err != nil {
+		m.editError = fmt.Sprintf("temp file: %v", err)
+		return nil
+	}
+	_, err = f.WriteString(value + "\n")
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(f.Name())
+		m.editError = fmt.Sprintf("temp file: %v", err)
+		return nil
+	}
+	argv := append(editorCommand(), f.Name())
+	path := f.Name()
+	return tea.ExecProcess(exec.Command(argv[0], argv[1:]...), func(err error) tea.Msg {
+		return editorFinishedMsg{path: path, original: value, err: err}
+	})
+}
+
+// finishExternalEdit reads back the edited temp file and saves the value like
+// the inline editor does. An invalid value keeps the modal open with the error,
+// and the edited text is kept for the next round in the editor.
+func (m *model) finishExternalEdit(msg editorFinishedMsg) tea.Cmd {
+	data, readErr := os.ReadFile(msg.path)
+	os.Remove(msg.path)
+	if m.activeModal != ModalEdit {
+		return nil
+	}
+	if msg.err != nil {
+		m.editError = fmt.Sprintf("editor: %v", msg.err)
+		return nil
+	}
+	if readErr != nil {
+		m.editError = fmt.Sprintf("editor: %v", readErr)
+		return nil
+	}
+	// Editors add a final newline; it is never part of the value.
+	value := strings.TrimRight(string(data), "\r\n")
+	if value == msg.original {
+		m.editError = ""
+		return nil
+	}
+	row := m.currentEditRow()
+	col := m.currentEditColumn()
+	if row == nil || col == nil {
+		m.editError = "No selection"
+		return nil
+	}
+	inputType, _ := m.resolveEditTypes(col.def, m.editTableKey, row)
+	m.editExternal = value
+	m.editInput.SetValue(singleLineValue(value, inputType))
+	m.editInput.CursorEnd()
+	return m.saveEdit(value)
+}
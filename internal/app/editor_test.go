@@ -0,0 +1,126 @@
+package app
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/bubbles/table"
+	"github.com/kthoms/o6n/internal/config"
+)
+
+// externalEditTestModel opens ModalEdit on a Json process variable served by url.
+func externalEditTestModel(t *testing.T, url string) model {
+	t.Helper()
+	m := newModel(&config.Config{
+		Environments: map[string]config.Environment{"local": {URL: url}},
+		Tables: []config.TableDef{{
+			Name: "process-variables",
+			Columns: []config.ColumnDef{
+				{Name: "name"},
+				{Name: "value", Editable: true, InputType: "auto"},
+			},
+		}},
+	})
+	m.currentEnv = "local"
+	m.selectedInstanceID = "pi-1"
+	m.variablesByName = map[string]config.Variable{"order": {Name: "order", Type: "Json"}}
+	m.table.SetColumns([]table.Column{{Title: "name", Width: 10}, {Title: "value", Width: 30}})
+	m.table.SetRows([]table.Row{{"order", `{"id":7,"items":["a"]}`}})
+	if err := m.startEdit("process-variables"); err != "" {
+		t.Fatalf("startEdit: %s", err)
+	}
+	return m
+}
+
+func TestEditorCommandPrefersVisual(t *testing.T) {
+	t.Setenv("VISUAL", "")
+	t.Setenv("EDITOR", "code --wait")
+	if got := editorCommand(); strings.Join(got, " ") != "code --wait" {
+		t.Errorf("expected $EDITOR with arguments, got %v", got)
+	}
+	t.Setenv("VISUAL", "nano")
+	if got := editorCommand(); len(got) != 1 || got[0] != "nano" {
+		t.Errorf("expected $VISUAL, got %v", got)
+	}
+	t.Setenv("VISUAL", "")
+	t.Setenv("EDITOR", "")
+	if got := editorCommand(); got[0] != "vi" {
+		t.Errorf("expected vi fallback, got %v", got)
+	}
+}
+
+func TestExternalEditSavesJsonVariable(t *testing.T) {
+	var saved map[string]interface{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPut || r.URL.Path != "/process-instance/pi-1/variables/order" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		body, _ := io.ReadAll(r.Body)
+		_ = json.Unmarshal(body, &saved)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	tmp := t.TempDir()
+	t.Setenv("TMPDIR", tmp)
+	m := externalEditTestModel(t, srv.URL)
+	if cmd := m.openExternalEditor(); cmd == nil {
+		t.Fatalf("expected an exec command, editError=%q", m.editError)
+	}
+	files, _ := filepath.Glob(filepath.Join(tmp, "o6n-edit-*.json"))
+	if len(files) != 1 {
+		t.Fatalf("expected one temp file, got %v", files)
+	}
+	written, _ := os.ReadFile(files[0])
+	pretty := "{\n  \"id\": 7,\n  \"items\": [\n    \"a\"\n  ]\n}"
+	if string(written) != pretty+"\n" {
+		t.Fatalf("expected pretty-printed JSON, got %q", written)
+	}
+	msg := editorFinishedMsg{path: files[0], original: pretty}
+
+	// An invalid document keeps the modal open and the edited text for the next round
+	if err := os.WriteFile(msg.path, []byte("{\n  \"id\": 8,\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	res, cmd := m.Update(msg)
+	m = res.(model)
+	if cmd != nil || m.activeModal != ModalEdit || !strings.Contains(m.editError, "invalid json") {
+		t.Fatalf("expected json validation error, got modal %v error %q", m.activeModal, m.editError)
+	}
+	if _, err := os.Stat(msg.path); !os.IsNotExist(err) {
+		t.Error("expected the temp file to be removed")
+	}
+
+	if err := os.WriteFile(msg.path, []byte("{\n  \"id\": 8,\n  \"items\": [\"a\", \"b\"]\n}\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	res, cmd = m.Update(msg)
+	m = res.(model)
+	if cmd == nil || m.activeModal != ModalNone {
+		t.Fatalf("expected save after a valid edit, error %q", m.editError)
+	}
+	done := findMsg[editSavedMsg](t, cmd)
+	if done.value != `{"id":8,"items":["a","b"]}` {
+		t.Errorf("expected compacted cell value, got %q", done.value)
+	}
+	if saved["type"] != "Json" || !strings.Contains(saved["value"].(string), "\n  \"id\": 8") {
+		t.Errorf("expected Json variable with the edited text, got %v", saved)
+	}
+}
+
+func TestExternalEditUnchangedKeepsModal(t *testing.T) {
+	m := externalEditTestModel(t, "http://localhost:1")
+	path := t.TempDir() + "/value.txt"
+	if err := os.WriteFile(path, []byte("same\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if cmd := m.finishExternalEdit(editorFinishedMsg{path: path, original: "same"}); cmd != nil || m.activeModal != ModalEdit {
+		t.Errorf("expected no save for an unchanged value, modal %v", m.activeModal)
+	}
+}
@@ -0,0 +1,129 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/kthoms/o6n/internal/config"
+)
+
+// envMode returns the mode of the active environment.
+func (m *model) envMode() string {
+	if m.config != nil {
+		if env, ok := m.config.Environments[m.currentEnv]; ok && env.Mode != "" {
+			return env.Mode
+		}
+	}
+	return config.ModeNormal
+}
+
+func (m *model) readOnly() bool  { return m.envMode() == config.ModeReadOnly }
+func (m *model) protected() bool { return m.envMode() == config.ModeProtected }
+
+// blockReadOnly refuses what in a read-only environment and reports it in the
+// footer. It returns false when the environment allows changes.
+func (m *model) blockReadOnly(what string) (tea.Cmd, bool) {
+	if !m.readOnly() {
+		return nil, false
+	}
+	msg, kind, cmd := setFooterStatus(footerStatusError,
+		fmt.Sprintf("%s is read-only — %s is disabled", m.currentEnv, what), 5*time.Second)
+	m.footerError = msg
+	m.footerStatusKind = kind
+	return cmd, true
+}
+
+// guardMutation runs a change that has no confirmation of its own. Read-only
+// environments refuse it; protected environments ask for a typed confirmation
+// first and return to returnTo afterwards. id is accepted as confirmation in
+// addition to the environment name.
+func (m *model) guardMutation(label, id string, returnTo ModalType, run func(m *model) tea.Cmd) tea.Cmd {
+	if cmd, blocked := m.blockReadOnly(strings.ToLower(label)); blocked {
+		return cmd
+	}
+	if !m.protected() {
+		return run(m)
+	}
+	m.pendingMutation = run
+	m.pendingMutationLabel = label
+	m.pendingMutationID = id
+	m.pendingMutationReturn = returnTo
+	m.confirmTyped = ""
+	m.confirmFocusedBtn = 0
+	m.activeModal = ModalConfirmDelete
+	return nil
+}
+
+// confirmationTyped reports whether the text typed into ModalConfirmDelete
+// confirms the pending change: always outside protected environments, otherwise
+// when it is the environment name or the ID of the affected resource.
+func (m *model) confirmationTyped() bool {
+	if !m.protected() {
+		return true
+	}
+	typed := strings.TrimSpace(m.confirmTyped)
+	if typed == "" {
+		return false
+	}
+	if typed == m.currentEnv {
+		return true
+	}
+	for _, id := range []string{m.pendingMutationID, m.pendingActionID, m.pendingDeleteID} {
+		if id != "" && typed == id {
+			return true
+		}
+	}
+	return false
+}
+
+// confirmationTarget describes what may be typed to confirm.
+func (m *model) confirmationTarget() string {
+	for _, id := range []string{m.pendingMutationID, m.pendingActionID, m.pendingDeleteID} {
+		if id != "" {
+			return fmt.Sprintf("%q or %q", m.currentEnv, id)
+		}
+	}
+	return fmt.Sprintf("%q", m.currentEnv)
+}
+
+// renderProtectedPrompt renders the typed confirmation of a protected
+// environment, shown above the confirmation buttons.
+func (m *model) renderProtectedPrompt() string {
+	if !m.protected() {
+		return ""
+	}
+	warn := lipgloss.NewStyle().Bold(true).Foreground(col(m.skin, "danger"))
+	var b strings.Builder
+	b.WriteString(warn.Render("🔒 PROTECTED ENVIRONMENT "+m.currentEnv) + "\n")
+	fmt.Fprintf(&b, "Type %s and press Enter (Esc cancels): %s▌\n\n", m.confirmationTarget(), m.confirmTyped)
+	return b.String()
+}
+
+// renderPendingMutationBody renders the confirmation of a guarded change.
+func (m *model) renderPendingMutationBody() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "⚠️  %s\n\n", strings.ToUpper(m.pendingMutationLabel))
+	fmt.Fprintf(&b, "Environment:   %s\n", m.currentEnv)
+	if m.pendingMutationID != "" {
+		fmt.Fprintf(&b, "ID:            %s\n", m.pendingMutationID)
+	}
+	return b.String()
+}
+
+// renderModeBadge renders the header badge of a read-only or protected
+// environment; normal environments have none.
+func (m *model) renderModeBadge() string {
+	var role, text string
+	switch m.envMode() {
+	case config.ModeReadOnly:
+		role, text = "warning", " READ-ONLY "
+	case config.ModeProtected:
+		role, text = "danger", " PROTECTED "
+	default:
+		return ""
+	}
+	return lipgloss.NewStyle().Bold(true).Reverse(true).Foreground(col(m.skin, role)).Render(text)
+}
@@ -0,0 +1,86 @@
+package app
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/bubbles/table"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/kthoms/o6n/internal/config"
+)
+
+// modeTestModel returns a process-instance view with one row in an environment
+// of the given mode.
+func modeTestModel(t *testing.T, mode string) model {
+	t.Helper()
+	m := tableTestModel(t, "http://localhost:8080", "process-instance", []string{"id"}, []table.Row{{"inst-1"}})
+	env := m.config.Environments["local"]
+	env.Mode = mode
+	m.config.Environments["local"] = env
+	return m
+}
+
+func TestReadOnlyEnvironmentHidesAndBlocksChanges(t *testing.T) {
+	m := modeTestModel(t, config.ModeReadOnly)
+
+	for _, item := range m.buildActionsForRoot() {
+		if item.mutates {
+			t.Errorf("expected no changing actions in a read-only environment, got %q", item.label)
+		}
+	}
+	labels := []string{}
+	for _, item := range m.buildActionsForRoot() {
+		labels = append(labels, item.label)
+	}
+	if got := strings.Join(labels, ","); !strings.Contains(got, "Diagram") || !strings.Contains(got, "View as JSON") {
+		t.Errorf("expected read-only actions to stay available, got %s", got)
+	}
+
+	m2, _ := sendKeyString(m, "ctrl+d")
+	if m2.activeModal == ModalConfirmDelete || !strings.Contains(m2.footerError, "read-only") {
+		t.Errorf("expected delete to be refused, got modal %v footer %q", m2.activeModal, m2.footerError)
+	}
+	if !strings.Contains(m.renderCompactHeader(120), "READ-ONLY") {
+		t.Error("expected the READ-ONLY badge in the header")
+	}
+}
+
+func TestProtectedEnvironmentRequiresTypedConfirmation(t *testing.T) {
+	m := modeTestModel(t, config.ModeProtected)
+
+	// An action without confirm: true is confirmed in a protected environment
+	var suspend actionItem
+	for _, item := range m.buildActionsForRoot() {
+		if item.key == "s" {
+			suspend = item
+		}
+	}
+	if cmd := suspend.cmd(&m); cmd != nil || m.activeModal != ModalConfirmDelete {
+		t.Fatalf("expected the confirmation modal instead of running the action, got modal %v", m.activeModal)
+	}
+	if out := m.renderConfirmDeleteModal(0, 0); !strings.Contains(out, "PROTECTED ENVIRONMENT local") {
+		t.Errorf("expected the typed confirmation prompt, got:\n%s", out)
+	}
+
+	m2, cmd := sendKeyString(m, "enter")
+	if cmd != nil || m2.activeModal != ModalConfirmDelete {
+		t.Fatal("expected Enter without the typed confirmation to be refused")
+	}
+	for _, r := range "inst-1" {
+		m2, _ = sendKeyString(m2, string(r))
+	}
+	m3, cmd := sendKeyString(m2, "enter")
+	if cmd == nil || m3.activeModal != ModalNone || m3.pendingAction != nil {
+		t.Errorf("expected the typed resource ID to confirm the action, got modal %v", m3.activeModal)
+	}
+
+	// A guarded change returns to where it was started when cancelled
+	ran := false
+	m.activeModal = ModalEdit
+	m.pendingAction = nil
+	m.guardMutation("Save value", "v1", ModalEdit, func(*model) tea.Cmd { ran = true; return nil })
+	m4, _ := sendKeyString(m, "esc")
+	if ran || m4.activeModal != ModalEdit || m4.pendingMutation != nil {
+		t.Errorf("expected cancel to return to the edit modal without saving, got modal %v ran %v", m4.activeModal, ran)
+	}
+}
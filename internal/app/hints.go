@@ -62,6 +62,7 @@ func tableViewHints(m model) []Hint {
 		{Key: "Ctrl+e", Label: "env", MinWidth: 90, Priority: 6},
 		{Key: "Ctrl+Space", Label: "actions", MinWidth: 100, Priority: 6},
 		{Key: "J", Label: "json", MinWidth: 112, Priority: 6},
+		{Key: "Space/A", Label: "mark", MinWidth: 120, Priority: 7},
 		{Key: "Ctrl+c", Label: "quit", MinWidth: 110, Priority: 8},
 	}
 
@@ -90,7 +91,9 @@ func tableViewHints(m model) []Hint {
 		}
 	}
 
-	if len(m.navigationStack) > 0 {
+	if len(m.markedRows) > 0 {
+		hints = append(hints, Hint{Key: "Esc", Label: "unmark", MinWidth: 0, Priority: 5})
+	} else if len(m.navigationStack) > 0 {
 		hints = append(hints, Hint{Key: "Esc", Label: "back", MinWidth: 0, Priority: 5})
 	}
 	if len(m.breadcrumb) > 1 {
@@ -57,6 +57,29 @@ func newTestModel(t *testing.T) model {
 	return newModel(cfg)
 }
 
+// tableTestModel builds a model showing rows of root on the "local" environment
+// at url, with the tables and actions of testConfigWithActions. Column titles
+// are upper-cased as buildColumnsFor renders them.
+func tableTestModel(t *testing.T, url, root string, cols []string, rows []table.Row) model {
+	t.Helper()
+	m := newTestModel(t)
+	m.config = testConfigWithActions()
+	m.config.Environments["local"] = config.Environment{URL: url}
+	m.currentEnv = "local"
+	m.splashActive = false
+	m.currentRoot = root
+	m.viewMode = root
+	m.breadcrumb = []string{root}
+	columns := make([]table.Column, len(cols))
+	for i, c := range cols {
+		columns[i] = table.Column{Title: strings.ToUpper(c), Width: 20}
+	}
+	m.table.SetColumns(columns)
+	m.table.SetRows(rows)
+	m.table.SetCursor(0)
+	return m
+}
+
 // sendKeyString feeds a raw key string through the switch in Update.
 // It wraps the string in a tea.KeyMsg by setting the internal field via
 // casting, which isn't possible — instead we use the public API approach:
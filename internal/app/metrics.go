@@ -0,0 +1,395 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/kthoms/o6n/internal/apispec"
+	"github.com/kthoms/o6n/internal/client"
+	"github.com/kthoms/o6n/internal/config"
+)
+
+// metricNames are the engine metrics shown by the metrics view, job executor
+// first.
+var metricNames = []string{
+	"job-successful",
+	"job-failed",
+	"job-acquisition-attempt",
+	"job-acquired-success",
+	"job-acquired-failure",
+	"job-execution-rejected",
+	"job-locked-exclusive",
+	"activity-instance-start",
+	"activity-instance-end",
+	"root-process-instance-start",
+	"executed-decision-elements",
+	"executed-decision-instances",
+}
+
+// metricsRanges are the selectable time ranges of the metrics view.
+var metricsRanges = []time.Duration{time.Hour, 6 * time.Hour, 24 * time.Hour, 7 * 24 * time.Hour}
+
+// sparkBlocks are the levels of a sparkline, lowest first.
+var sparkBlocks = []rune("▁▂▃▄▅▆▇█")
+
+// metricSeries is one metric of one environment in buckets of the interval,
+// oldest first.
+type metricSeries struct {
+	name   string
+	env    string
+	values []int64
+	total  int64
+	err    string
+}
+
+// metricsState holds the engine metrics view (ModalMetrics).
+type metricsState struct {
+	gen       int // bumped per load; older results are dropped
+	rangeIdx  int
+	from      time.Time
+	interval  time.Duration
+	series    []metricSeries // metricNames in the current environment
+	compare   []metricSeries // the selected metric in every environment
+	comparing bool
+	chart     bool // bar chart of the selected metric
+	cursor    int
+	loading   bool
+}
+
+// metricsLoadedMsg delivers series loaded for the metrics view.
+type metricsLoadedMsg struct {
+	gen     int
+	compare bool
+	series  []metricSeries
+}
+
+// formatMetricsDuration renders a range or interval, e.g. "15m", "6h", "7d".
+func formatMetricsDuration(d time.Duration) string {
+	switch {
+	case d >= 24*time.Hour && d%(24*time.Hour) == 0:
+		return fmt.Sprintf("%dd", int(d.Hours())/24)
+	case d >= time.Hour && d%time.Hour == 0:
+		return fmt.Sprintf("%dh", int(d.Hours()))
+	default:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	}
+}
+
+// metricsBuckets returns the start of the first bucket and the interval to
+// cover span with points buckets, in whole minutes aligned to the interval
+// like the engine's aggregation.
+func metricsBuckets(now time.Time, span time.Duration, points int) (time.Time, time.Duration) {
+	if points < 1 {
+		points = 1
+	}
+	interval := (span/time.Duration(points) + time.Minute - 1).Truncate(time.Minute)
+	if interval < time.Minute {
+		interval = time.Minute
+	}
+	return now.Truncate(interval).Add(-time.Duration(points-1) * interval), interval
+}
+
+// metricsChartWidth is the number of buckets a sparkline has room for.
+func (m *model) metricsChartWidth() int {
+	w := int(float64(m.lastWidth)*0.80) - 6 - 2 - 30 - 22
+	if w < 10 {
+		w = 10
+	}
+	return w
+}
+
+// metricsChartHeight is the number of rows of the bar chart.
+func (m *model) metricsChartHeight() int {
+	h := m.lastHeight - 16
+	if h < 4 {
+		h = 4
+	}
+	return h
+}
+
+// fetchMetricSeries loads metric name of env aggregated over all reporters in
+// buckets of interval starting at from.
+func fetchMetricSeries(ctx context.Context, envName string, env config.Environment, name string, from time.Time, interval time.Duration, points int) metricSeries {
+	s := metricSeries{name: name, env: envName, values: make([]int64, points)}
+	q := url.Values{}
+	q.Set("name", name)
+	q.Set("startDate", from.Format(apispec.DateTimeLayout))
+	q.Set("endDate", from.Add(time.Duration(points)*interval).Format(apispec.DateTimeLayout))
+	q.Set("interval", strconv.Itoa(int(interval.Seconds())))
+	q.Set("aggregateByReporter", "true")
+	q.Set("maxResults", strconv.Itoa(points+1))
+	var rows []struct {
+		Timestamp interface{} `json:"timestamp"`
+		Value     int64       `json:"value"`
+	}
+	if err := client.GetJSON(ctx, env, strings.TrimRight(env.URL, "/")+"/metrics?"+q.Encode(), &rows); err != nil {
+		s.err = err.Error()
+		return s
+	}
+	for _, r := range rows {
+		ts, ok := parseOperatonTime(r.Timestamp)
+		if !ok {
+			continue
+		}
+		i := int(ts.Sub(from) / interval)
+		if i < 0 || i >= points {
+			continue
+		}
+		s.values[i] += r.Value
+		s.total += r.Value
+	}
+	return s
+}
+
+// openMetrics opens the metrics view and loads the current environment.
+func (m *model) openMetrics() tea.Cmd {
+	m.metrics.cursor = 0
+	m.metrics.comparing = false
+	m.metrics.chart = false
+	m.activeModal = ModalMetrics
+	return m.loadMetricsCmd()
+}
+
+// loadMetricsCmd (re)loads the metrics view for the selected range: every
+// metric of the current environment, or the selected metric in every
+// environment when comparing.
+func (m *model) loadMetricsCmd() tea.Cmd {
+	m.metrics.gen++
+	m.metrics.loading = true
+	gen := m.metrics.gen
+	points := m.metricsChartWidth()
+	from, interval := metricsBuckets(time.Now(), metricsRanges[m.metrics.rangeIdx], points)
+	m.metrics.from, m.metrics.interval = from, interval
+
+	if m.metrics.comparing {
+		name := metricNames[m.metrics.cursor]
+		m.metrics.compare = nil
+		var cmds []tea.Cmd
+		for _, envName := range m.envNames {
+			envName := envName
+			env, ok := m.config.Environments[envName]
+			if !ok {
+				continue
+			}
+			cmds = append(cmds, func() tea.Msg {
+				ctx, cancel := context.WithTimeout(context.Background(), env.RequestTimeout())
+				defer cancel()
+				return metricsLoadedMsg{gen: gen, compare: true, series: []metricSeries{fetchMetricSeries(ctx, envName, env, name, from, interval, points)}}
+			})
+		}
+		return tea.Batch(cmds...)
+	}
+
+	envName := m.currentEnv
+	env, ok := m.config.Environments[envName]
+	if !ok {
+		return nil
+	}
+	return func() tea.Msg {
+		msg := metricsLoadedMsg{gen: gen}
+		for _, name := range metricNames {
+			ctx, cancel := context.WithTimeout(context.Background(), env.RequestTimeout())
+			msg.series = append(msg.series, fetchMetricSeries(ctx, envName, env, name, from, interval, points))
+			cancel()
+		}
+		return msg
+	}
+}
+
+// applyMetrics stores loaded series of the current load.
+func (m *model) applyMetrics(msg metricsLoadedMsg) {
+	if msg.gen != m.metrics.gen {
+		return
+	}
+	if !msg.compare {
+		m.metrics.series = msg.series
+		m.metrics.loading = false
+		return
+	}
+	m.metrics.compare = append(m.metrics.compare, msg.series...)
+	m.metrics.loading = len(m.metrics.compare) < len(m.envNames)
+}
+
+// handleMetricsKey processes a key press while ModalMetrics is open.
+func (m *model) handleMetricsKey(msg tea.KeyMsg) tea.Cmd {
+	switch msg.String() {
+	case "esc", "q":
+		if m.metrics.chart {
+			m.metrics.chart = false
+			return nil
+		}
+		m.activeModal = ModalNone
+	case "down", "j":
+		if !m.metrics.comparing && m.metrics.cursor < len(metricNames)-1 {
+			m.metrics.cursor++
+		}
+	case "up", "k":
+		if !m.metrics.comparing && m.metrics.cursor > 0 {
+			m.metrics.cursor--
+		}
+	case "right", "l", "]":
+		if m.metrics.rangeIdx < len(metricsRanges)-1 {
+			m.metrics.rangeIdx++
+			return m.loadMetricsCmd()
+		}
+	case "left", "h", "[":
+		if m.metrics.rangeIdx > 0 {
+			m.metrics.rangeIdx--
+			return m.loadMetricsCmd()
+		}
+	case "enter", "b":
+		m.metrics.chart = !m.metrics.chart && !m.metrics.comparing
+	case "c":
+		m.metrics.comparing = !m.metrics.comparing
+		m.metrics.chart = false
+		return m.loadMetricsCmd()
+	case "r":
+		return m.loadMetricsCmd()
+	}
+	return nil
+}
+
+// sparkline renders values as one block per value, scaled to max.
+func sparkline(values []int64, max int64) string {
+	var b strings.Builder
+	for _, v := range values {
+		if v <= 0 || max <= 0 {
+			b.WriteRune(' ')
+			continue
+		}
+		b.WriteRune(sparkBlocks[int(v*int64(len(sparkBlocks)-1)/max)])
+	}
+	return b.String()
+}
+
+// barChart renders values as vertical bars of height rows scaled to their
+// maximum, top row first, one column per value.
+func barChart(values []int64, height int) []string {
+	var max int64
+	for _, v := range values {
+		if v > max {
+			max = v
+		}
+	}
+	rows := make([]string, height)
+	for r := 0; r < height; r++ {
+		var b strings.Builder
+		floor := int64(height-1-r) * 8 // eighths below this row
+		for _, v := range values {
+			eighths := int64(0)
+			if max > 0 {
+				eighths = v * int64(height) * 8 / max
+			}
+			switch fill := eighths - floor; {
+			case fill >= 8:
+				b.WriteRune('█')
+			case fill > 0:
+				b.WriteRune(sparkBlocks[fill-1])
+			default:
+				b.WriteRune(' ')
+			}
+		}
+		rows[r] = b.String()
+	}
+	return rows
+}
+
+// seriesMax returns the largest bucket of series.
+func seriesMax(series []metricSeries) int64 {
+	var max int64
+	for _, s := range series {
+		for _, v := range s.values {
+			if v > max {
+				max = v
+			}
+		}
+	}
+	return max
+}
+
+// renderMetricRow renders a series as label, sparkline scaled to max, total
+// and last bucket.
+func (m *model) renderMetricRow(label string, s metricSeries, max int64, selected bool) string {
+	label = fmt.Sprintf("%-30s", ansi.Truncate(label, 29, "…"))
+	if selected {
+		label = m.styles.PopupCursor.Render(label)
+	}
+	if s.err != "" {
+		return label + m.styles.ValidationError.Render(ansi.Truncate("⚠ "+s.err, m.metricsChartWidth()+20, "…"))
+	}
+	var last int64
+	if n := len(s.values); n > 0 {
+		last = s.values[n-1]
+	}
+	spark := sparkline(s.values, max)
+	if s.name == "job-failed" || strings.HasSuffix(s.name, "-failure") || strings.HasSuffix(s.name, "-rejected") {
+		spark = m.styles.RowFailed.Render(spark)
+	} else {
+		spark = m.styles.Accent.Render(spark)
+	}
+	return label + spark + fmt.Sprintf("  %9d %8d", s.total, last)
+}
+
+// modalMetricsBody renders the metrics as sparklines, the selected metric as a
+// bar chart, or the selected metric of every environment on a shared scale.
+func (m *model) modalMetricsBody() string {
+	var b strings.Builder
+	span := formatMetricsDuration(metricsRanges[m.metrics.rangeIdx])
+	every := formatMetricsDuration(m.metrics.interval)
+	var ranges []string
+	for i, r := range metricsRanges {
+		label := formatMetricsDuration(r)
+		if i == m.metrics.rangeIdx {
+			label = "[" + label + "]"
+		}
+		ranges = append(ranges, label)
+	}
+	title := "Engine metrics — " + m.currentEnv
+	if m.metrics.comparing {
+		title = "Engine metrics — " + metricNames[m.metrics.cursor] + " across environments"
+	}
+	b.WriteString(m.styles.Accent.Render(title) + "  " + m.styles.FgMuted.Render(fmt.Sprintf("last %s every %s  %s", span, every, strings.Join(ranges, " "))) + "\n\n")
+	if m.metrics.loading && len(m.metrics.series) == 0 && len(m.metrics.compare) == 0 {
+		b.WriteString(m.styles.FgMuted.Render("Loading…") + "\n")
+		return b.String()
+	}
+
+	header := fmt.Sprintf("%-30s%-*s  %9s %8s", "", m.metricsChartWidth(), "", "total", "last")
+	switch {
+	case m.metrics.chart && m.metrics.cursor < len(m.metrics.series):
+		s := m.metrics.series[m.metrics.cursor]
+		max := seriesMax([]metricSeries{s})
+		b.WriteString(fmt.Sprintf("%s  total %d  max %d per %s\n\n", s.name, s.total, max, every))
+		for _, row := range barChart(s.values, m.metricsChartHeight()) {
+			b.WriteString(m.styles.Accent.Render(row) + "\n")
+		}
+		pad := len(s.values) - 11
+		if pad < 4 {
+			pad = 4
+		}
+		b.WriteString(m.styles.FgMuted.Render(fmt.Sprintf("%s%*s", m.metrics.from.Local().Format("01-02 15:04"), pad, "now")) + "\n")
+	case m.metrics.comparing:
+		b.WriteString(m.styles.FgMuted.Render(header) + "\n")
+		max := seriesMax(m.metrics.compare)
+		for _, envName := range m.envNames {
+			for _, s := range m.metrics.compare {
+				if s.env == envName {
+					b.WriteString(m.renderMetricRow(envName, s, max, envName == m.currentEnv) + "\n")
+				}
+			}
+		}
+		b.WriteString("\n" + m.styles.FgMuted.Render(fmt.Sprintf("shared scale, max %d per %s", max, every)) + "\n")
+	default:
+		b.WriteString(m.styles.FgMuted.Render(header) + "\n")
+		for i, s := range m.metrics.series {
+			b.WriteString(m.renderMetricRow(s.name, s, seriesMax([]metricSeries{s}), i == m.metrics.cursor) + "\n")
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,97 @@
+package app
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kthoms/o6n/internal/apispec"
+	"github.com/kthoms/o6n/internal/config"
+)
+
+func TestMetricsBucketsAlignToTheInterval(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 7, 30, 0, time.UTC)
+	from, interval := metricsBuckets(now, 6*time.Hour, 24)
+	if interval != 15*time.Minute {
+		t.Errorf("expected 15m buckets, got %v", interval)
+	}
+	if want := time.Date(2026, 10, 16, 6, 15, 0, 0, time.UTC); !from.Equal(want) {
+		t.Errorf("expected the first bucket at %v, got %v", want, from)
+	}
+	if _, interval := metricsBuckets(now, time.Hour, 200); interval != time.Minute {
+		t.Errorf("expected at least a minute, got %v", interval)
+	}
+}
+
+func TestSparklineAndBarChart(t *testing.T) {
+	if got := sparkline([]int64{0, 1, 4, 8}, 8); got != " ▁▄█" {
+		t.Errorf("unexpected sparkline %q", got)
+	}
+	rows := barChart([]int64{0, 4, 8}, 2)
+	if len(rows) != 2 || rows[0] != "  █" || rows[1] != " ██" {
+		t.Errorf("unexpected bar chart %q", rows)
+	}
+}
+
+func TestFetchMetricSeriesBucketsTheValues(t *testing.T) {
+	from := time.Date(2026, 10, 16, 10, 0, 0, 0, time.UTC)
+	var query string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.RawQuery
+		_, _ = w.Write([]byte(`[
+			{"timestamp":"` + from.Add(30*time.Minute).Format(apispec.DateTimeLayout) + `","name":"job-failed","value":5},
+			{"timestamp":"` + from.Format(apispec.DateTimeLayout) + `","name":"job-failed","value":2},
+			{"timestamp":"` + from.Add(-time.Hour).Format(apispec.DateTimeLayout) + `","name":"job-failed","value":9}]`))
+	}))
+	defer srv.Close()
+
+	s := fetchMetricSeries(context.Background(), "local", config.Environment{URL: srv.URL}, "job-failed", from, 15*time.Minute, 4)
+	if s.err != "" || s.total != 7 || s.values[0] != 2 || s.values[2] != 5 {
+		t.Errorf("unexpected series %+v", s)
+	}
+	for _, p := range []string{"name=job-failed", "interval=900", "aggregateByReporter=true"} {
+		if !strings.Contains(query, p) {
+			t.Errorf("expected %s in the query, got %s", p, query)
+		}
+	}
+}
+
+func TestMetricsCompareAcrossEnvironments(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`[]`))
+	}))
+	defer srv.Close()
+
+	m := modeTestModel(t, config.ModeNormal)
+	m.config.Environments["local"] = config.Environment{URL: srv.URL}
+	m.config.Environments["prod"] = config.Environment{URL: srv.URL}
+	m.envNames = []string{"local", "prod"}
+	m.lastWidth, m.lastHeight = 160, 40
+	res, _ := m.Update(m.openMetrics()())
+	m = res.(model)
+	if m.activeModal != ModalMetrics || len(m.metrics.series) != len(metricNames) || m.metrics.loading {
+		t.Fatalf("expected every metric to be loaded, got %d", len(m.metrics.series))
+	}
+
+	m, _ = sendKeyString(m, "down")
+	m, cmd := sendKeyString(m, "c")
+	if cmd == nil || !m.metrics.comparing {
+		t.Fatal("expected c to compare the selected metric")
+	}
+	stale := metricsLoadedMsg{gen: m.metrics.gen - 1, series: []metricSeries{{name: "old"}}}
+	res, _ = m.Update(stale)
+	m = res.(model)
+	for _, env := range []string{"local", "prod"} {
+		res, _ = m.Update(metricsLoadedMsg{gen: m.metrics.gen, compare: true, series: []metricSeries{{name: "job-failed", env: env, values: []int64{1, 2}}}})
+		m = res.(model)
+	}
+	if m.metrics.loading || len(m.metrics.compare) != 2 {
+		t.Errorf("expected both environments, got %+v", m.metrics.compare)
+	}
+	if body := m.modalMetricsBody(); !strings.Contains(body, "job-failed across environments") || !strings.Contains(body, "prod") {
+		t.Errorf("expected the comparison, got:\n%s", body)
+	}
+}
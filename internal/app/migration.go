@@ -0,0 +1,525 @@
+package app
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/kthoms/o6n/internal/client"
+	"github.com/kthoms/o6n/internal/config"
+	"github.com/kthoms/o6n/internal/operaton"
+)
+
+// migrationPhase is the step of the migration planner (ModalMigration).
+type migrationPhase int
+
+const (
+	migrationPickTarget migrationPhase = iota // choose the target definition version
+	migrationEditPlan                         // review, edit and execute the plan
+)
+
+// migrationVersion is one selectable target definition version.
+type migrationVersion struct {
+	id      string
+	key     string
+	version int32
+	name    string
+}
+
+// migrationState holds the migration planner.
+type migrationState struct {
+	phase        migrationPhase
+	sourceID     string
+	instanceIDs  []string          // explicit instances (marked rows); nil = query
+	query        map[string]string // process instance query used when instanceIDs is nil
+	versions     []migrationVersion
+	targetID     string
+	instructions []operaton.MigrationInstructionDto
+	failures     map[string][]string // validation failures by instruction key (sources→targets)
+	validated    bool
+	cursor       int
+	loading      bool
+	inputFor     string // "target" (edit selected instruction) | "add"; "" = no input
+	input        textinput.Model
+	err          string
+	confirming   bool // the two-step confirmation was opened from the planner
+	async        bool // execute as batch
+}
+
+// migrationVersionsMsg delivers the versions of the source definition's key.
+type migrationVersionsMsg struct {
+	sourceID string
+	versions []migrationVersion
+	err      error
+}
+
+// migrationPlanMsg delivers a generated or edited plan and its validation report.
+type migrationPlanMsg struct {
+	instructions []operaton.MigrationInstructionDto
+	failures     map[string][]string
+	err          error
+}
+
+// migrationInstructionKey identifies an instruction by its activity mapping.
+func migrationInstructionKey(ins operaton.MigrationInstructionDto) string {
+	return strings.Join(ins.SourceActivityIds, ",") + "→" + strings.Join(ins.TargetActivityIds, ",")
+}
+
+// migrationFailures indexes the instruction reports of a validation report.
+func migrationFailures(report *operaton.MigrationPlanReportDto) map[string][]string {
+	failures := make(map[string][]string)
+	if report == nil {
+		return failures
+	}
+	for _, r := range report.InstructionReports {
+		if r.Instruction == nil || len(r.Failures) == 0 {
+			continue
+		}
+		key := migrationInstructionKey(*r.Instruction)
+		failures[key] = append(failures[key], r.Failures...)
+	}
+	return failures
+}
+
+// migrationPlan returns the plan DTO for the planner's current instructions.
+func (m *model) migrationPlan() operaton.MigrationPlanDto {
+	plan := operaton.NewMigrationPlanDto()
+	plan.SetSourceProcessDefinitionId(m.migration.sourceID)
+	plan.SetTargetProcessDefinitionId(m.migration.targetID)
+	plan.Instructions = m.migration.instructions
+	return *plan
+}
+
+// fetchMigrationVersionsCmd loads all versions of the source definition's key.
+func (m model) fetchMigrationVersionsCmd(sourceID string) tea.Cmd {
+	env, ok := m.config.Environments[m.currentEnv]
+	if !ok {
+		return nil
+	}
+	c := client.NewClient(env, m.debugEnabled)
+	return func() tea.Msg {
+		defs, err := c.FetchProcessDefinitionVersions(sourceID)
+		if err != nil {
+			return migrationVersionsMsg{sourceID: sourceID, err: err}
+		}
+		versions := make([]migrationVersion, 0, len(defs))
+		for _, d := range defs {
+			versions = append(versions, migrationVersion{
+				id:      client.GetStringValue(d.Id),
+				key:     client.GetStringValue(d.Key),
+				version: client.GetInt32Value(d.Version),
+				name:    client.GetStringValue(d.Name),
+			})
+		}
+		return migrationVersionsMsg{sourceID: sourceID, versions: versions}
+	}
+}
+
+// generateMigrationPlanCmd generates the plan for source → target and validates it.
+func (m model) generateMigrationPlanCmd(sourceID, targetID string) tea.Cmd {
+	env, ok := m.config.Environments[m.currentEnv]
+	if !ok {
+		return nil
+	}
+	c := client.NewClient(env, m.debugEnabled)
+	return func() tea.Msg {
+		plan, err := c.GenerateMigrationPlan(sourceID, targetID)
+		if err != nil {
+			return migrationPlanMsg{err: err}
+		}
+		report, err := c.ValidateMigrationPlan(*plan)
+		return migrationPlanMsg{instructions: plan.Instructions, failures: migrationFailures(report), err: err}
+	}
+}
+
+// validateMigrationPlanCmd validates the planner's (edited) instructions.
+func (m model) validateMigrationPlanCmd() tea.Cmd {
+	env, ok := m.config.Environments[m.currentEnv]
+	if !ok {
+		return nil
+	}
+	c := client.NewClient(env, m.debugEnabled)
+	plan := m.migrationPlan()
+	return func() tea.Msg {
+		report, err := c.ValidateMigrationPlan(plan)
+		return migrationPlanMsg{instructions: plan.Instructions, failures: migrationFailures(report), err: err}
+	}
+}
+
+// executeMigrationCmd runs the confirmed migration synchronously or as a batch.
+func (m model) executeMigrationCmd() tea.Cmd {
+	env, ok := m.config.Environments[m.currentEnv]
+	if !ok {
+		return nil
+	}
+	c := client.NewClient(env, m.debugEnabled).WithContext(m.auditContext("Migrate instances"))
+	plan := m.migrationPlan()
+	ids, query, async := m.migration.instanceIDs, m.migration.query, m.migration.async
+	return func() tea.Msg {
+		batchID, err := c.ExecuteMigration(plan, ids, query, async)
+		if err != nil {
+			return errMsg{err}
+		}
+		if async {
+			return batchSubmittedMsg{label: "Migrate Instances", batchID: batchID}
+		}
+		return actionExecutedMsg{label: "Migrated instances"}
+	}
+}
+
+// openMigration opens the migration planner for sourceID and the given instance scope.
+func (m *model) openMigration(sourceID string, instanceIDs []string, query map[string]string) tea.Cmd {
+	input := textinput.New()
+	input.Prompt = "> "
+	input.CharLimit = 0
+	input.Width = 50
+	m.migration = migrationState{
+		phase:       migrationPickTarget,
+		sourceID:    sourceID,
+		instanceIDs: instanceIDs,
+		query:       query,
+		loading:     true,
+		input:       input,
+	}
+	m.activeModal = ModalMigration
+	return m.fetchMigrationVersionsCmd(sourceID)
+}
+
+// openMigrationFromDefinition migrates all instances of the selected process definition.
+func (m *model) openMigrationFromDefinition() tea.Cmd {
+	id := m.resolveActionID(config.ActionDef{})
+	if id == "" {
+		return nil
+	}
+	return m.openMigration(id, nil, map[string]string{"processDefinitionId": id})
+}
+
+// openMigrationFromInstances migrates the marked process instances or, without marks,
+// all instances matching the current filter that share the selected row's definition.
+func (m *model) openMigrationFromInstances() tea.Cmd {
+	if idxs := m.markedIndexes(); len(idxs) > 0 {
+		sourceID := ""
+		ids := make([]string, 0, len(idxs))
+		for _, i := range idxs {
+			def := m.instanceDefinitionAt(i)
+			if def == "" {
+				return m.refuseMigration("Cannot tell the process definition of a marked instance")
+			}
+			if sourceID != "" && def != sourceID {
+				return m.refuseMigration("Marked instances belong to different process definitions")
+			}
+			sourceID = def
+			ids = append(ids, m.resolveActionIDAt(config.ActionDef{}, i))
+		}
+		return m.openMigration(sourceID, ids, nil)
+	}
+	sourceID := m.instanceDefinitionAt(m.table.Cursor())
+	if sourceID == "" {
+		return m.refuseMigration("Cannot tell the process definition of the selected instance")
+	}
+	// The planner must not migrate more instances than the table shows
+	query, ok := m.scopeParams()
+	if !ok {
+		return m.refuseMigration("Clear the search first: " + m.currentRoot + " has no server-side search to scope a migration to")
+	}
+	query["processDefinitionId"] = sourceID
+	if err := client.ValidateInstanceQuery(query); err != nil {
+		return m.refuseMigration(err.Error())
+	}
+	return m.openMigration(sourceID, nil, query)
+}
+
+// instanceDefinitionAt returns the definitionId of the instance in table row idx,
+// from its column or its item, or "" when neither has one.
+func (m *model) instanceDefinitionAt(idx int) string {
+	if v, ok := m.cellAt(idx, "definitionId"); ok {
+		return v
+	}
+	if item := m.rowDataAt(idx); item != nil {
+		if v, ok := item["definitionId"].(string); ok {
+			return v
+		}
+	}
+	return ""
+}
+
+// refuseMigration shows why the planner does not open.
+func (m *model) refuseMigration(reason string) tea.Cmd {
+	var cmd tea.Cmd
+	m.footerError, m.footerStatusKind, cmd = setFooterStatus(footerStatusError, reason, 5*time.Second)
+	return cmd
+}
+
+// migrationScope describes which instances the planner migrates.
+func (m *model) migrationScope() string {
+	if n := len(m.migration.instanceIDs); n > 0 {
+		return fmt.Sprintf("%d marked instances", n)
+	}
+	keys := make([]string, 0, len(m.migration.query))
+	for k := range m.migration.query {
+		if k != "processDefinitionId" {
+			keys = append(keys, k)
+		}
+	}
+	if len(keys) == 0 {
+		return "all instances of the source version"
+	}
+	sort.Strings(keys)
+	parts := make([]string, 0, len(keys))
+	for _, k := range keys {
+		parts = append(parts, k+"="+m.migration.query[k])
+	}
+	return "instances matching " + strings.Join(parts, ", ")
+}
+
+// migrationVersionLabel renders a definition id as key:version when it is known.
+func (m *model) migrationVersionLabel(id string) string {
+	for _, v := range m.migration.versions {
+		if v.id == id {
+			return fmt.Sprintf("%s:%d", v.key, v.version)
+		}
+	}
+	return id
+}
+
+// migrationFailureCount returns the number of instructions with validation failures.
+func (m *model) migrationFailureCount() int {
+	n := 0
+	for _, ins := range m.migration.instructions {
+		if len(m.migration.failures[migrationInstructionKey(ins)]) > 0 {
+			n++
+		}
+	}
+	return n
+}
+
+// commitMigrationInput applies the input line and revalidates the plan.
+func (m *model) commitMigrationInput() tea.Cmd {
+	value := strings.TrimSpace(m.migration.input.Value())
+	switch m.migration.inputFor {
+	case "target":
+		if value == "" {
+			m.migration.err = "a target activity is required"
+			return nil
+		}
+		m.migration.instructions[m.migration.cursor].TargetActivityIds = splitActivityIDs(value)
+	case "add":
+		src, tgt, ok := strings.Cut(value, "=")
+		if !ok || strings.TrimSpace(src) == "" || strings.TrimSpace(tgt) == "" {
+			m.migration.err = "expected sourceActivity=targetActivity"
+			return nil
+		}
+		m.migration.instructions = append(m.migration.instructions, operaton.MigrationInstructionDto{
+			SourceActivityIds: splitActivityIDs(src),
+			TargetActivityIds: splitActivityIDs(tgt),
+		})
+		m.migration.cursor = len(m.migration.instructions) - 1
+	}
+	m.migration.inputFor = ""
+	m.migration.err = ""
+	m.migration.input.Blur()
+	m.migration.validated = false
+	return m.validateMigrationPlanCmd()
+}
+
+// splitActivityIDs splits a comma-separated list of activity ids.
+func splitActivityIDs(s string) []string {
+	var ids []string
+	for _, p := range strings.Split(s, ",") {
+		if p = strings.TrimSpace(p); p != "" {
+			ids = append(ids, p)
+		}
+	}
+	return ids
+}
+
+// handleMigrationKey processes a key press while ModalMigration is open.
+func (m *model) handleMigrationKey(msg tea.KeyMsg) tea.Cmd {
+	s := msg.String()
+	if m.migration.inputFor != "" {
+		switch s {
+		case "esc":
+			m.migration.inputFor = ""
+			m.migration.err = ""
+			m.migration.input.Blur()
+		case "enter":
+			return m.commitMigrationInput()
+		default:
+			var cmd tea.Cmd
+			m.migration.input, cmd = m.migration.input.Update(msg)
+			return cmd
+		}
+		return nil
+	}
+
+	n := len(m.migration.versions)
+	if m.migration.phase == migrationEditPlan {
+		n = len(m.migration.instructions)
+	}
+	switch s {
+	case "up", "k":
+		if m.migration.cursor > 0 {
+			m.migration.cursor--
+		}
+		return nil
+	case "down", "j":
+		if m.migration.cursor < n-1 {
+			m.migration.cursor++
+		}
+		return nil
+	}
+
+	if m.migration.phase == migrationPickTarget {
+		switch s {
+		case "esc", "q":
+			m.activeModal = ModalNone
+			m.migration = migrationState{}
+		case "enter":
+			if m.migration.cursor >= len(m.migration.versions) {
+				return nil
+			}
+			target := m.migration.versions[m.migration.cursor].id
+			if target == m.migration.sourceID {
+				m.migration.err = "choose a version other than the source"
+				return nil
+			}
+			m.migration.targetID = target
+			m.migration.phase = migrationEditPlan
+			m.migration.cursor = 0
+			m.migration.loading = true
+			m.migration.err = ""
+			return m.generateMigrationPlanCmd(m.migration.sourceID, target)
+		}
+		return nil
+	}
+
+	switch s {
+	case "esc", "q":
+		// Back to the version list
+		m.migration.phase = migrationPickTarget
+		m.migration.instructions = nil
+		m.migration.failures = nil
+		m.migration.validated = false
+		m.migration.cursor = 0
+		m.migration.err = ""
+	case "e":
+		if m.migration.cursor < len(m.migration.instructions) {
+			m.migration.inputFor = "target"
+			m.migration.input.Placeholder = "target activity id(s)"
+			m.migration.input.SetValue(strings.Join(m.migration.instructions[m.migration.cursor].TargetActivityIds, ","))
+			m.migration.input.CursorEnd()
+			return m.migration.input.Focus()
+		}
+	case "n":
+		m.migration.inputFor = "add"
+		m.migration.input.Placeholder = "sourceActivity=targetActivity"
+		m.migration.input.SetValue("")
+		return m.migration.input.Focus()
+	case "x", "backspace":
+		if m.migration.cursor < len(m.migration.instructions) {
+			m.migration.instructions = append(m.migration.instructions[:m.migration.cursor], m.migration.instructions[m.migration.cursor+1:]...)
+			if m.migration.cursor > 0 && m.migration.cursor >= len(m.migration.instructions) {
+				m.migration.cursor--
+			}
+			m.migration.validated = false
+			return m.validateMigrationPlanCmd()
+		}
+	case "enter", "b":
+		switch {
+		case !m.migration.validated:
+			m.migration.err = "wait for the plan to be validated"
+		case len(m.migration.instructions) == 0:
+			m.migration.err = "the plan has no instructions"
+		case m.migrationFailureCount() > 0:
+			m.migration.err = "fix the validation failures first"
+		default:
+			m.migration.async = s == "b"
+			m.migration.confirming = true
+			m.activeModal = ModalConfirmDelete
+			m.confirmFocusedBtn = 1 // default to Cancel (safe)
+		}
+	}
+	return nil
+}
+
+// modalMigrationBody renders the migration planner.
+func (m *model) modalMigrationBody() string {
+	var b strings.Builder
+	source := m.migrationVersionLabel(m.migration.sourceID)
+	if m.migration.phase == migrationPickTarget {
+		fmt.Fprintf(&b, "Migrate %s from %s\n\n", m.migrationScope(), source)
+		b.WriteString(m.styles.Accent.Render("TARGET VERSION") + "\n")
+		if m.migration.loading {
+			b.WriteString("  Loading…\n")
+		}
+		for i, v := range m.migration.versions {
+			prefix := "  "
+			if i == m.migration.cursor {
+				prefix = "> "
+			}
+			line := fmt.Sprintf("%s%s:%d  %s", prefix, v.key, v.version, m.styles.FgMuted.Render(v.id))
+			if v.id == m.migration.sourceID {
+				line += m.styles.FgMuted.Render("  (source)")
+			}
+			if i == m.migration.cursor {
+				line = m.styles.PopupCursor.Render(line)
+			}
+			b.WriteString(line + "\n")
+		}
+	} else {
+		fmt.Fprintf(&b, "Migration plan %s → %s\n", source, m.migrationVersionLabel(m.migration.targetID))
+		fmt.Fprintf(&b, "Scope: %s\n\n", m.migrationScope())
+		b.WriteString(m.styles.Accent.Render(fmt.Sprintf("  %-28s → %s", "SOURCE ACTIVITY", "TARGET ACTIVITY")) + "\n")
+		if m.migration.loading {
+			b.WriteString("  Generating plan…\n")
+		}
+		for i, ins := range m.migration.instructions {
+			prefix := "  "
+			if i == m.migration.cursor {
+				prefix = "> "
+			}
+			line := fmt.Sprintf("%s%-28s → %s", prefix, strings.Join(ins.SourceActivityIds, ","), strings.Join(ins.TargetActivityIds, ","))
+			if i == m.migration.cursor {
+				line = m.styles.PopupCursor.Render(line)
+			}
+			b.WriteString(line + "\n")
+			for _, f := range m.migration.failures[migrationInstructionKey(ins)] {
+				b.WriteString("      " + m.styles.ValidationError.Render("✗ "+f) + "\n")
+			}
+		}
+		b.WriteString("\n")
+		switch {
+		case !m.migration.validated:
+			b.WriteString(m.styles.FgMuted.Render("Validating…") + "\n")
+		case m.migrationFailureCount() > 0:
+			b.WriteString(m.styles.ValidationError.Render(fmt.Sprintf("✗ %d instructions fail validation", m.migrationFailureCount())) + "\n")
+		default:
+			b.WriteString(m.styles.SuccessFooter.Render("✓ plan is valid") + "\n")
+		}
+	}
+	if m.migration.inputFor != "" {
+		b.WriteString("\n" + m.migration.inputFor + ": " + m.migration.input.View() + "\n")
+	}
+	if m.migration.err != "" {
+		b.WriteString("\n" + m.styles.ValidationError.Render(m.migration.err) + "\n")
+	}
+	return b.String()
+}
+
+// renderMigrationConfirmBody renders the two-step confirmation of a migration.
+func (m *model) renderMigrationConfirmBody() string {
+	mode := "synchronously"
+	if m.migration.async {
+		mode = "as a batch"
+	}
+	var b strings.Builder
+	b.WriteString("⚠️  MIGRATE PROCESS INSTANCES\n\n")
+	fmt.Fprintf(&b, "%s → %s\n", m.migrationVersionLabel(m.migration.sourceID), m.migrationVersionLabel(m.migration.targetID))
+	fmt.Fprintf(&b, "Scope:         %s\n", m.migrationScope())
+	fmt.Fprintf(&b, "Instructions:  %d\n", len(m.migration.instructions))
+	fmt.Fprintf(&b, "Execution:     %s\n", mode)
+	return b.String()
+}
@@ -0,0 +1,199 @@
+package app
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/bubbles/table"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/kthoms/o6n/internal/operaton"
+)
+
+// migrationServer fakes the engine endpoints the migration planner uses. Any
+// instruction targeting "gone" fails validation.
+func migrationServer(t *testing.T, executed *map[string]interface{}) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.URL.Path == "/process-definition/invoice:1":
+			_, _ = w.Write([]byte(`{"id":"invoice:1","key":"invoice","version":1}`))
+		case r.URL.Path == "/process-definition":
+			_, _ = w.Write([]byte(`[{"id":"invoice:2","key":"invoice","version":2},{"id":"invoice:1","key":"invoice","version":1}]`))
+		case r.URL.Path == "/migration/generate":
+			_, _ = w.Write([]byte(`{"sourceProcessDefinitionId":"invoice:1","targetProcessDefinitionId":"invoice:2",
+				"instructions":[{"sourceActivityIds":["review"],"targetActivityIds":["review"]}]}`))
+		case r.URL.Path == "/migration/validate":
+			var plan struct {
+				Instructions []struct {
+					SourceActivityIds []string `json:"sourceActivityIds"`
+					TargetActivityIds []string `json:"targetActivityIds"`
+				} `json:"instructions"`
+			}
+			_ = json.NewDecoder(r.Body).Decode(&plan)
+			var reports []string
+			for _, ins := range plan.Instructions {
+				if ins.TargetActivityIds[0] == "gone" {
+					src, _ := json.Marshal(ins.SourceActivityIds)
+					reports = append(reports, `{"instruction":{"sourceActivityIds":`+string(src)+`,"targetActivityIds":["gone"]},"failures":["target activity does not exist"]}`)
+				}
+			}
+			_, _ = w.Write([]byte(`{"instructionReports":[` + strings.Join(reports, ",") + `]}`))
+		case r.URL.Path == "/migration/execute":
+			_ = json.NewDecoder(r.Body).Decode(executed)
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	}))
+}
+
+// migrationTestRows are two process instances of invoice:1.
+var migrationTestRows = []table.Row{{"pi-1", "invoice:1"}, {"pi-2", "invoice:1"}}
+
+// runMigrationCmd feeds the messages of cmd back into the model.
+func runMigrationCmd(t *testing.T, m model, cmd tea.Cmd) model {
+	t.Helper()
+	if cmd == nil {
+		return m
+	}
+	res, _ := m.Update(cmd())
+	return res.(model)
+}
+
+func TestMigrationPlannerGeneratesValidatesAndExecutes(t *testing.T) {
+	var executed map[string]interface{}
+	srv := migrationServer(t, &executed)
+	defer srv.Close()
+
+	m := tableTestModel(t, srv.URL, "process-instance", []string{"id", "definitionId"}, migrationTestRows)
+	m, _ = sendKeyString(m, " ") // mark pi-1
+	var item actionItem
+	for _, it := range m.buildActionsForRoot() {
+		if it.key == "M" {
+			item = it
+		}
+	}
+	if item.cmd == nil {
+		t.Fatal("expected Migrate Instances in the process-instance actions")
+	}
+	m = runMigrationCmd(t, m, item.cmd(&m))
+	if m.activeModal != ModalMigration || len(m.migration.versions) != 2 {
+		t.Fatalf("expected planner with versions, got modal %v %+v", m.activeModal, m.migration.versions)
+	}
+	if m.migration.versions[m.migration.cursor].id != "invoice:2" {
+		t.Errorf("expected the newest other version preselected, got cursor %d", m.migration.cursor)
+	}
+
+	m, cmd := sendKeyString(m, "enter")
+	m = runMigrationCmd(t, m, cmd)
+	if len(m.migration.instructions) != 1 || !m.migration.validated {
+		t.Fatalf("expected validated generated plan, got %+v", m.migration.instructions)
+	}
+
+	// An instruction to a missing activity fails validation and blocks execution
+	m, _ = sendKeyString(m, "n")
+	m, _ = sendKeyString(m, "archive=gone")
+	m, cmd = sendKeyString(m, "enter")
+	m = runMigrationCmd(t, m, cmd)
+	if m.migrationFailureCount() != 1 {
+		t.Fatalf("expected one failing instruction, got %v", m.migration.failures)
+	}
+	if view := m.modalMigrationBody(); !strings.Contains(view, "target activity does not exist") {
+		t.Errorf("expected validation failure in the planner, got %q", view)
+	}
+	m, _ = sendKeyString(m, "enter")
+	if m.activeModal != ModalMigration || !strings.Contains(m.migration.err, "validation failures") {
+		t.Fatal("expected execution to be refused while validation fails")
+	}
+
+	m, cmd = sendKeyString(m, "x")
+	m = runMigrationCmd(t, m, cmd)
+	m, _ = sendKeyString(m, "enter")
+	if m.activeModal != ModalConfirmDelete {
+		t.Fatalf("expected two-step confirmation, got modal %v", m.activeModal)
+	}
+	if confirm := m.renderConfirmDeleteModal(0, 0); !strings.Contains(confirm, "invoice:1 → invoice:2") || !strings.Contains(confirm, "1 marked instances") {
+		t.Errorf("unexpected confirmation %q", confirm)
+	}
+
+	m, cmd = sendKeyString(m, "ctrl+d")
+	findMsg[actionExecutedMsg](t, cmd)
+	ids, _ := executed["processInstanceIds"].([]interface{})
+	if len(ids) != 1 || ids[0] != "pi-1" {
+		t.Errorf("expected marked instance pi-1 to be migrated, got %v", executed)
+	}
+}
+
+func TestMigrationPlannerCancelReturnsToPlan(t *testing.T) {
+	m := tableTestModel(t, "http://localhost:0", "process-instance", []string{"id", "definitionId"}, migrationTestRows)
+	m.openMigration("invoice:1", nil, map[string]string{"processDefinitionId": "invoice:1"})
+	m.migration.phase = migrationEditPlan
+	m.migration.targetID = "invoice:2"
+	res, _ := m.Update(migrationPlanMsg{instructions: nil, failures: map[string][]string{}})
+	m = res.(model)
+	m.migration.instructions = []operaton.MigrationInstructionDto{
+		{SourceActivityIds: []string{"review"}, TargetActivityIds: []string{"review"}},
+	}
+
+	m, _ = sendKeyString(m, "b")
+	if m.activeModal != ModalConfirmDelete || !m.migration.async {
+		t.Fatalf("expected batch confirmation, got modal %v", m.activeModal)
+	}
+	if confirm := m.renderConfirmDeleteModal(0, 0); !strings.Contains(confirm, "as a batch") || !strings.Contains(confirm, "all instances of the source version") {
+		t.Errorf("unexpected confirmation %q", confirm)
+	}
+	m, _ = sendKeyString(m, "esc")
+	if m.activeModal != ModalMigration || len(m.migration.instructions) != 1 {
+		t.Fatalf("expected to return to the planner, got modal %v", m.activeModal)
+	}
+}
+
+func TestMigrationRefusesMarkedInstancesOfDifferentDefinitions(t *testing.T) {
+	m := tableTestModel(t, "http://localhost:0", "process-instance", []string{"id", "definitionId"}, migrationTestRows)
+	m.table.SetRows([]table.Row{{"pi-1", "invoice:1"}, {"pi-2", "invoice:2"}})
+	m, _ = sendKeyString(m, " ")
+	m, _ = sendKeyString(m, " ")
+	m.openMigrationFromInstances()
+	if m.activeModal == ModalMigration || !strings.Contains(m.footerError, "different process definitions") {
+		t.Errorf("expected mixed definitions to be refused, got modal %v footer %q", m.activeModal, m.footerError)
+	}
+}
+
+func TestMigrationRefusesFilterUnknownToTheInstanceQuery(t *testing.T) {
+	m := tableTestModel(t, "http://localhost:0", "process-instance", []string{"id", "definitionId"}, migrationTestRows)
+	m.queryFilters = map[string]string{"businesKeyLike": "%x%"}
+	m.openMigrationFromInstances()
+	if m.activeModal == ModalMigration || !strings.Contains(m.footerError, "businesKeyLike") {
+		t.Errorf("expected the unknown filter to be refused, got modal %v footer %q", m.activeModal, m.footerError)
+	}
+}
+
+func TestMigrationScopedToSearchAndSelectedRow(t *testing.T) {
+	// definitionId is hidden and the table sorted: the cursor row is pi-2
+	m := tableTestModel(t, "http://localhost:0", "process-instance", []string{"id"}, []table.Row{{"pi-2"}, {"pi-1"}})
+	m.rowData = []map[string]interface{}{
+		{"id": "pi-1", "definitionId": "invoice:1"},
+		{"id": "pi-2", "definitionId": "invoice:2"},
+	}
+	m.sortColumn = 0
+	m.searchTerm = "order"
+
+	m.openMigrationFromInstances()
+	if m.activeModal == ModalMigration || !strings.Contains(m.footerError, "Clear the search") {
+		t.Fatalf("expected a search without search_param to be refused, got modal %v footer %q", m.activeModal, m.footerError)
+	}
+
+	m.config.Tables[0].SearchParam = "businessKeyLike"
+	m.openMigrationFromInstances()
+	if m.activeModal != ModalMigration {
+		t.Fatalf("expected the planner, got footer %q", m.footerError)
+	}
+	if m.migration.sourceID != "invoice:2" || m.migration.query["processDefinitionId"] != "invoice:2" ||
+		m.migration.query["businessKeyLike"] != "order" {
+		t.Errorf("expected source invoice:2 scoped to the search, got %q %v", m.migration.sourceID, m.migration.query)
+	}
+}
@@ -105,6 +105,7 @@ func init() {
 		HintLine: []Hint{
 			{Key: "Tab", Label: "switch", Priority: 1},
 			{Key: "Enter", Label: "save", Priority: 1},
+			{Key: "Ctrl+O", Label: "$EDITOR", Priority: 2},
 			{Key: "Esc", Label: "cancel", Priority: 2},
 		},
 	})
@@ -167,6 +168,180 @@ func init() {
 		},
 	})
 
+	registerModal(ModalBulkResult, ModalConfig{
+		SizeHint: OverlayLarge,
+		BodyRenderer: func(m model) string {
+			return m.modalBulkResultBody()
+		},
+		HintLine: []Hint{
+			{Key: "↑↓", Label: "scroll", Priority: 1},
+			{Key: "q/Esc", Label: "close", Priority: 1},
+		},
+	})
+
+	registerModal(ModalModifyInstance, ModalConfig{
+		SizeHint: OverlayLarge,
+		BodyRenderer: func(m model) string {
+			return m.modalModifyInstanceBody()
+		},
+		HintLine: []Hint{
+			{Key: "↑↓", Label: "select", Priority: 1},
+			{Key: "c/b/a/t", Label: "add", Priority: 1},
+			{Key: "v", Label: "variable", Priority: 2},
+			{Key: "x", Label: "remove", Priority: 2},
+			{Key: "Enter", Label: "submit", Priority: 1},
+			{Key: "Esc", Label: "close", Priority: 1},
+		},
+	})
+
+	registerModal(ModalMigration, ModalConfig{
+		SizeHint: OverlayLarge,
+		BodyRenderer: func(m model) string {
+			return m.modalMigrationBody()
+		},
+		HintLine: []Hint{
+			{Key: "↑↓", Label: "select", Priority: 1},
+			{Key: "Enter", Label: "choose/execute", Priority: 1},
+			{Key: "b", Label: "batch", Priority: 1},
+			{Key: "e/n/x", Label: "edit/add/remove", Priority: 2},
+			{Key: "Esc", Label: "back", Priority: 1},
+		},
+	})
+
+	registerModal(ModalDeploy, ModalConfig{
+		SizeHint: OverlayCenter,
+		BodyRenderer: func(m model) string {
+			return m.modalDeployBody()
+		},
+		HintLine: []Hint{
+			{Key: "Tab", Label: "next field", Priority: 1},
+			{Key: "Space", Label: "toggle", Priority: 2},
+			{Key: "Enter", Label: "deploy", Priority: 1},
+			{Key: "Esc", Label: "cancel", Priority: 1},
+		},
+	})
+
+	registerModal(ModalDiagram, ModalConfig{
+		SizeHint: OverlayLarge,
+		BodyRenderer: func(m model) string {
+			return m.modalDiagramBody()
+		},
+		HintLine: []Hint{
+			{Key: "↑↓", Label: "scroll", Priority: 1},
+			{Key: "PgUp/PgDn", Label: "page", Priority: 2},
+			{Key: "r", Label: "refresh", Priority: 2},
+			{Key: "q/Esc", Label: "close", Priority: 1},
+		},
+	})
+
+	registerModal(ModalTimeline, ModalConfig{
+		SizeHint: OverlayLarge,
+		BodyRenderer: func(m model) string {
+			return m.modalTimelineBody()
+		},
+		HintLine: []Hint{
+			{Key: "↑↓", Label: "select", Priority: 1},
+			{Key: "v/Enter", Label: "variables", Priority: 1},
+			{Key: "l", Label: "job log", Priority: 1},
+			{Key: "r", Label: "refresh", Priority: 2},
+			{Key: "q/Esc", Label: "close", Priority: 1},
+		},
+	})
+
+	registerModal(ModalAudit, ModalConfig{
+		SizeHint: OverlayLarge,
+		BodyRenderer: func(m model) string {
+			return m.modalAuditBody()
+		},
+		HintLine: []Hint{
+			{Key: "↑↓", Label: "select", Priority: 1},
+			{Key: "a", Label: "all envs", Priority: 1},
+			{Key: "f", Label: "failed", Priority: 1},
+			{Key: "r", Label: "refresh", Priority: 2},
+			{Key: "q/Esc", Label: "close", Priority: 1},
+		},
+	})
+
+	registerModal(ModalAlerts, ModalConfig{
+		SizeHint: OverlayLarge,
+		BodyRenderer: func(m model) string {
+			return m.modalAlertsBody()
+		},
+		HintLine: []Hint{
+			{Key: "↑↓", Label: "select", Priority: 1},
+			{Key: "Enter", Label: "open view", Priority: 1},
+			{Key: "c", Label: "clear", Priority: 2},
+			{Key: "q/Esc", Label: "close", Priority: 1},
+		},
+	})
+
+	registerModal(ModalDashboard, ModalConfig{
+		SizeHint: OverlayLarge,
+		BodyRenderer: func(m model) string {
+			return m.modalDashboardBody()
+		},
+		HintLine: []Hint{
+			{Key: "←↑↓→", Label: "select", Priority: 1},
+			{Key: "Enter", Label: "open table", Priority: 1},
+			{Key: "r", Label: "refresh", Priority: 2},
+			{Key: "q/Esc", Label: "close", Priority: 1},
+		},
+	})
+
+	registerModal(ModalMetrics, ModalConfig{
+		SizeHint: OverlayLarge,
+		BodyRenderer: func(m model) string {
+			return m.modalMetricsBody()
+		},
+		HintLine: []Hint{
+			{Key: "↑↓", Label: "select", Priority: 1},
+			{Key: "←→", Label: "range", Priority: 1},
+			{Key: "Enter", Label: "bar chart", Priority: 1},
+			{Key: "c", Label: "compare envs", Priority: 1},
+			{Key: "r", Label: "refresh", Priority: 2},
+			{Key: "q/Esc", Label: "close", Priority: 1},
+		},
+	})
+
+	registerModal(ModalStacktrace, ModalConfig{
+		SizeHint: OverlayLarge,
+		BodyRenderer: func(m model) string {
+			return m.modalStacktraceBody()
+		},
+		HintLine: []Hint{
+			{Key: "↑↓", Label: "scroll", Priority: 1},
+			{Key: "Enter", Label: "fold", Priority: 1},
+			{Key: "z", Label: "fold all", Priority: 2},
+			{Key: "/", Label: "search", Priority: 1},
+			{Key: "c", Label: "copy", Priority: 1},
+			{Key: "s", Label: "save", Priority: 1},
+			{Key: "q/Esc", Label: "close", Priority: 1},
+		},
+	})
+
+	registerModal(ModalTriage, ModalConfig{
+		SizeHint: OverlayLarge,
+		BodyRenderer: func(m model) string {
+			return m.modalTriageBody()
+		},
+		HintLine: []Hint{
+			{Key: "↑↓", Label: "select", Priority: 1},
+			{Key: "Enter", Label: "stacktrace", Priority: 1},
+			{Key: "o", Label: "open jobs", Priority: 2},
+			{Key: "R", Label: "retry group", Priority: 1},
+			{Key: "+", Label: "set retries", Priority: 1},
+			{Key: "r", Label: "refresh", Priority: 2},
+			{Key: "q/Esc", Label: "close", Priority: 1},
+		},
+	})
+
+	registerModal(ModalSaveView, ModalConfig{
+		SizeHint: OverlayCenter,
+		BodyRenderer: func(m model) string {
+			return m.renderSaveViewBody()
+		},
+	})
+
 	registerModal(ModalContextSwitcher, ModalConfig{
 		SizeHint: OverlayCenter,
 		BodyRenderer: func(m model) string {
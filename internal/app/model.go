@@ -1,6 +1,7 @@
 package app
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"os"
@@ -13,12 +14,14 @@ import (
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/kthoms/o6n/internal/apispec"
 	"github.com/kthoms/o6n/internal/config"
 	"github.com/kthoms/o6n/internal/dao"
 	"golang.org/x/term"
 )
 
-type refreshMsg struct{}
+// refreshMsg is an auto-refresh tick; gen is the refreshGen it was scheduled in.
+type refreshMsg struct{ gen int }
 
 type dataLoadedMsg struct {
 	definitions []config.ProcessDefinition
@@ -56,6 +59,39 @@ type actionExecutedMsg struct {
 	closeTaskDialog bool   // when true, close ModalTaskComplete and clear its state
 }
 
+// bulkResult is the outcome of a bulk action for a single row ID.
+type bulkResult struct {
+	id  string
+	err error
+}
+
+// bulkProgressMsg reports one finished row of a running bulk action; ch delivers the rest.
+type bulkProgressMsg struct {
+	result bulkResult
+	ch     <-chan bulkResult
+}
+
+// bulkDoneMsg is sent when every row of a bulk action has been processed.
+type bulkDoneMsg struct{}
+
+// batchSubmittedMsg is sent when a server-side batch was created for a batch action.
+type batchSubmittedMsg struct {
+	label   string
+	batchID string
+}
+
+// batchProgressMsg reports the statistics of a tracked batch. found is false once
+// the engine no longer lists the batch, i.e. all of its jobs completed.
+type batchProgressMsg struct {
+	batchID   string
+	found     bool
+	total     int
+	completed int
+	remaining int
+	failed    int
+	err       error
+}
+
 type errMsg struct{ err error }
 
 type healthTickMsg struct{}
@@ -146,6 +182,19 @@ const (
 	ModalFirstRun   // home context selection on first run (or Ctrl+H to revisit)
 	ModalActionMenu // Ctrl+Space context-sensitive action menu
 	ModalContextSwitcher
+	ModalBulkResult // per-row summary after a bulk action on marked rows
+	ModalModifyInstance // process instance modification wizard
+	ModalMigration      // process instance migration planner
+	ModalDeploy         // deploy files from disk
+	ModalDiagram        // BPMN diagram with runtime overlay
+	ModalTimeline       // historic activity timeline of a process instance
+	ModalAudit          // audit log of changes made through o6n
+	ModalSaveView       // name and save the current view
+	ModalAlerts         // alerts fired by the alert rules
+	ModalDashboard      // engine dashboard with drillable counts
+	ModalMetrics        // engine metrics as sparklines and bar charts
+	ModalStacktrace     // stacktrace of a failed job or external task
+	ModalTriage         // failed jobs grouped by exception
 )
 
 // taskCompleteFocusArea tracks keyboard focus within the task completion modal
@@ -231,6 +280,7 @@ type actionItem struct {
 	label      string // display label
 	cmd        func(m *model) tea.Cmd
 	isNavigate bool
+	mutates    bool // changes engine state; hidden in read-only environments
 }
 
 // viewState captures the complete state of a view for navigation history
@@ -245,6 +295,7 @@ type viewState struct {
 	cachedDefinitions     []config.ProcessDefinition
 	tableColumns          []table.Column
 	genericParams         map[string]string        // drilldown filter params active at this level
+	queryFilters          map[string]string        // filter bar params active at this level
 	rowData               []map[string]interface{} // raw API data per row for drilldown column lookup
 }
 
@@ -267,6 +318,10 @@ type model struct {
 	// navigation history stack for back/forward
 	navigationStack []viewState
 
+	// viewCtx scopes the fetches of the current view; navigation cancels it
+	viewCtx    context.Context
+	viewCancel context.CancelFunc
+
 	// view mode: "definitions" or "instances"
 	viewMode string
 
@@ -283,6 +338,89 @@ type model struct {
 	pendingActionID   string            // resolved ID for the pending action
 	pendingActionPath string            // resolved path for the pending action
 
+	// Environment modes: a guarded change awaiting confirmation in a protected
+	// environment, and the confirmation typed into ModalConfirmDelete
+	pendingMutation       func(m *model) tea.Cmd // runs the change once confirmed
+	pendingMutationLabel  string                 // what the change does, e.g. "Complete task Review"
+	pendingMutationID     string                 // resource ID accepted as typed confirmation
+	pendingMutationReturn ModalType              // modal restored after confirm or cancel
+	confirmTyped          string                 // text typed to confirm in a protected environment
+
+	// Mark mode: rows marked with Space receive HTTP actions in bulk
+	markedRows       map[string]bool // marked row IDs (default "id" column)
+	pendingBulkIDs   []string        // IDs the pendingAction runs against once confirmed
+	bulkRunning      bool            // a bulk action is in flight
+	bulkLabel        string          // label of the running or last bulk action
+	bulkTotal        int             // number of rows in the running or last bulk action
+	bulkResults      []bulkResult    // per-row outcomes of the running or last bulk action
+	bulkResultScroll int             // scroll offset of ModalBulkResult
+
+	// Server-side batches: batch actions apply to the whole current filter
+	pendingBatchParams map[string]string // filter the pending batch action is submitted with
+	pendingBatchCount  int               // matching rows at confirmation time (-1 = unknown)
+	trackedBatchID     string            // batch whose progress is polled ("" = none)
+	trackedBatchLabel  string            // label of the action that created the tracked batch
+
+	// Process instance modification wizard (ModalModifyInstance)
+	modify modifyState
+
+	// Process instance migration planner (ModalMigration)
+	migration migrationState
+
+	// Deploy form (ModalDeploy)
+	deploy deployState
+
+	// BPMN diagram view (ModalDiagram)
+	diagram diagramState
+
+	// Instance timeline (ModalTimeline)
+	timeline timelineState
+
+	// Audit log viewer (ModalAudit)
+	audit auditState
+
+	// Saved views (o6n-views.yaml), recalled from the context switcher as @name;
+	// ModalSaveView names the current one. viewsPath "" keeps views in memory.
+	savedViews []config.SavedView
+	viewsPath  string
+	saveView   saveViewState
+
+	// Alert rules (o6n-cfg.yaml alerts), evaluated in every environment every
+	// alertInterval; ModalAlerts lists what fired.
+	alertRules    []config.AlertRule
+	alertInterval time.Duration
+	alerts        alertState
+
+	// Engine dashboard (ModalDashboard), the home context "dashboard"
+	dashboard dashboardState
+
+	// Engine metrics view (ModalMetrics)
+	metrics metricsState
+
+	// Stacktrace viewer (ModalStacktrace)
+	stacktrace stacktraceState
+
+	// Failed-job triage view (ModalTriage)
+	triage triageState
+
+	// Auto-refresh (r): refreshDefault is the --refresh interval for tables
+	// without their own; a toggle bumps refreshGen so older ticks are dropped.
+	refreshDefault time.Duration
+	refreshGen     int
+	refreshFetch   bool    // the pending load was started by the poller
+	rowDiff        rowDiff // changes of the last refresh, highlighted until it expires
+
+	// Command line (: with table aliases and built-ins); cmdHistoryPos browses
+	// cmdHistory with Ctrl+P/Ctrl+N, commandErr explains a rejected command.
+	cmdHistory    []string
+	cmdHistoryPos int
+	commandErr    string
+
+	// Query parameter filter bar (F) and the OpenAPI document it completes from
+	queryBar   queryBarState
+	spec       *apispec.Spec
+	specLoaded bool
+
 	// Edit modal state
 	editInput     textinput.Model
 	editColumns   []editableColumn
@@ -291,6 +429,7 @@ type model struct {
 	editTableKey  string
 	editError     string
 	editFocus     editFocusArea
+	editExternal  string // full value from $EDITOR while the input shows it flattened
 
 	// Search/filter state
 	searchMode   bool
@@ -378,6 +517,9 @@ type model struct {
 
 	// active filter params for the current generic collection view (e.g. drilldown filters)
 	genericParams map[string]string
+	// query parameters set in the filter bar for the current view; appended
+	// after genericParams and shown as chips in the content header
+	queryFilters map[string]string
 
 	// raw API data per visible row; used to resolve drilldown column values
 	// including columns that are not displayed (hidden) in the table
@@ -397,6 +539,7 @@ type model struct {
 
 	// Sort state
 	sortColumn      int // index into visible columns, -1 = unsorted
+	pendingSort     string // sort column title of a restored view, resolved on load
 	sortAscending   bool
 	sortPopupCursor int
 
@@ -564,7 +707,7 @@ func newModel(cfg *config.Config) model {
 	m.table.SetHeight(contentHeight - 1)
 
 	// set root contexts and currentRoot
-	m.rootContexts = loadRootContexts("resources/operaton-rest-api.json")
+	m.rootContexts = loadRootContexts(apiSpecPath)
 	// Filter to only contexts that have a TableDef in config — prevents broken contexts
 	filtered := m.rootContexts[:0]
 	for _, rc := range m.rootContexts {
@@ -574,6 +717,7 @@ func newModel(cfg *config.Config) model {
 	}
 	m.rootContexts = filtered
 	m.currentRoot = dao.ResourceProcessDefinitions
+	m.viewCtx, m.viewCancel = context.WithCancel(context.Background())
 
 	return m
 }
@@ -586,11 +730,7 @@ func newModelEnvApp(envCfg *config.EnvConfig, appCfg *config.AppConfig, skinName
 		Tables:       appCfg.Tables,
 	}
 	for k, v := range envCfg.Environments {
-		cfg.Environments[k] = config.Environment{
-			URL:      v.URL,
-			Username: v.Username,
-			Password: v.Password,
-		}
+		cfg.Environments[k] = v
 	}
 	cfg.Active = envCfg.Active
 	cfg.Skin = envCfg.Skin
@@ -598,6 +738,8 @@ func newModelEnvApp(envCfg *config.EnvConfig, appCfg *config.AppConfig, skinName
 	m := newModel(cfg)
 	m.envConfig = envCfg
 	m.appConfig = appCfg
+	m.alertRules = appCfg.Alerts
+	m.alertInterval = appCfg.AlertInterval
 	m.activeSkin = skinName
 
 	// Normalize skin filename: accept either bare name (e.g. "narsingh") or full filename ("narsingh.yaml").
@@ -698,17 +840,26 @@ func (m model) Init() tea.Cmd {
 		initialFetch = m.fetchForRoot("process-definition")
 	}
 
-	// Check health of all environments
+	// Check health of all environments; their password_cmds were resolved
+	// before the TUI started (see Run), so no command runs from here
 	cmds := []tea.Cmd{initialFetch, flashOnCmd(), firstTick, listSkinsCmd()}
 	for _, envName := range m.envNames {
 		cmds = append(cmds, m.checkEnvironmentHealthCmd(envName))
 	}
 	cmds = append(cmds, tea.Tick(60*time.Second, func(time.Time) tea.Msg { return healthTickMsg{} }))
+	if m.autoRefresh {
+		cmds = append(cmds, m.refreshTickCmd())
+	}
+	if len(m.alertRules) > 0 {
+		cmds = append(cmds, func() tea.Msg { return alertTickMsg{} })
+	}
 
 	// On first run (no saved nav state), queue FirstRunModal after startup.
 	// The modal will appear after the splash; meanwhile, the default data fetch runs in the background.
 	if m.firstRunNeeded {
 		cmds = append(cmds, func() tea.Msg { return openFirstRunMsg{} })
+	} else if m.dashboard.onStart {
+		cmds = append(cmds, func() tea.Msg { return openDashboardMsg{} })
 	}
 
 	return tea.Batch(cmds...)
@@ -0,0 +1,376 @@
+package app
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/kthoms/o6n/internal/client"
+	"github.com/kthoms/o6n/internal/config"
+	"github.com/kthoms/o6n/internal/operaton"
+)
+
+// Modification instruction types accepted by POST /process-instance/{id}/modification.
+const (
+	modifyCancel          = "cancel"
+	modifyStartBefore     = "startBeforeActivity"
+	modifyStartAfter      = "startAfterActivity"
+	modifyStartTransition = "startTransition"
+)
+
+// modifyInputVariable is the inputFor value while a variable is being entered.
+const modifyInputVariable = "variable"
+
+// activityTreeNode is one line of the flattened activity instance tree.
+type activityTreeNode struct {
+	depth        int
+	activityID   string
+	name         string
+	activityType string
+	instanceID   string // activity or transition instance id
+	parentID     string // id of the parent activity instance ("" for the process instance)
+	transition   bool   // a transition instance (execution waiting in an async continuation)
+}
+
+// modifyVariable is a variable set by a start instruction; the engine infers its type.
+type modifyVariable struct {
+	Value interface{} `json:"value"`
+}
+
+// modifyInstruction is one entry of the modification request's instructions array.
+type modifyInstruction struct {
+	Type                       string                    `json:"type"`
+	ActivityID                 string                    `json:"activityId,omitempty"`
+	TransitionID               string                    `json:"transitionId,omitempty"`
+	ActivityInstanceID         string                    `json:"activityInstanceId,omitempty"`
+	TransitionInstanceID       string                    `json:"transitionInstanceId,omitempty"`
+	AncestorActivityInstanceID string                    `json:"ancestorActivityInstanceId,omitempty"`
+	Variables                  map[string]modifyVariable `json:"variables,omitempty"`
+}
+
+// modifyState holds the process instance modification wizard (ModalModifyInstance).
+type modifyState struct {
+	instanceID   string
+	loading      bool
+	nodes        []activityTreeNode
+	cursor       int
+	instructions []modifyInstruction
+	inputFor     string // instruction type (or modifyInputVariable) the input line collects a value for; "" = no input
+	input        textinput.Model
+	err          string
+	confirming   bool // the two-step confirmation was opened from the wizard
+}
+
+// activityTreeLoadedMsg delivers the activity instance tree of a process instance.
+type activityTreeLoadedMsg struct {
+	instanceID string
+	nodes      []activityTreeNode
+	err        error
+}
+
+// flattenActivityTree converts the activity instance tree into display order (depth first).
+func flattenActivityTree(ai operaton.ActivityInstanceDto, depth int) []activityTreeNode {
+	name := client.GetStringValue(ai.ActivityName)
+	if name == "" {
+		name = client.GetStringValue(ai.Name)
+	}
+	nodes := []activityTreeNode{{
+		depth:        depth,
+		activityID:   client.GetStringValue(ai.ActivityId),
+		name:         name,
+		activityType: client.GetStringValue(ai.ActivityType),
+		instanceID:   client.GetStringValue(ai.Id),
+		parentID:     client.GetStringValue(ai.ParentActivityInstanceId),
+	}}
+	for _, child := range ai.ChildActivityInstances {
+		nodes = append(nodes, flattenActivityTree(child, depth+1)...)
+	}
+	for _, ti := range ai.ChildTransitionInstances {
+		nodes = append(nodes, activityTreeNode{
+			depth:        depth + 1,
+			activityID:   client.GetStringValue(ti.ActivityId),
+			name:         client.GetStringValue(ti.ActivityName),
+			activityType: client.GetStringValue(ti.ActivityType),
+			instanceID:   client.GetStringValue(ti.Id),
+			parentID:     client.GetStringValue(ti.ParentActivityInstanceId),
+			transition:   true,
+		})
+	}
+	return nodes
+}
+
+// fetchActivityTreeCmd loads GET /process-instance/{id}/activity-instances.
+func (m model) fetchActivityTreeCmd(instanceID string) tea.Cmd {
+	env, ok := m.config.Environments[m.currentEnv]
+	if !ok {
+		return nil
+	}
+	c := client.NewClient(env, m.debugEnabled)
+	return func() tea.Msg {
+		tree, _, err := c.OperatonAPI().ProcessInstanceAPI.GetActivityInstanceTree(c.AuthContext(), instanceID).Execute()
+		if err != nil {
+			return activityTreeLoadedMsg{instanceID: instanceID, err: fmt.Errorf("activity instances: %w", err)}
+		}
+		return activityTreeLoadedMsg{instanceID: instanceID, nodes: flattenActivityTree(*tree, 0)}
+	}
+}
+
+// openModifyInstance opens the modification wizard for the selected process instance.
+func (m *model) openModifyInstance() tea.Cmd {
+	id := m.resolveActionID(config.ActionDef{})
+	if id == "" {
+		return nil
+	}
+	input := textinput.New()
+	input.Prompt = "> "
+	input.CharLimit = 0
+	input.Width = 50
+	m.modify = modifyState{instanceID: id, loading: true, input: input}
+	m.activeModal = ModalModifyInstance
+	return m.fetchActivityTreeCmd(id)
+}
+
+// modifySelectedNode returns the activity tree node under the wizard cursor.
+func (m *model) modifySelectedNode() (activityTreeNode, bool) {
+	if m.modify.cursor < 0 || m.modify.cursor >= len(m.modify.nodes) {
+		return activityTreeNode{}, false
+	}
+	return m.modify.nodes[m.modify.cursor], true
+}
+
+// startModifyInput shows the input line collecting a value for kind, prefilled with value.
+func (m *model) startModifyInput(kind, placeholder, value string) tea.Cmd {
+	m.modify.inputFor = kind
+	m.modify.err = ""
+	m.modify.input.Placeholder = placeholder
+	m.modify.input.SetValue(value)
+	m.modify.input.CursorEnd()
+	return m.modify.input.Focus()
+}
+
+// commitModifyInput turns the input line into an instruction or a variable.
+func (m *model) commitModifyInput() {
+	value := strings.TrimSpace(m.modify.input.Value())
+	kind := m.modify.inputFor
+	if value == "" {
+		m.modify.err = "a value is required"
+		return
+	}
+	switch kind {
+	case modifyInputVariable:
+		name, raw, ok := strings.Cut(value, "=")
+		name = strings.TrimSpace(name)
+		if !ok || name == "" {
+			m.modify.err = "expected name=value"
+			return
+		}
+		last := &m.modify.instructions[len(m.modify.instructions)-1]
+		if last.Variables == nil {
+			last.Variables = make(map[string]modifyVariable)
+		}
+		last.Variables[name] = modifyVariable{Value: parseModifyValue(strings.TrimSpace(raw))}
+	case modifyStartTransition:
+		m.modify.instructions = append(m.modify.instructions, modifyInstruction{Type: kind, TransitionID: value})
+	default:
+		ins := modifyInstruction{Type: kind, ActivityID: value}
+		// Restarting the selected activity: scope it to the same parent instance
+		if node, ok := m.modifySelectedNode(); ok && node.activityID == value && node.parentID != "" {
+			ins.AncestorActivityInstanceID = node.parentID
+		}
+		m.modify.instructions = append(m.modify.instructions, ins)
+	}
+	m.modify.inputFor = ""
+	m.modify.err = ""
+	m.modify.input.Blur()
+}
+
+// parseModifyValue interprets a variable value as JSON (numbers, booleans, quoted
+// strings, objects) and falls back to the raw string.
+func parseModifyValue(raw string) interface{} {
+	var v interface{}
+	if err := json.Unmarshal([]byte(raw), &v); err == nil {
+		return v
+	}
+	return raw
+}
+
+// modificationBody renders the JSON request body for the wizard's instructions.
+func (m *model) modificationBody() string {
+	body := struct {
+		Instructions []modifyInstruction `json:"instructions"`
+	}{Instructions: m.modify.instructions}
+	if body.Instructions == nil {
+		body.Instructions = []modifyInstruction{}
+	}
+	b, _ := json.MarshalIndent(body, "", "  ")
+	return string(b)
+}
+
+// submitModification hands the request to the two-step confirmation.
+func (m *model) submitModification() {
+	if len(m.modify.instructions) == 0 {
+		m.modify.err = "add at least one instruction"
+		return
+	}
+	path := fmt.Sprintf("/process-instance/%s/modification", m.modify.instanceID)
+	m.pendingAction = &config.ActionDef{
+		Label:  "Modify Instance",
+		Method: "POST",
+		Path:   path,
+		Body:   m.modificationBody(),
+	}
+	m.pendingActionID = m.modify.instanceID
+	m.pendingActionPath = path
+	m.modify.confirming = true
+	m.activeModal = ModalConfirmDelete
+	m.confirmFocusedBtn = 1 // default to Cancel (safe)
+}
+
+// renderModificationConfirmBody renders the two-step confirmation of the
+// wizard's modification request with its JSON body.
+func (m *model) renderModificationConfirmBody() string {
+	var b strings.Builder
+	b.WriteString("⚠️  MODIFY PROCESS INSTANCE\n\n")
+	fmt.Fprintf(&b, "You are about to run POST %s\n\n", m.pendingActionPath)
+	fmt.Fprintf(&b, "ID:            %s\n\n", m.pendingActionID)
+	b.WriteString(m.pendingAction.Body + "\n")
+	return b.String()
+}
+
+// handleModifyKey processes a key press while ModalModifyInstance is open.
+func (m *model) handleModifyKey(msg tea.KeyMsg) tea.Cmd {
+	s := msg.String()
+	if m.modify.inputFor != "" {
+		switch s {
+		case "esc":
+			m.modify.inputFor = ""
+			m.modify.err = ""
+			m.modify.input.Blur()
+		case "enter":
+			m.commitModifyInput()
+		default:
+			var cmd tea.Cmd
+			m.modify.input, cmd = m.modify.input.Update(msg)
+			return cmd
+		}
+		return nil
+	}
+
+	node, hasNode := m.modifySelectedNode()
+	switch s {
+	case "esc", "q":
+		m.activeModal = ModalNone
+		m.modify = modifyState{}
+	case "up", "k":
+		if m.modify.cursor > 0 {
+			m.modify.cursor--
+		}
+	case "down", "j":
+		if m.modify.cursor < len(m.modify.nodes)-1 {
+			m.modify.cursor++
+		}
+	case "c":
+		if !hasNode {
+			return nil
+		}
+		ins := modifyInstruction{Type: modifyCancel, ActivityInstanceID: node.instanceID}
+		if node.transition {
+			ins = modifyInstruction{Type: modifyCancel, TransitionInstanceID: node.instanceID}
+		}
+		m.modify.instructions = append(m.modify.instructions, ins)
+		m.modify.err = ""
+	case "b":
+		return m.startModifyInput(modifyStartBefore, "activity id to start before", node.activityID)
+	case "a":
+		return m.startModifyInput(modifyStartAfter, "activity id to start after", node.activityID)
+	case "t":
+		return m.startModifyInput(modifyStartTransition, "sequence flow id", "")
+	case "v":
+		n := len(m.modify.instructions)
+		if n == 0 || m.modify.instructions[n-1].Type == modifyCancel {
+			m.modify.err = "variables can only be added to a start instruction"
+			return nil
+		}
+		return m.startModifyInput(modifyInputVariable, "name=value", "")
+	case "x", "backspace":
+		if n := len(m.modify.instructions); n > 0 {
+			m.modify.instructions = m.modify.instructions[:n-1]
+		}
+	case "enter", "ctrl+s":
+		m.submitModification()
+	}
+	return nil
+}
+
+// describeModifyInstruction renders an instruction as one summary line.
+func describeModifyInstruction(ins modifyInstruction) string {
+	var target string
+	switch {
+	case ins.ActivityInstanceID != "":
+		target = "activityInstance " + ins.ActivityInstanceID
+	case ins.TransitionInstanceID != "":
+		target = "transitionInstance " + ins.TransitionInstanceID
+	case ins.TransitionID != "":
+		target = ins.TransitionID
+	default:
+		target = ins.ActivityID
+	}
+	line := fmt.Sprintf("%-20s %s", ins.Type, target)
+	if len(ins.Variables) > 0 {
+		line += fmt.Sprintf("  (+%d variables)", len(ins.Variables))
+	}
+	return line
+}
+
+// modalModifyInstanceBody renders the wizard: activity tree, instructions and JSON preview.
+func (m *model) modalModifyInstanceBody() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Modify process instance %s\n\n", m.modify.instanceID)
+
+	b.WriteString(m.styles.Accent.Render("ACTIVITY TREE") + "\n")
+	switch {
+	case m.modify.loading:
+		b.WriteString("  Loading…\n")
+	case len(m.modify.nodes) == 0:
+		b.WriteString("  (no active activities)\n")
+	}
+	for i, n := range m.modify.nodes {
+		prefix := "  "
+		if i == m.modify.cursor {
+			prefix = "> "
+		}
+		label := n.activityID
+		if n.name != "" && n.name != n.activityID {
+			label = fmt.Sprintf("%s (%s)", n.name, n.activityID)
+		}
+		if n.transition {
+			label += " ⏸ async"
+		}
+		line := fmt.Sprintf("%s%s%s  %s", prefix, strings.Repeat("  ", n.depth), label, m.styles.FgMuted.Render(n.activityType))
+		if i == m.modify.cursor {
+			line = m.styles.PopupCursor.Render(line)
+		}
+		b.WriteString(line + "\n")
+	}
+
+	b.WriteString("\n" + m.styles.Accent.Render("INSTRUCTIONS") + "\n")
+	if len(m.modify.instructions) == 0 {
+		b.WriteString(m.styles.FgMuted.Render("  c cancel  b start before  a start after  t start transition  v add variable") + "\n")
+	}
+	for i, ins := range m.modify.instructions {
+		fmt.Fprintf(&b, "  %d. %s\n", i+1, describeModifyInstruction(ins))
+	}
+
+	b.WriteString("\n" + m.styles.Accent.Render("REQUEST BODY") + "\n")
+	b.WriteString(m.modificationBody() + "\n")
+
+	if m.modify.inputFor != "" {
+		b.WriteString("\n" + m.modify.inputFor + ": " + m.modify.input.View() + "\n")
+	}
+	if m.modify.err != "" {
+		b.WriteString("\n" + m.styles.ValidationError.Render(m.modify.err) + "\n")
+	}
+	return b.String()
+}
@@ -0,0 +1,178 @@
+package app
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/bubbles/table"
+	"github.com/kthoms/o6n/internal/config"
+	"github.com/kthoms/o6n/internal/operaton"
+)
+
+func TestFlattenActivityTreeDepthFirstWithTransitions(t *testing.T) {
+	var tree operaton.ActivityInstanceDto
+	raw := `{
+		"id": "pi-1", "activityId": "invoice", "activityType": "processDefinition",
+		"childActivityInstances": [
+			{"id": "sub:1", "parentActivityInstanceId": "pi-1", "activityId": "sub", "activityType": "subProcess",
+			 "childActivityInstances": [
+				{"id": "review:1", "parentActivityInstanceId": "sub:1", "activityId": "review", "activityName": "Review", "activityType": "userTask"}
+			 ]}
+		],
+		"childTransitionInstances": [
+			{"id": "ti-1", "parentActivityInstanceId": "pi-1", "activityId": "archive", "activityType": "serviceTask"}
+		]
+	}`
+	if err := json.Unmarshal([]byte(raw), &tree); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	nodes := flattenActivityTree(tree, 0)
+	var got []string
+	for _, n := range nodes {
+		got = append(got, strings.Repeat(".", n.depth)+n.activityID)
+	}
+	if strings.Join(got, ",") != "invoice,.sub,..review,.archive" {
+		t.Fatalf("unexpected order %v", got)
+	}
+	if !nodes[3].transition || nodes[3].instanceID != "ti-1" {
+		t.Errorf("expected transition instance last, got %+v", nodes[3])
+	}
+	if nodes[2].name != "Review" || nodes[2].parentID != "sub:1" {
+		t.Errorf("unexpected node %+v", nodes[2])
+	}
+}
+
+// modifyTestModel returns a model with the wizard open on a loaded activity tree.
+func modifyTestModel(t *testing.T, url string) model {
+	t.Helper()
+	m := tableTestModel(t, url, "process-instance", []string{"id"}, []table.Row{{"pi-1"}})
+
+	var item actionItem
+	for _, it := range m.buildActionsForRoot() {
+		if it.key == "m" {
+			item = it
+		}
+	}
+	if item.cmd == nil {
+		t.Fatal("expected Modify Instance in the process-instance actions")
+	}
+	item.cmd(&m)
+	if m.activeModal != ModalModifyInstance || m.modify.instanceID != "pi-1" {
+		t.Fatalf("expected wizard for pi-1, got modal %v", m.activeModal)
+	}
+	res, _ := m.Update(activityTreeLoadedMsg{instanceID: "pi-1", nodes: []activityTreeNode{
+		{depth: 0, activityID: "invoice", instanceID: "pi-1"},
+		{depth: 1, activityID: "review", instanceID: "review:1", parentID: "pi-1"},
+	}})
+	return res.(model)
+}
+
+func TestModifyWizardBuildsInstructionsAndPreview(t *testing.T) {
+	m := modifyTestModel(t, "http://localhost:0")
+
+	m, _ = sendKeyString(m, "down")
+	m, _ = sendKeyString(m, "c")
+	m, _ = sendKeyString(m, "b")
+	if m.modify.inputFor != modifyStartBefore || m.modify.input.Value() != "review" {
+		t.Fatalf("expected start-before input prefilled with review, got %q %q", m.modify.inputFor, m.modify.input.Value())
+	}
+	m, _ = sendKeyString(m, "enter")
+	m, _ = sendKeyString(m, "v")
+	m, _ = sendKeyString(m, "amount=42")
+	m, _ = sendKeyString(m, "enter")
+
+	if len(m.modify.instructions) != 2 {
+		t.Fatalf("expected 2 instructions, got %+v", m.modify.instructions)
+	}
+	var body struct {
+		Instructions []map[string]interface{} `json:"instructions"`
+	}
+	if err := json.Unmarshal([]byte(m.modificationBody()), &body); err != nil {
+		t.Fatalf("preview is not valid JSON: %v", err)
+	}
+	cancel, start := body.Instructions[0], body.Instructions[1]
+	if cancel["type"] != "cancel" || cancel["activityInstanceId"] != "review:1" {
+		t.Errorf("unexpected cancel instruction %v", cancel)
+	}
+	if start["type"] != "startBeforeActivity" || start["activityId"] != "review" || start["ancestorActivityInstanceId"] != "pi-1" {
+		t.Errorf("unexpected start instruction %v", start)
+	}
+	vars, _ := start["variables"].(map[string]interface{})
+	if amount, _ := vars["amount"].(map[string]interface{}); amount["value"] != 42.0 {
+		t.Errorf("expected typed variable amount=42, got %v", start["variables"])
+	}
+	if view := m.modalModifyInstanceBody(); !strings.Contains(view, `"startBeforeActivity"`) {
+		t.Errorf("expected JSON preview in the wizard, got %q", view)
+	}
+
+	m, _ = sendKeyString(m, "x")
+	if len(m.modify.instructions) != 1 {
+		t.Errorf("expected x to remove the last instruction, got %d", len(m.modify.instructions))
+	}
+}
+
+func TestModifyWizardVariableNeedsStartInstruction(t *testing.T) {
+	m := modifyTestModel(t, "http://localhost:0")
+	m, _ = sendKeyString(m, "c")
+	m, _ = sendKeyString(m, "v")
+	if m.modify.inputFor != "" || m.modify.err == "" {
+		t.Error("expected variables to be refused for a cancel instruction")
+	}
+	m, _ = sendKeyString(m, "x")
+	m, _ = sendKeyString(m, "enter")
+	if m.activeModal != ModalModifyInstance || !strings.Contains(m.modify.err, "at least one") {
+		t.Error("expected submit without instructions to be refused")
+	}
+}
+
+func TestModifyWizardSubmitsThroughTwoStepConfirm(t *testing.T) {
+	var gotPath, gotBody string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		gotPath, gotBody = r.Method+" "+r.URL.Path, string(b)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	m := modifyTestModel(t, srv.URL)
+	m, _ = sendKeyString(m, "down")
+	m, _ = sendKeyString(m, "c")
+	m, _ = sendKeyString(m, "enter")
+	if m.activeModal != ModalConfirmDelete || m.pendingAction == nil {
+		t.Fatalf("expected two-step confirmation, got modal %v", m.activeModal)
+	}
+	if confirm := m.renderConfirmDeleteModal(0, 0); !strings.Contains(confirm, "/process-instance/pi-1/modification") || !strings.Contains(confirm, "review:1") {
+		t.Errorf("expected path and body in confirmation, got %q", confirm)
+	}
+
+	// Cancelling returns to the wizard with instructions intact
+	m, _ = sendKeyString(m, "esc")
+	if m.activeModal != ModalModifyInstance || len(m.modify.instructions) != 1 {
+		t.Fatalf("expected to return to the wizard, got modal %v", m.activeModal)
+	}
+
+	m, _ = sendKeyString(m, "enter")
+	m, cmd := sendKeyString(m, "ctrl+d")
+	if m.activeModal != ModalNone {
+		t.Errorf("expected confirmation closed, got %v", m.activeModal)
+	}
+	findMsg[actionExecutedMsg](t, cmd)
+	if gotPath != "POST /process-instance/pi-1/modification" || !strings.Contains(gotBody, `"activityInstanceId": "review:1"`) {
+		t.Errorf("unexpected request %s %s", gotPath, gotBody)
+	}
+}
+
+func TestConfirmOfOtherActionsKeepsDeleteWarning(t *testing.T) {
+	m := newTestModel(t)
+	m.pendingAction = &config.ActionDef{Label: "Delete", Method: "DELETE", Path: "/process-instance/{id}", Confirm: true}
+	m.pendingActionID = "pi-1"
+	m.pendingActionPath = "/process-instance/pi-1"
+	m.activeModal = ModalConfirmDelete
+	if confirm := m.renderConfirmDeleteModal(0, 0); !strings.Contains(confirm, "CANNOT be undone") {
+		t.Errorf("expected the delete warning outside the modification wizard, got %q", confirm)
+	}
+}
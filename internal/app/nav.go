@@ -13,13 +13,23 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/x/ansi"
 	"github.com/kthoms/o6n/internal/config"
+	"github.com/kthoms/o6n/internal/dao"
 )
 
 // resolveActionID extracts the ID value from the selected row for a given action.
 // It uses the action's IDColumn (defaults to "id") and finds the matching table column.
 // For hidden columns, it checks m.rowData as a fallback.
 func (m *model) resolveActionID(action config.ActionDef) string {
-	row := m.table.SelectedRow()
+	return m.resolveActionIDAt(action, m.table.Cursor())
+}
+
+// resolveActionIDAt is resolveActionID for the table row at index idx.
+func (m *model) resolveActionIDAt(action config.ActionDef, idx int) string {
+	rows := m.table.Rows()
+	if idx < 0 || idx >= len(rows) {
+		return ""
+	}
+	row := rows[idx]
 	if len(row) == 0 {
 		return ""
 	}
@@ -27,16 +37,12 @@ func (m *model) resolveActionID(action config.ActionDef) string {
 	if idCol == "" {
 		idCol = "id"
 	}
-	cols := m.table.Columns()
-	for i, col := range cols {
-		if col.Title == idCol && i < len(row) {
-			return stripFocusIndicatorPrefix(row[i])
-		}
+	if v, ok := m.cellAt(idx, idCol); ok {
+		return v
 	}
-	// Fallback: check m.rowData for hidden columns
-	cursor := m.table.Cursor()
-	if cursor >= 0 && cursor < len(m.rowData) {
-		if v, ok := m.rowData[cursor][idCol]; ok {
+	// Fallback: check the row's item for hidden columns
+	if item := m.rowDataAt(idx); item != nil {
+		if v, ok := item[idCol]; ok {
 			if v == nil {
 				return ""
 			}
@@ -47,10 +53,51 @@ func (m *model) resolveActionID(action config.ActionDef) string {
 		}
 	}
 	// Fallback: use first column
-	if len(row) > 0 {
-		return stripFocusIndicatorPrefix(row[0])
+	return stripFocusIndicatorPrefix(ansi.Strip(row[0]))
+}
+
+// columnTitleIs reports whether a column title as rendered by buildColumnsFor
+// (upper-cased, maybe with editable marker and sort indicator) names column name.
+func columnTitleIs(title, name string) bool {
+	title = strings.TrimSuffix(stripSortIndicator(title), " ✎")
+	return strings.EqualFold(stripFocusIndicatorPrefix(title), name)
+}
+
+// cellAt returns the plain cell of table row idx in the visible column name.
+func (m *model) cellAt(idx int, name string) (string, bool) {
+	rows := m.table.Rows()
+	if idx < 0 || idx >= len(rows) {
+		return "", false
 	}
-	return ""
+	for i, col := range m.table.Columns() {
+		if columnTitleIs(col.Title, name) && i < len(rows[idx]) {
+			return stripFocusIndicatorPrefix(ansi.Strip(rows[idx][i])), true
+		}
+	}
+	return "", false
+}
+
+// rowDataAt returns the item behind table row idx. Rows are in m.rowData order
+// unless the table is sorted or filtered locally; then the item is found by
+// the row's visible id, or nil without one.
+func (m *model) rowDataAt(idx int) map[string]interface{} {
+	rows := m.table.Rows()
+	if idx < 0 || idx >= len(rows) {
+		return nil
+	}
+	if m.sortColumn < 0 && m.originalRows == nil && m.searchTerm == "" && len(rows) == len(m.rowData) {
+		return m.rowData[idx]
+	}
+	id, ok := m.cellAt(idx, "id")
+	if !ok || id == "" {
+		return nil
+	}
+	for _, it := range m.rowData {
+		if rowKey(it) == id {
+			return it
+		}
+	}
+	return nil
 }
 
 // resolveRowValue returns the value in a row for the given column name.
@@ -147,18 +194,36 @@ func (m *model) buildActionsForRoot() []actionItem {
 								return cmd
 							},
 						})
+					} else if act.Type == "batch" {
+						// Server-side batch over the whole current filter; always confirmed
+						items = append(items, actionItem{
+							key:     act.Key,
+							label:   act.Label + " ⧉",
+							mutates: true,
+							cmd: func(m *model) tea.Cmd {
+								return m.confirmBatchAction(act)
+							},
+						})
 					} else {
-						// HTTP mutation action (existing logic)
+						// HTTP mutation action; runs on all marked rows when marks exist
+						label := act.Label
+						if n := len(m.markedIndexes()); n > 0 {
+							label = fmt.Sprintf("%s (%d marked)", act.Label, n)
+						}
 						items = append(items, actionItem{
-							key:   act.Key,
-							label: act.Label,
+							key:     act.Key,
+							label:   label,
+							mutates: true,
 							cmd: func(m *model) tea.Cmd {
+								if ids := m.markedActionIDs(act); len(ids) > 0 {
+									return m.confirmBulkAction(act, ids)
+								}
 								id := m.resolveActionID(act)
 								if id == "" {
 									return nil
 								}
-								resolvedPath := strings.Replace(act.Path, "{id}", id, 1)
-								if act.Confirm {
+								resolvedPath := resolveActionPath(act, id)
+								if act.Confirm || m.protected() {
 									m.pendingAction = &act
 									m.pendingActionID = id
 									m.pendingActionPath = resolvedPath
@@ -176,6 +241,18 @@ func (m *model) buildActionsForRoot() []actionItem {
 		}
 	}
 
+	items = append(items, m.builtinActionsForRoot(m.currentRoot)...)
+
+	if m.readOnly() {
+		kept := items[:0]
+		for _, it := range items {
+			if !it.mutates {
+				kept = append(kept, it)
+			}
+		}
+		items = kept
+	}
+
 	// Always add "View as JSON" and "Copy as JSON" as the last two actions
 	items = append(items, actionItem{key: "J", label: "View as JSON", cmd: func(m *model) tea.Cmd {
 		row := m.table.SelectedRow()
@@ -204,6 +281,59 @@ func (m *model) buildActionsForRoot() []actionItem {
 	return items
 }
 
+// builtinActionsForRoot returns the actions o6n provides itself for a resource,
+// in addition to the config-driven ones.
+func (m *model) builtinActionsForRoot(root string) []actionItem {
+	switch root {
+	case "process-instance", "process-instances":
+		return []actionItem{
+			{key: "m", label: "Modify Instance…", mutates: true, cmd: func(m *model) tea.Cmd {
+				return m.openModifyInstance()
+			}},
+			{key: "M", label: "Migrate Instances…", mutates: true, cmd: func(m *model) tea.Cmd {
+				return m.openMigrationFromInstances()
+			}},
+			{key: "g", label: "Diagram", cmd: func(m *model) tea.Cmd {
+				return m.openDiagram()
+			}},
+			{key: "T", label: "Timeline", cmd: func(m *model) tea.Cmd {
+				return m.openTimeline()
+			}},
+		}
+	case "process-definition", "process-definitions":
+		return []actionItem{
+			{key: "M", label: "Migrate Instances…", mutates: true, cmd: func(m *model) tea.Cmd {
+				return m.openMigrationFromDefinition()
+			}},
+			{key: "g", label: "Diagram", cmd: func(m *model) tea.Cmd {
+				return m.openDiagram()
+			}},
+		}
+	case "history-process-instance":
+		return []actionItem{{key: "T", label: "Timeline", cmd: func(m *model) tea.Cmd {
+			return m.openTimeline()
+		}}}
+	case "job", "jobs":
+		return []actionItem{
+			{key: "e", label: "Stacktrace", cmd: func(m *model) tea.Cmd {
+				return m.openStacktrace()
+			}},
+			{key: "T", label: "Triage Failed Jobs", cmd: func(m *model) tea.Cmd {
+				return m.openTriage()
+			}},
+		}
+	case "external-task", "external-tasks", "history-job-log", "incident", "incidents":
+		return []actionItem{{key: "e", label: "Stacktrace", cmd: func(m *model) tea.Cmd {
+			return m.openStacktrace()
+		}}}
+	case "deployment":
+		return []actionItem{{key: "n", label: "Deploy Files…", mutates: true, cmd: func(m *model) tea.Cmd {
+			return m.openDeployForm()
+		}}}
+	}
+	return nil
+}
+
 // buildDetailContent builds a JSON representation of the selected row.
 func (m *model) buildDetailContent(row table.Row) string {
 	// Prefer full raw API object when available
@@ -236,9 +366,59 @@ func (m *model) buildDetailContent(row table.Row) string {
 // switchToEnvironment switches to the named environment (extracted from cycling logic).
 func (m *model) switchToEnvironment(name string) {
 	m.currentEnv = name
+	m.trackedBatchID = "" // batches are tracked in the environment they were submitted to
 	m.applyStyle()
 }
 
+// openTable switches to the table root, filtered by the query filters, and
+// loads it.
+func (m *model) openTable(root string, filters map[string]string) tea.Cmd {
+	return m.openTableWithParams(root, nil, filters)
+}
+
+// openTableWithParams is openTable with drilldown params scoping the table.
+func (m *model) openTableWithParams(root string, params, filters map[string]string) tea.Cmd {
+	m.prepareStateTransition(TransitionFull)
+	m.currentRoot = root
+	m.breadcrumb = []string{root}
+	m.contentHeader = root
+	m.viewMode = root
+	if len(params) > 0 {
+		m.genericParams = params
+	}
+	if len(filters) > 0 {
+		m.queryFilters = filters
+	}
+
+	// Set columns immediately if table def exists
+	if def := m.findTableDef(root); def != nil {
+		cols := m.buildColumnsFor(root, m.paneWidth-4)
+		if len(cols) > 0 {
+			m.table.SetRows([]table.Row{})
+			m.table.SetColumns(cols)
+			m.table.SetRows(normalizeRows(nil, len(cols)))
+		}
+	}
+	m.table.SetCursor(0)
+
+	m.isLoading = true
+	m.apiCallStarted = time.Now()
+	return tea.Batch(m.fetchForRoot(root), flashOnCmd(), spinnerTickCmd(), m.saveStateCmd())
+}
+
+// changeEnvironment switches to the environment name and reloads the process
+// definitions there.
+func (m *model) changeEnvironment(name string) tea.Cmd {
+	m.switchToEnvironment(name)
+	m.prepareStateTransition(TransitionFull)
+	m.currentRoot = dao.ResourceProcessDefinitions
+	m.contentHeader = dao.ResourceProcessDefinitions
+	m.breadcrumb = []string{m.currentRoot}
+	m.isLoading = true
+	m.apiCallStarted = time.Now()
+	return tea.Batch(m.fetchDefinitionsCmd(), flashOnCmd(), m.checkEnvironmentHealthCmd(name), spinnerTickCmd(), m.saveStateCmd())
+}
+
 func (m *model) resetViews() {
 	m.list.SetItems([]list.Item{})
 	m.table.SetRows([]table.Row{})
@@ -360,6 +540,7 @@ func (m *model) contextPopupHeight() int {
 func (m *model) computePaneHeight() int {
 	h := m.lastHeight - 2 - 2 - 1 // header(2) - footer(2) - safe(1)
 	h -= m.contextPopupHeight()
+	h -= m.queryBarHeight()
 	if m.searchMode {
 		h -= 1
 	}
@@ -479,6 +660,26 @@ func (m model) executeDrilldown(d *config.DrillDownDef) (model, tea.Cmd) {
 	return m, tea.Batch(m.fetchGenericCmd(d.Target), flashOnCmd(), m.saveStateCmd())
 }
 
+// drillDownTo pushes the current view and opens the target table filtered by params,
+// like a config drilldown whose value does not come from the selected row.
+func (m *model) drillDownTo(target, label, title string, params map[string]string) tea.Cmd {
+	m.prepareStateTransition(TransitionDrillDown)
+	m.currentRoot = target
+	m.viewMode = target
+	m.genericParams = params
+	m.breadcrumb = append(m.breadcrumb, label)
+	m.contentHeader = fmt.Sprintf("%s — %s", target, title)
+
+	cols := m.buildColumnsFor(target, m.paneWidth-4)
+	m.table.SetRows([]table.Row{})
+	if len(cols) > 0 {
+		m.table.SetColumns(cols)
+		m.table.SetRows(normalizeRows(nil, len(cols)))
+	}
+	m.table.SetCursor(0)
+	return tea.Batch(m.fetchGenericCmd(target), flashOnCmd(), m.saveStateCmd())
+}
+
 // currentNavState returns the current navigation position as a serialisable NavState.
 func (m *model) currentNavState() config.NavState {
 	return config.NavState{
@@ -487,6 +688,7 @@ func (m *model) currentNavState() config.NavState {
 		SelectedDefinitionKey: m.selectedDefinitionKey,
 		SelectedInstanceID:    m.selectedInstanceID,
 		GenericParams:         m.genericParams,
+		QueryFilters:          m.queryFilters,
 	}
 }
 
@@ -497,6 +699,9 @@ func (m *model) currentAppState() *config.AppState {
 		Skin:        m.activeSkin,
 		ShowLatency: m.showLatency,
 		Navigation:  m.currentNavState(),
+
+		HomeDashboard:  m.dashboard.home,
+		CommandHistory: m.cmdHistory,
 	}
 }
 
@@ -522,17 +727,20 @@ func (m *model) restoreNavState(nav config.NavState) {
 	if nav.GenericParams != nil {
 		m.genericParams = nav.GenericParams
 	}
+	m.queryFilters = nav.QueryFilters
 	// viewMode is set by the first genericLoadedMsg received after Init.
 }
 
-// filteredFirstRunContexts returns m.rootContexts filtered by m.firstRunInput.
-// Returns all contexts when input is empty; filters by substring match otherwise.
+// filteredFirstRunContexts returns m.rootContexts and the dashboard filtered by
+// m.firstRunInput. Returns all contexts when input is empty; filters by
+// substring match otherwise.
 func (m *model) filteredFirstRunContexts() []string {
+	contexts := append(append([]string(nil), m.rootContexts...), dashboardContext)
 	if m.firstRunInput == "" {
-		return m.rootContexts
+		return contexts
 	}
 	var out []string
-	for _, rc := range m.rootContexts {
+	for _, rc := range contexts {
 		if strings.Contains(rc, m.firstRunInput) {
 			out = append(out, rc)
 		}
@@ -546,13 +754,7 @@ func (m *model) filteredFirstRunContexts() []string {
 // For search mode: first-column values of current table rows.
 func (m *model) popupItems() []string {
 	if m.activeModal == ModalContextSwitcher {
-		var out []string
-		for _, rc := range m.rootContexts {
-			if m.popup.input == "" || strings.Contains(rc, m.popup.input) {
-				out = append(out, rc)
-			}
-		}
-		return out
+		return m.commandItems()
 	}
 
 	switch m.popup.mode {
@@ -0,0 +1,412 @@
+package app
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/kthoms/o6n/internal/apispec"
+)
+
+// queryBarMaxSuggestions bounds the completion list of the filter bar.
+const queryBarMaxSuggestions = 6
+
+// queryFilter is one name=value query parameter of the filter bar.
+type queryFilter struct {
+	name  string
+	value string
+}
+
+// queryBarState holds the query parameter filter bar (F). Chips are the
+// filters that Enter applies; input holds further name=value tokens.
+type queryBarState struct {
+	active bool
+	chips  []queryFilter
+	input  string
+	cursor int // selected suggestion
+	err    string
+	params []apispec.Param // GET parameters of the current table
+	known  bool            // params come from the OpenAPI document
+}
+
+// querySuggestion is one completion of the filter bar.
+type querySuggestion struct {
+	insert string // replaces the token being typed
+	label  string
+	detail string
+}
+
+// apiSpec returns the parsed OpenAPI document, loading it on first use. It is
+// nil when the document is missing or malformed.
+func (m *model) apiSpec() *apispec.Spec {
+	if m.spec == nil && !m.specLoaded {
+		m.specLoaded = true
+		if spec, err := apispec.Load(apiSpecPath); err == nil {
+			m.spec = spec
+		}
+	}
+	return m.spec
+}
+
+// viewParams returns the query of the current view: the drill-down params with
+// the filter bar's filters on top.
+func (m *model) viewParams() map[string]string {
+	params := make(map[string]string, len(m.genericParams)+len(m.queryFilters))
+	for k, v := range m.genericParams {
+		params[k] = v
+	}
+	for k, v := range m.queryFilters {
+		params[k] = v
+	}
+	return params
+}
+
+// scopeParams returns the query selecting the rows the table shows: viewParams
+// plus an active search term as the table's search_param. It is not ok while a
+// search is active on a table without search_param, as no query matches it.
+func (m *model) scopeParams() (map[string]string, bool) {
+	params := m.viewParams()
+	if m.searchTerm == "" {
+		return params, true
+	}
+	def := m.findTableDef(m.currentRoot)
+	if def == nil || def.SearchParam == "" {
+		return nil, false
+	}
+	params[def.SearchParam] = m.searchTerm
+	return params, true
+}
+
+// sortedQueryFilters returns filters ordered by name.
+func sortedQueryFilters(filters map[string]string) []queryFilter {
+	out := make([]queryFilter, 0, len(filters))
+	for k, v := range filters {
+		out = append(out, queryFilter{name: k, value: v})
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].name < out[j].name })
+	return out
+}
+
+// openQueryBar opens the filter bar with the active filters as chips.
+func (m *model) openQueryBar() {
+	apiPath, _ := m.tablePaths(m.currentRoot)
+	params, known := m.apiSpec().QueryParams(apiPath)
+	m.queryBar = queryBarState{
+		active: true,
+		chips:  sortedQueryFilters(m.queryFilters),
+		params: params,
+		known:  known,
+	}
+	m.paneHeight = m.computePaneHeight()
+	m.table.SetHeight(m.paneHeight - 1)
+}
+
+// closeQueryBar closes the filter bar without applying it.
+func (m *model) closeQueryBar() {
+	m.queryBar = queryBarState{}
+	m.paneHeight = m.computePaneHeight()
+	m.table.SetHeight(m.paneHeight - 1)
+}
+
+// queryParam returns the OpenAPI parameter name of the current table.
+func (m *model) queryParam(name string) (apispec.Param, bool) {
+	return findParam(m.queryBar.params, name)
+}
+
+func findParam(params []apispec.Param, name string) (apispec.Param, bool) {
+	for _, p := range params {
+		if p.Name == name {
+			return p, true
+		}
+	}
+	return apispec.Param{}, false
+}
+
+// validateFilter checks name=value against the GET parameters of table; known is
+// false when the OpenAPI document does not describe the table, and anything goes.
+func validateFilter(params []apispec.Param, known bool, table, name, value string) error {
+	if !known {
+		return nil
+	}
+	p, found := findParam(params, name)
+	if !found {
+		return fmt.Errorf("unknown parameter %q for %s", name, table)
+	}
+	return p.Validate(value)
+}
+
+// splitQueryTokens splits filter bar input at spaces; double quotes keep spaces
+// in a value (businessKeyLike="a b").
+func splitQueryTokens(input string) []string {
+	var tokens []string
+	var cur strings.Builder
+	quoted := false
+	for _, r := range input {
+		switch {
+		case r == '"':
+			quoted = !quoted
+		case r == ' ' && !quoted:
+			if cur.Len() > 0 {
+				tokens = append(tokens, cur.String())
+				cur.Reset()
+			}
+		default:
+			cur.WriteRune(r)
+		}
+	}
+	if cur.Len() > 0 {
+		tokens = append(tokens, cur.String())
+	}
+	return tokens
+}
+
+// parseQueryInput parses and validates the name=value tokens of input against
+// the parameters of the current table.
+func (m *model) parseQueryInput(input string) ([]queryFilter, error) {
+	var out []queryFilter
+	for _, tok := range splitQueryTokens(input) {
+		name, value, ok := strings.Cut(tok, "=")
+		name = strings.TrimSpace(name)
+		if !ok || name == "" {
+			return nil, fmt.Errorf("expected name=value, got %q", tok)
+		}
+		if err := validateFilter(m.queryBar.params, m.queryBar.known, m.currentRoot, name, value); err != nil {
+			return nil, err
+		}
+		out = append(out, queryFilter{name: name, value: value})
+	}
+	return out, nil
+}
+
+// currentQueryToken returns the token being typed: the input after its last
+// unquoted space.
+func currentQueryToken(input string) string {
+	if strings.Count(input, `"`)%2 == 1 {
+		return input[strings.LastIndex(input[:strings.LastIndex(input, `"`)], " ")+1:]
+	}
+	return input[strings.LastIndex(input, " ")+1:]
+}
+
+// querySuggestions completes the token being typed: parameter names (prefix
+// matches first, parameters already used left out) or the values of an enum or
+// boolean parameter.
+func (m *model) querySuggestions() []querySuggestion {
+	used := map[string]bool{}
+	for _, c := range m.queryBar.chips {
+		used[c.name] = true
+	}
+	return paramSuggestions(m.queryBar.params, m.queryBar.input, used)
+}
+
+// paramSuggestions completes the last token of input from params, leaving out
+// the parameters in used and those already named in input.
+func paramSuggestions(params []apispec.Param, input string, used map[string]bool) []querySuggestion {
+	tok := currentQueryToken(input)
+	if name, prefix, ok := strings.Cut(tok, "="); ok {
+		p, found := findParam(params, name)
+		if !found {
+			return nil
+		}
+		var out []querySuggestion
+		for _, v := range p.Values() {
+			if strings.HasPrefix(v, prefix) && v != prefix {
+				out = append(out, querySuggestion{insert: name + "=" + v + " ", label: v, detail: p.Name})
+			}
+		}
+		return out
+	}
+	named := map[string]bool{}
+	for n := range used {
+		named[n] = true
+	}
+	for _, t := range splitQueryTokens(input) {
+		if n, _, ok := strings.Cut(t, "="); ok {
+			named[n] = true
+		}
+	}
+	lower := strings.ToLower(tok)
+	var prefixed, contained []querySuggestion
+	for _, p := range params {
+		if named[p.Name] {
+			continue
+		}
+		s := querySuggestion{insert: p.Name + "=", label: p.Name, detail: p.TypeLabel()}
+		switch name := strings.ToLower(p.Name); {
+		case strings.HasPrefix(name, lower):
+			prefixed = append(prefixed, s)
+		case lower != "" && strings.Contains(name, lower):
+			contained = append(contained, s)
+		}
+	}
+	return append(prefixed, contained...)
+}
+
+// applyQueryBar validates the input, merges it into the chips and refetches
+// the view with the resulting filters.
+func (m *model) applyQueryBar() tea.Cmd {
+	parsed, err := m.parseQueryInput(m.queryBar.input)
+	if err != nil {
+		m.queryBar.err = err.Error()
+		return nil
+	}
+	filters := make(map[string]string, len(m.queryBar.chips)+len(parsed))
+	for _, c := range append(m.queryBar.chips, parsed...) {
+		filters[c.name] = c.value
+	}
+	m.closeQueryBar()
+	return m.setQueryFilters(filters)
+}
+
+// setQueryFilters replaces the filters of the current view and reloads its
+// first page.
+func (m *model) setQueryFilters(filters map[string]string) tea.Cmd {
+	if len(filters) == 0 {
+		filters = nil
+	}
+	m.queryFilters = filters
+	m.cancelViewRequests()
+	if m.pageOffsets == nil {
+		m.pageOffsets = make(map[string]int)
+	}
+	m.pageOffsets[m.currentRoot] = 0
+	m.table.SetCursor(0)
+	m.isLoading = true
+	m.apiCallStarted = time.Now()
+	return tea.Batch(m.fetchGenericCmd(m.currentRoot), flashOnCmd(), spinnerTickCmd(), m.saveStateCmd())
+}
+
+// handleQueryBarKey processes a key press while the filter bar is open.
+func (m *model) handleQueryBarKey(msg tea.KeyMsg) tea.Cmd {
+	suggestions := m.querySuggestions()
+	switch msg.String() {
+	case "esc":
+		m.closeQueryBar()
+		return nil
+	case "enter":
+		return m.applyQueryBar()
+	case "tab":
+		if m.queryBar.cursor < len(suggestions) {
+			tok := currentQueryToken(m.queryBar.input)
+			m.queryBar.input = strings.TrimSuffix(m.queryBar.input, tok) + suggestions[m.queryBar.cursor].insert
+			m.queryBar.cursor = 0
+		}
+	case "down":
+		if m.queryBar.cursor < len(suggestions)-1 {
+			m.queryBar.cursor++
+		}
+	case "up":
+		if m.queryBar.cursor > 0 {
+			m.queryBar.cursor--
+		}
+	case "backspace":
+		if m.queryBar.input == "" {
+			// remove the last chip
+			if n := len(m.queryBar.chips); n > 0 {
+				m.queryBar.chips = m.queryBar.chips[:n-1]
+			}
+		} else {
+			r := []rune(m.queryBar.input)
+			m.queryBar.input = string(r[:len(r)-1])
+		}
+		m.queryBar.cursor = 0
+	case "ctrl+w":
+		if m.queryBar.input == "" {
+			if n := len(m.queryBar.chips); n > 0 {
+				m.queryBar.chips = m.queryBar.chips[:n-1]
+			}
+		} else {
+			trimmed := strings.TrimRight(m.queryBar.input, " ")
+			m.queryBar.input = trimmed[:strings.LastIndex(trimmed, " ")+1]
+		}
+		m.queryBar.cursor = 0
+	case " ", "space":
+		m.queryBar.input += " "
+		m.queryBar.cursor = 0
+	default:
+		if msg.Type == tea.KeyRunes {
+			m.queryBar.input += string(msg.Runes)
+			m.queryBar.cursor = 0
+		}
+	}
+	m.queryBar.err = ""
+	return nil
+}
+
+// queryBarHeight is the number of rows the filter bar takes from the table. It
+// is fixed while the bar is open so the table does not jump while typing.
+func (m *model) queryBarHeight() int {
+	if !m.queryBar.active {
+		return 0
+	}
+	// 2 border lines + input line + hint line + suggestions
+	return 2 + 2 + queryBarMaxSuggestions
+}
+
+// renderQueryChips renders filters as chips, e.g. "[withIncident=true ×]".
+func renderQueryChips(filters []queryFilter) string {
+	parts := make([]string, len(filters))
+	for i, f := range filters {
+		parts[i] = "[" + f.name + "=" + f.value + " ×]"
+	}
+	return strings.Join(parts, " ")
+}
+
+// renderQueryBar renders the filter bar: chips and input, a hint or validation
+// error, and the completions of the token being typed.
+func (m *model) renderQueryBar() string {
+	if !m.queryBar.active {
+		return ""
+	}
+	line := m.styles.PopupInput.Render("⚲ ")
+	if chips := renderQueryChips(m.queryBar.chips); chips != "" {
+		line += m.styles.Accent.Render(chips) + " "
+	}
+	line += m.styles.PopupInput.Render(m.queryBar.input + "▌")
+
+	var hint string
+	switch {
+	case m.queryBar.err != "":
+		hint = m.styles.ValidationError.Render(m.queryBar.err)
+	case !m.queryBar.known:
+		hint = m.styles.PopupHint.Render("No parameter metadata for " + m.currentRoot + " — name=value sent as is  Enter:apply  Esc:cancel")
+	default:
+		hint = m.styles.PopupHint.Render("Tab:complete  ↑↓:select  Enter:apply  Backspace:remove filter  Esc:cancel")
+	}
+
+	lines := []string{line, hint}
+	suggestions := m.querySuggestions()
+	start := 0
+	if m.queryBar.cursor >= queryBarMaxSuggestions {
+		start = m.queryBar.cursor - queryBarMaxSuggestions + 1
+	}
+	end := min(start+queryBarMaxSuggestions, len(suggestions))
+	width := m.lastWidth - 8
+	selectedStyle := lipgloss.NewStyle().Foreground(col(m.skin, "borderFocus")).Bold(true)
+	for i := start; i < end; i++ {
+		s := suggestions[i]
+		text := fmt.Sprintf("%-32s %s", s.label, s.detail)
+		if p, ok := m.queryParam(s.label); ok && p.Description != "" {
+			text += "  " + strings.SplitN(p.Description, "\n", 2)[0]
+		}
+		text = ansi.Truncate(text, max(width, 20), "…")
+		if i == m.queryBar.cursor {
+			lines = append(lines, selectedStyle.Render("▸ "+text))
+		} else {
+			lines = append(lines, "  "+m.styles.FgMuted.Render(text))
+		}
+	}
+	for len(lines) < 2+queryBarMaxSuggestions {
+		lines = append(lines, "")
+	}
+
+	return lipgloss.NewStyle().
+		BorderStyle(lipgloss.NormalBorder()).
+		BorderForeground(col(m.skin, "borderFocus")).
+		Width(m.lastWidth-4).
+		Padding(0, 1).
+		Render(strings.Join(lines, "\n"))
+}
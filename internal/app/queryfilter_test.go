@@ -0,0 +1,150 @@
+package app
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/kthoms/o6n/internal/apispec"
+	"github.com/kthoms/o6n/internal/config"
+)
+
+const queryFilterTestSpec = `{"paths": {"/process-instance": {"get": {"parameters": [
+  {"name": "withIncident", "in": "query", "schema": {"type": "boolean"}},
+  {"name": "suspended", "in": "query", "schema": {"type": "boolean"}},
+  {"name": "activityIdIn", "in": "query", "schema": {"type": "string"}},
+  {"name": "businessKeyLike", "in": "query", "schema": {"type": "string"}}
+]}}}}`
+
+func queryFilterTestModel(t *testing.T) model {
+	t.Helper()
+	m := modeTestModel(t, config.ModeNormal)
+	spec, err := apispec.Parse([]byte(queryFilterTestSpec))
+	if err != nil {
+		t.Fatal(err)
+	}
+	m.spec, m.specLoaded = spec, true
+	return m
+}
+
+func typeQuery(m model, text string) model {
+	for _, r := range text {
+		m, _ = sendKeyString(m, string(r))
+	}
+	return m
+}
+
+func TestQueryBarCompletesValidatesAndFetches(t *testing.T) {
+	var mu sync.Mutex
+	var queries []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		queries = append(queries, r.URL.Path+"?"+r.URL.RawQuery)
+		mu.Unlock()
+		if strings.HasSuffix(r.URL.Path, "/count") {
+			_, _ = w.Write([]byte(`{"count":0}`))
+			return
+		}
+		_, _ = w.Write([]byte(`[]`))
+	}))
+	defer srv.Close()
+
+	m := queryFilterTestModel(t)
+	m.config.Environments["local"] = config.Environment{URL: srv.URL}
+	m, _ = sendKeyString(m, "F")
+	if !m.queryBar.active {
+		t.Fatal("expected F to open the filter bar")
+	}
+
+	// complete a name, then its boolean value
+	m = typeQuery(m, "withI")
+	m, _ = sendKeyString(m, "tab")
+	if m.queryBar.input != "withIncident=" {
+		t.Fatalf("expected the name to be completed, got %q", m.queryBar.input)
+	}
+	if s := m.querySuggestions(); len(s) != 2 || s[0].label != "true" {
+		t.Fatalf("expected true/false to be offered, got %v", s)
+	}
+	m, _ = sendKeyString(m, "tab")
+	m = typeQuery(m, "suspended=no")
+	m, cmd := sendKeyString(m, "enter")
+	if cmd != nil || !m.queryBar.active || !strings.Contains(m.queryBar.err, "expected true or false") {
+		t.Fatalf("expected a validation error and no request, got %q", m.queryBar.err)
+	}
+
+	m, _ = sendKeyString(m, "backspace")
+	m, _ = sendKeyString(m, "backspace")
+	m = typeQuery(m, `false activityIdIn=ServiceTask_1 businessKeyLike="a b"`)
+	m, cmd = sendKeyString(m, "enter")
+	if m.queryBar.active || cmd == nil {
+		t.Fatalf("expected the filters to be applied, bar error %q", m.queryBar.err)
+	}
+	want := map[string]string{"withIncident": "true", "suspended": "false", "activityIdIn": "ServiceTask_1", "businessKeyLike": "a b"}
+	for k, v := range want {
+		if m.queryFilters[k] != v {
+			t.Errorf("expected filter %s=%s, got %v", k, v, m.queryFilters)
+		}
+	}
+	if msg := m.fetchGenericCmd(m.currentRoot)(); msg == nil {
+		t.Fatal("expected the view to load")
+	}
+	mu.Lock()
+	got := strings.Join(queries, "\n")
+	mu.Unlock()
+	for _, q := range []string{"activityIdIn=ServiceTask_1&businessKeyLike=a+b&suspended=false&withIncident=true", "/process-instance/count?"} {
+		if !strings.Contains(got, q) {
+			t.Errorf("expected %q in the requests, got:\n%s", q, got)
+		}
+	}
+
+	// chips show in the content header and can be removed in the bar
+	m.lastWidth, m.lastHeight = 160, 40
+	if out := m.View(); !strings.Contains(out, "[withIncident=true ×]") {
+		t.Errorf("expected the filters as chips in the header, got:\n%s", out)
+	}
+	m, _ = sendKeyString(m, "F")
+	if len(m.queryBar.chips) != 4 {
+		t.Fatalf("expected the active filters as chips, got %v", m.queryBar.chips)
+	}
+	m, _ = sendKeyString(m, "backspace")
+	m, _ = sendKeyString(m, "enter")
+	if _, ok := m.queryFilters["withIncident"]; ok || len(m.queryFilters) != 3 {
+		t.Errorf("expected the last chip to be removed, got %v", m.queryFilters)
+	}
+}
+
+func TestQueryBarRejectsUnknownParams(t *testing.T) {
+	m := queryFilterTestModel(t)
+	m, _ = sendKeyString(m, "F")
+	m = typeQuery(m, "nope=1")
+	m, cmd := sendKeyString(m, "enter")
+	if cmd != nil || !strings.Contains(m.queryBar.err, `unknown parameter "nope"`) {
+		t.Errorf("expected unknown parameters to be rejected, got %q", m.queryBar.err)
+	}
+	m, _ = sendKeyString(m, "esc")
+	if m.queryBar.active || m.queryFilters != nil {
+		t.Error("expected Esc to close the bar without filters")
+	}
+}
+
+func TestQueryFiltersFollowNavigation(t *testing.T) {
+	m := queryFilterTestModel(t)
+	m.queryFilters = map[string]string{"suspended": "true"}
+	m.prepareStateTransition(TransitionDrillDown)
+	if m.queryFilters != nil {
+		t.Errorf("expected a drill-down to start without filters, got %v", m.queryFilters)
+	}
+	m.prepareStateTransition(TransitionPop)
+	if m.queryFilters["suspended"] != "true" {
+		t.Errorf("expected going back to restore the filters, got %v", m.queryFilters)
+	}
+	if nav := m.currentNavState(); nav.QueryFilters["suspended"] != "true" {
+		t.Errorf("expected the filters to be persisted, got %v", nav.QueryFilters)
+	}
+	m.prepareStateTransition(TransitionFull)
+	if m.queryFilters != nil {
+		t.Errorf("expected a context switch to clear the filters, got %v", m.queryFilters)
+	}
+}
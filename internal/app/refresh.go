@@ -0,0 +1,239 @@
+package app
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/table"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// refreshHighlight is how long the rows changed by an auto-refresh stay
+// highlighted.
+const refreshHighlight = 3 * time.Second
+
+// rowDiff holds the changes an auto-refresh found in the table of root,
+// keyed by the id of the rows.
+type rowDiff struct {
+	root    string
+	until   time.Time                // when the highlight expires; identifies the diff
+	items   []map[string]interface{} // the refreshed items, rendered plain once expired
+	added   map[string]bool
+	changed map[string]map[string]bool // id -> lower-cased names of the changed fields
+	removed []map[string]interface{}
+}
+
+// rowDiffExpiredMsg ends the highlight of the diff that expires at until.
+type rowDiffExpiredMsg struct{ until time.Time }
+
+func (d rowDiff) empty() bool {
+	return len(d.added) == 0 && len(d.changed) == 0 && len(d.removed) == 0
+}
+
+// summary renders the counts as "+added / −removed / ~changed".
+func (d rowDiff) summary() string {
+	return fmt.Sprintf("+%d / −%d / ~%d", len(d.added), len(d.removed), len(d.changed))
+}
+
+// removedSummary names the removed rows, e.g. "gone: a, b +3 more", or "".
+// Removed rows are not in the table, so the footer is where they show.
+func (d rowDiff) removedSummary() string {
+	if len(d.removed) == 0 {
+		return ""
+	}
+	const shown = 3
+	var ids []string
+	for _, it := range d.removed {
+		if len(ids) == shown {
+			break
+		}
+		ids = append(ids, rowKey(it))
+	}
+	s := "gone: " + strings.Join(ids, ", ")
+	if more := len(d.removed) - len(ids); more > 0 {
+		s += fmt.Sprintf(" +%d more", more)
+	}
+	return s
+}
+
+// rowKey returns the id of item, or "" for rows without one.
+func rowKey(item map[string]interface{}) string {
+	v, ok := item["id"]
+	if !ok || v == nil {
+		return ""
+	}
+	return fmt.Sprintf("%v", v)
+}
+
+// diffRows compares two loads of a table by row id. Rows without an id are
+// not compared.
+func diffRows(prev, next []map[string]interface{}) rowDiff {
+	before := make(map[string]map[string]interface{}, len(prev))
+	for _, it := range prev {
+		if k := rowKey(it); k != "" {
+			before[k] = it
+		}
+	}
+	d := rowDiff{added: map[string]bool{}, changed: map[string]map[string]bool{}}
+	seen := make(map[string]bool, len(next))
+	for _, it := range next {
+		k := rowKey(it)
+		if k == "" {
+			continue
+		}
+		seen[k] = true
+		old, ok := before[k]
+		if !ok {
+			d.added[k] = true
+			continue
+		}
+		if fields := changedFields(old, it); len(fields) > 0 {
+			d.changed[k] = fields
+		}
+	}
+	for _, it := range prev {
+		if k := rowKey(it); k != "" && !seen[k] {
+			d.removed = append(d.removed, it)
+		}
+	}
+	return d
+}
+
+// changedFields returns the lower-cased names of the fields that differ
+// between a and b.
+func changedFields(a, b map[string]interface{}) map[string]bool {
+	fields := map[string]bool{}
+	for k, v := range a {
+		if !reflect.DeepEqual(v, b[k]) {
+			fields[strings.ToLower(k)] = true
+		}
+	}
+	for k := range b {
+		if _, ok := a[k]; !ok {
+			fields[strings.ToLower(k)] = true
+		}
+	}
+	return fields
+}
+
+// refreshIntervalFor returns the auto-refresh interval of root: the table's
+// refresh from o6n-cfg.yaml, else the --refresh interval, else refreshInterval.
+func (m *model) refreshIntervalFor(root string) time.Duration {
+	if def := m.findTableDef(root); def != nil && def.Refresh > 0 {
+		return def.Refresh
+	}
+	if m.refreshDefault > 0 {
+		return m.refreshDefault
+	}
+	return refreshInterval
+}
+
+// refreshTickCmd schedules the next auto-refresh of the current table.
+func (m *model) refreshTickCmd() tea.Cmd {
+	gen := m.refreshGen
+	return tea.Tick(m.refreshIntervalFor(m.currentRoot), func(time.Time) tea.Msg { return refreshMsg{gen: gen} })
+}
+
+// refreshPaused reports whether auto-refresh should skip a tick because the
+// user is busy with a modal, popup, filter bar or search.
+func (m *model) refreshPaused() bool {
+	return m.activeModal != ModalNone || m.popup.mode != popupModeNone || m.queryBar.active || m.searchMode
+}
+
+// toggleAutoRefresh turns auto-refresh on (reloading right away) or off.
+func (m *model) toggleAutoRefresh() tea.Cmd {
+	m.autoRefresh = !m.autoRefresh
+	m.refreshGen++
+	if !m.autoRefresh {
+		msg, kind, cmd := setFooterStatus(footerStatusInfo, "Auto-refresh off", 3*time.Second)
+		m.footerError, m.footerStatusKind = msg, kind
+		return cmd
+	}
+	m.isLoading = true
+	m.apiCallStarted = time.Now()
+	initialCmd := m.fetchForRoot(m.currentRoot)
+	if initialCmd == nil {
+		initialCmd = m.fetchDefinitionsCmd()
+	}
+	msg, kind, cmd := setFooterStatus(footerStatusInfo, "Auto-refresh every "+m.refreshIntervalFor(m.currentRoot).String(), 3*time.Second)
+	m.footerError, m.footerStatusKind = msg, kind
+	return tea.Batch(initialCmd, flashOnCmd(), m.refreshTickCmd(), spinnerTickCmd(), cmd)
+}
+
+// trackRefreshChanges diffs the items loaded for root against the rows shown
+// when the load came from the poller, and highlights the changes for
+// refreshHighlight. Any other load ends the highlight.
+func (m *model) trackRefreshChanges(root string, items []map[string]interface{}) tea.Cmd {
+	fromPoll := m.refreshFetch
+	m.refreshFetch = false
+	m.rowDiff = rowDiff{}
+	if !fromPoll || root != m.viewMode {
+		return nil
+	}
+	d := diffRows(m.rowData, items)
+	if d.empty() {
+		return nil
+	}
+	d.root = root
+	d.items = items
+	d.until = time.Now().Add(refreshHighlight)
+	m.rowDiff = d
+	status := "↺ " + d.summary()
+	if gone := d.removedSummary(); gone != "" {
+		status += " — " + gone
+	}
+	msg, kind, clear := setFooterStatus(footerStatusInfo, status, refreshHighlight)
+	m.footerError, m.footerStatusKind = msg, kind
+	until := d.until
+	return tea.Batch(clear, tea.Tick(refreshHighlight, func(time.Time) tea.Msg { return rowDiffExpiredMsg{until: until} }))
+}
+
+// expireRowDiff ends the highlight of the diff that expires at until and
+// renders its rows plain again.
+func (m *model) expireRowDiff(until time.Time) {
+	if m.rowDiff.root == "" || !m.rowDiff.until.Equal(until) {
+		return
+	}
+	root, items := m.rowDiff.root, m.rowDiff.items
+	m.rowDiff = rowDiff{}
+	if m.viewMode == root {
+		m.applyGenericItems(root, items)
+	}
+}
+
+// highlightRowDiff styles the rows of rd found by the last refresh: added rows
+// whole, changed rows in the changed cells. Removed rows are not shown: the
+// table only holds rows backed by rowData, so the cursor, marks and actions
+// never reach a gone row; the footer names them instead. plain holds the
+// unstyled cells of styled.
+func (m *model) highlightRowDiff(rd []map[string]interface{}, plain, styled []table.Row, cols []table.Column) []table.Row {
+	if len(cols) == 0 {
+		return styled
+	}
+	out := append([]table.Row(nil), styled...)
+	for i, it := range rd {
+		if i >= len(out) || i >= len(plain) {
+			break
+		}
+		k := rowKey(it)
+		switch {
+		case m.rowDiff.added[k]:
+			row := make(table.Row, len(plain[i]))
+			for j, cell := range plain[i] {
+				row[j] = m.styles.RowAdded.Render(cell)
+			}
+			out[i] = row
+		case m.rowDiff.changed[k] != nil:
+			row := append(table.Row(nil), out[i]...)
+			for j, c := range cols {
+				if j < len(row) && m.rowDiff.changed[k][strings.ToLower(stripSortIndicator(c.Title))] {
+					row[j] = m.styles.RowChanged.Render(plain[i][j])
+				}
+			}
+			out[i] = row
+		}
+	}
+	return out
+}
@@ -0,0 +1,116 @@
+package app
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kthoms/o6n/internal/config"
+)
+
+func TestDiffRowsByID(t *testing.T) {
+	prev := []map[string]interface{}{
+		{"id": "a", "state": "ACTIVE"},
+		{"id": "b", "state": "ACTIVE"},
+		{"id": "c", "state": "ACTIVE"},
+	}
+	next := []map[string]interface{}{
+		{"id": "a", "state": "ACTIVE"},
+		{"id": "b", "state": "SUSPENDED"},
+		{"id": "d", "state": "ACTIVE"},
+		{"state": "no id"},
+	}
+	d := diffRows(prev, next)
+	if !d.added["d"] || !d.changed["b"]["state"] || len(d.removed) != 1 || rowKey(d.removed[0]) != "c" {
+		t.Errorf("unexpected diff %+v", d)
+	}
+	if got := d.summary(); got != "+1 / −1 / ~1" {
+		t.Errorf("expected the counter, got %q", got)
+	}
+	if !diffRows(prev, prev).empty() {
+		t.Error("expected no changes between equal loads")
+	}
+}
+
+func TestAutoRefreshHighlightsChanges(t *testing.T) {
+	m := modeTestModel(t, config.ModeNormal)
+	m.paneWidth, m.lastWidth, m.lastHeight = 120, 120, 40
+	res, _ := m.Update(genericLoadedMsg{root: "process-instance", items: []map[string]interface{}{
+		{"id": "inst-a", "definitionId": "v1"}, {"id": "inst-b", "definitionId": "v1"},
+	}})
+	m = res.(model)
+
+	m, _ = sendKeyString(m, "r")
+	if !m.autoRefresh || !strings.Contains(m.footerError, "every 5s") {
+		t.Fatalf("expected auto-refresh on at the default interval, footer %q", m.footerError)
+	}
+	res, cmd := m.Update(refreshMsg{gen: m.refreshGen})
+	m = res.(model)
+	if cmd == nil || !m.refreshFetch {
+		t.Fatal("expected a tick to reload the table")
+	}
+	res, cmd = m.Update(genericLoadedMsg{root: "process-instance", items: []map[string]interface{}{
+		{"id": "inst-a", "definitionId": "v2"}, {"id": "inst-c", "definitionId": "v1"},
+	}})
+	m = res.(model)
+	if cmd == nil || !strings.Contains(m.footerError, "+1 / −1 / ~1 — gone: inst-b") {
+		t.Errorf("expected the change counter and the removed row in the footer, got %q", m.footerError)
+	}
+	if rows := m.table.Rows(); len(rows) != 2 || len(m.rowData) != 2 {
+		t.Errorf("expected only the loaded rows in the table, got %v", rows)
+	}
+	m.table.GotoBottom()
+	if c := m.table.Cursor(); rowKey(m.rowData[c]) != "inst-c" {
+		t.Errorf("expected the cursor to stop at the last live row, got row %d", c)
+	}
+
+	res, _ = m.Update(rowDiffExpiredMsg{until: m.rowDiff.until})
+	m = res.(model)
+	if rows := m.table.Rows(); len(rows) != 2 || m.rowDiff.root != "" {
+		t.Errorf("expected the highlight to expire, got %v", rows)
+	}
+
+	// a load the user started does not highlight anything
+	res, _ = m.Update(genericLoadedMsg{root: "process-instance", items: []map[string]interface{}{{"id": "inst-z"}}})
+	m = res.(model)
+	if m.rowDiff.root != "" {
+		t.Errorf("expected no diff for a manual load, got %+v", m.rowDiff)
+	}
+}
+
+func TestAutoRefreshPausesAndDropsStaleTicks(t *testing.T) {
+	m := modeTestModel(t, config.ModeNormal)
+	m, _ = sendKeyString(m, "r")
+
+	m.activeModal = ModalHelp
+	res, cmd := m.Update(refreshMsg{gen: m.refreshGen})
+	m = res.(model)
+	if cmd == nil || m.refreshFetch {
+		t.Error("expected a tick under a modal to reschedule without reloading")
+	}
+	m.activeModal = ModalNone
+
+	stale := m.refreshGen
+	m, _ = sendKeyString(m, "r")
+	m, _ = sendKeyString(m, "r")
+	res, cmd = m.Update(refreshMsg{gen: stale})
+	m = res.(model)
+	if cmd != nil || m.refreshFetch {
+		t.Error("expected a tick of an earlier toggle to be dropped")
+	}
+}
+
+func TestRefreshIntervalFor(t *testing.T) {
+	m := modeTestModel(t, config.ModeNormal)
+	if got := m.refreshIntervalFor("job"); got != refreshInterval {
+		t.Errorf("expected the default interval, got %v", got)
+	}
+	m.refreshDefault = 10 * time.Second
+	if got := m.refreshIntervalFor("job"); got != 10*time.Second {
+		t.Errorf("expected the --refresh interval, got %v", got)
+	}
+	m.config.Tables[1].Refresh = 2 * time.Second
+	if got := m.refreshIntervalFor(m.config.Tables[1].Name); got != 2*time.Second {
+		t.Errorf("expected the table's interval, got %v", got)
+	}
+}
@@ -6,12 +6,19 @@ import (
 	"log"
 	"os"
 
+	"github.com/kthoms/o6n/internal/client"
 	"github.com/kthoms/o6n/internal/config"
 
 	tea "github.com/charmbracelet/bubbletea"
 )
 
-const statePath = "o6n-stat.yml"
+const (
+	statePath     = "o6n-stat.yml"
+	envConfigPath = "o6n-env.yaml"
+	appConfigPath = "o6n-cfg.yaml"
+	apiSpecPath   = "resources/operaton-rest-api.json"
+	viewsPath     = "o6n-views.yaml"
+)
 
 // Run is the application entry point called from main.
 func Run() {
@@ -19,6 +26,8 @@ func Run() {
 	var skin = flag.String("skin", "", "skin to use")
 	var noSplash = flag.Bool("no-splash", false, "disable splash screen")
 	var vimFlag = flag.Bool("vim", false, "enable vim keybindings (j/k/gg/G/Ctrl+U/Ctrl+D)")
+	var viewFlag = flag.String("view", "", "open a saved view from o6n-views.yaml")
+	var refreshFlag = flag.Duration("refresh", 0, "start with auto-refresh on, polling at this interval (e.g. 10s)")
 	flag.Parse()
 
 	// Always open debug/o6n.log for error logging.
@@ -39,6 +48,13 @@ func Run() {
 		log.SetPrefix("[ERROR] ")
 	}
 
+	// Headless subcommands (e.g. `o6n get process-instance`) bypass the TUI.
+	if args := flag.Args(); len(args) > 0 {
+		code := runCLI(args, os.Stdout, os.Stderr)
+		logFile.Close()
+		os.Exit(code)
+	}
+
 	// Verify critical config files exist and are not corrupted
 	if err := validateConfigFiles(); err != nil {
 		log.Printf("CRITICAL: %v", err)
@@ -46,14 +62,19 @@ func Run() {
 	}
 
 	// Load split config files (o6n-env.yaml + o6n-cfg.yaml). No legacy fallback.
-	envCfg, err := config.LoadEnvConfig("o6n-env.yaml")
+	envCfg, err := config.LoadEnvConfig(envConfigPath)
 	if err != nil {
 		fmt.Printf("Error loading o6n-env.yaml: %v\n", err)
 		fmt.Println("Please create o6n-env.yaml from the example.")
 		os.Exit(1)
 	}
+	if err := envCfg.ResolveSecrets(credentialsPassphrase); err != nil {
+		fmt.Printf("Error resolving credentials in o6n-env.yaml: %v\n", err)
+		os.Exit(1)
+	}
+	client.AuditFile = envCfg.AuditPath()
 
-	appCfg, err := config.LoadAppConfig("o6n-cfg.yaml")
+	appCfg, err := config.LoadAppConfig(appConfigPath)
 	if err != nil {
 		fmt.Printf("Error loading o6n-cfg.yaml: %v\n", err)
 		os.Exit(1)
@@ -94,6 +115,11 @@ func Run() {
 	}
 	m.statePath = statePath
 	m.showLatency = appState.ShowLatency
+	if *refreshFlag > 0 {
+		m.refreshDefault = *refreshFlag
+		m.autoRefresh = true
+	}
+	m.cmdHistory = appState.CommandHistory
 
 	// Restore active environment from state (falls back to env config / default).
 	if appState.ActiveEnv != "" {
@@ -106,7 +132,43 @@ func Run() {
 	// Restore last navigation position (root resource + drilldown path).
 	m.restoreNavState(appState.Navigation)
 	// Detect first run: no saved navigation state means the user has never chosen a home context.
-	m.firstRunNeeded = (appState.Navigation.Root == "")
+	m.firstRunNeeded = (appState.Navigation.Root == "" && !appState.HomeDashboard)
+	m.dashboard.home = appState.HomeDashboard
+	m.dashboard.onStart = appState.HomeDashboard
+
+	// Saved views (o6n-views.yaml); --view opens one instead of the last position.
+	m.viewsPath = viewsPath
+	if m.savedViews, err = config.LoadViews(viewsPath); err != nil {
+		log.Printf("Warning: could not load views: %v", err)
+	}
+	if *viewFlag != "" {
+		v, ok := config.FindView(m.savedViews, *viewFlag)
+		if !ok {
+			fmt.Printf("Unknown view %q (see 'o6n views')\n", *viewFlag)
+			os.Exit(1)
+		}
+		if err := m.restoreSavedView(v); err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		m.firstRunNeeded = false
+		m.dashboard.onStart = false
+	}
+
+	// Run every environment's password_cmd before the TUI owns the terminal, so
+	// password managers can prompt; health checks and the alert poller reach all
+	// environments. Once the TUI runs, commands are frozen and requests only use
+	// these results, so a failed command stays an auth error until restart.
+	for _, name := range m.envNames {
+		if _, err := m.config.Environments[name].WithCommandSecrets(); err != nil {
+			if name == m.currentEnv {
+				m.footerError = friendlyError(m.currentEnv, fmt.Errorf("auth: %w", err))
+			} else {
+				log.Printf("Warning: %s: %v", name, err)
+			}
+		}
+	}
+	config.FreezeSecretCommands(true)
 
 	if *noSplash {
 		m.splashActive = false
@@ -0,0 +1,477 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/atotto/clipboard"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/kthoms/o6n/internal/client"
+	"github.com/kthoms/o6n/internal/config"
+)
+
+// frameworkFramePrefixes are the packages of JDK, engine and library frames;
+// every other frame is an application frame and highlighted.
+var frameworkFramePrefixes = []string{
+	"java.", "javax.", "jakarta.", "jdk.", "sun.", "com.sun.", "kotlin.", "scala.",
+	"org.operaton.", "org.camunda.", "org.springframework.", "org.apache.", "org.hibernate.",
+	"org.mybatis.", "org.jboss.", "org.glassfish.", "org.eclipse.", "io.netty.", "io.undertow.",
+	"reactor.", "net.bytebuddy.", "com.fasterxml.", "org.slf4j.", "ch.qos.logback.",
+	"groovy.", "org.codehaus.groovy.", "org.graalvm.", "com.zaxxer.",
+}
+
+// stackSection is one exception of a stacktrace: its header line (the top
+// exception, or a "Caused by:" line) and the frames below it.
+type stackSection struct {
+	lines  []string // lines[0] is the header
+	folded bool
+}
+
+// stackLine is one visible line of the stacktrace view.
+type stackLine struct {
+	section int
+	index   int // line within the section; -1 for the folded placeholder
+}
+
+// stacktraceState holds the stacktrace viewer (ModalStacktrace).
+type stacktraceState struct {
+	title     string
+	path      string // API path the stacktrace is loaded from
+	id        string // id of the job, external task or log entry, for the file name
+	raw       string
+	sections  []stackSection
+	cursor    int // index into visibleLines
+	scroll    int
+	searching bool // typing a search term
+	query     string
+	loading   bool
+	err       string
+	returnTo  ModalType // modal restored on close, e.g. the triage view
+}
+
+// stacktraceLoadedMsg delivers a loaded stacktrace.
+type stacktraceLoadedMsg struct {
+	path string
+	text string
+	err  error
+}
+
+// parseStacktrace splits a Java stacktrace into sections at each "Caused by:";
+// all causes start folded.
+func parseStacktrace(text string) []stackSection {
+	var sections []stackSection
+	for _, line := range strings.Split(strings.ReplaceAll(strings.TrimRight(text, "\n"), "\r\n", "\n"), "\n") {
+		line = strings.ReplaceAll(line, "\t", "    ")
+		if len(sections) == 0 || strings.HasPrefix(strings.TrimSpace(line), "Caused by:") {
+			sections = append(sections, stackSection{folded: len(sections) > 0})
+		}
+		s := &sections[len(sections)-1]
+		s.lines = append(s.lines, line)
+	}
+	return sections
+}
+
+// isStackFrame reports whether line is an "at …" frame.
+func isStackFrame(line string) bool {
+	return strings.HasPrefix(strings.TrimSpace(line), "at ")
+}
+
+// isFrameworkFrame reports whether line is a frame of the JDK, the engine or
+// a library, e.g. "at java.base/java.lang.Thread.run(Thread.java:833)".
+func isFrameworkFrame(line string) bool {
+	frame := strings.TrimPrefix(strings.TrimSpace(line), "at ")
+	// Drop the module prefix of Java 9+ frames ("java.base/")
+	if slash := strings.Index(frame, "/"); slash >= 0 && slash < strings.Index(frame, "(") {
+		frame = frame[slash+1:]
+	}
+	for _, p := range frameworkFramePrefixes {
+		if strings.HasPrefix(frame, p) {
+			return true
+		}
+	}
+	return false
+}
+
+// stacktraceSource returns the API path of the stacktrace of the selected row
+// of root and the id it belongs to; an error explains rows without one.
+func (m *model) stacktraceSource(root string) (path, id string, err error) {
+	id = m.resolveActionID(config.ActionDef{})
+	if id == "" {
+		return "", "", fmt.Errorf("no row selected")
+	}
+	switch root {
+	case "job", "jobs":
+		return "/job/" + url.PathEscape(id) + "/stacktrace", id, nil
+	case "external-task", "external-tasks":
+		return "/external-task/" + url.PathEscape(id) + "/errorDetails", id, nil
+	case "history-job-log":
+		return "/history/job-log/" + url.PathEscape(id) + "/stacktrace", id, nil
+	case "incident", "incidents":
+		row := m.rowDataAt(m.table.Cursor())
+		incidentType, _ := row["incidentType"].(string)
+		// configuration holds the id of the failed job or external task
+		target, _ := row["configuration"].(string)
+		if target == "" {
+			target, _ = row["jobId"].(string)
+		}
+		switch {
+		case target == "":
+		case incidentType == "failedJob":
+			return "/job/" + url.PathEscape(target) + "/stacktrace", target, nil
+		case incidentType == "failedExternalTask":
+			return "/external-task/" + url.PathEscape(target) + "/errorDetails", target, nil
+		}
+		return "", "", fmt.Errorf("incident %s (%s) has no stacktrace", id, incidentType)
+	}
+	return "", "", fmt.Errorf("%s has no stacktraces", root)
+}
+
+// fetchStacktraceCmd loads the stacktrace at path in the current environment.
+func (m model) fetchStacktraceCmd(path string) tea.Cmd {
+	env, ok := m.config.Environments[m.currentEnv]
+	if !ok {
+		return nil
+	}
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), env.RequestTimeout())
+		defer cancel()
+		text, err := client.GetText(ctx, env, strings.TrimRight(env.URL, "/")+path)
+		return stacktraceLoadedMsg{path: path, text: text, err: err}
+	}
+}
+
+// openStacktrace opens the stacktrace of the selected job, external task,
+// job log entry or incident.
+func (m *model) openStacktrace() tea.Cmd {
+	path, id, err := m.stacktraceSource(m.currentRoot)
+	if err != nil {
+		msg, kind, cmd := setFooterStatus(footerStatusError, err.Error(), 5*time.Second)
+		m.footerError = msg
+		m.footerStatusKind = kind
+		return cmd
+	}
+	return m.showStacktrace(strings.TrimSuffix(m.currentRoot, "s")+" "+id, path, id, ModalNone)
+}
+
+// showStacktrace opens ModalStacktrace on the stacktrace at path; closing it
+// returns to returnTo.
+func (m *model) showStacktrace(title, path, id string, returnTo ModalType) tea.Cmd {
+	m.stacktrace = stacktraceState{title: title, path: path, id: id, loading: true, returnTo: returnTo}
+	m.activeModal = ModalStacktrace
+	return m.fetchStacktraceCmd(path)
+}
+
+// applyStacktrace stores a loaded stacktrace if it is still the one being shown.
+func (m *model) applyStacktrace(msg stacktraceLoadedMsg) {
+	if m.activeModal != ModalStacktrace || msg.path != m.stacktrace.path {
+		return
+	}
+	m.stacktrace.loading = false
+	m.stacktrace.err = ""
+	if msg.err != nil {
+		m.stacktrace.err = friendlyError(m.currentEnv, msg.err)
+		return
+	}
+	m.stacktrace.raw = msg.text
+	m.stacktrace.sections = parseStacktrace(msg.text)
+	m.stacktrace.cursor, m.stacktrace.scroll = 0, 0
+}
+
+// visibleLines lists the lines shown with the current folding.
+func (s *stacktraceState) visibleLines() []stackLine {
+	var lines []stackLine
+	for si, sec := range s.sections {
+		lines = append(lines, stackLine{section: si})
+		if sec.folded {
+			if len(sec.lines) > 1 {
+				lines = append(lines, stackLine{section: si, index: -1})
+			}
+			continue
+		}
+		for i := 1; i < len(sec.lines); i++ {
+			lines = append(lines, stackLine{section: si, index: i})
+		}
+	}
+	return lines
+}
+
+// findMatch moves the cursor to the next (dir 1) or previous (dir -1) line
+// containing the query, starting after the cursor and wrapping around.
+// Folded sections containing the match are unfolded. It reports whether a
+// match was found.
+func (s *stacktraceState) findMatch(dir int) bool {
+	q := strings.ToLower(s.query)
+	if q == "" {
+		return false
+	}
+	// Search all lines in order, folded or not
+	type pos struct{ section, index int }
+	var all []pos
+	cur := 0
+	var at stackLine
+	if vis := s.visibleLines(); s.cursor < len(vis) {
+		at = vis[s.cursor]
+	}
+	if at.index < 0 {
+		at.index = 0 // the folded placeholder stands for its section
+	}
+	for si, sec := range s.sections {
+		for i := range sec.lines {
+			if at.section == si && at.index == i {
+				cur = len(all)
+			}
+			all = append(all, pos{si, i})
+		}
+	}
+	for step := 1; step <= len(all); step++ {
+		p := all[((cur+dir*step)%len(all)+len(all))%len(all)]
+		if !strings.Contains(strings.ToLower(s.sections[p.section].lines[p.index]), q) {
+			continue
+		}
+		s.sections[p.section].folded = false
+		for i, l := range s.visibleLines() {
+			if l.section == p.section && l.index == p.index {
+				s.cursor = i
+			}
+		}
+		return true
+	}
+	return false
+}
+
+// saveStacktrace writes the stacktrace to stacktrace-<id>.txt in the working
+// directory and returns the file name.
+func (s *stacktraceState) saveStacktrace() (string, error) {
+	name := "stacktrace-" + strings.Map(func(r rune) rune {
+		if r == '/' || r == '\\' || r == ':' {
+			return '_'
+		}
+		return r
+	}, s.id) + ".txt"
+	return name, os.WriteFile(name, []byte(s.raw), 0o644)
+}
+
+// handleStacktraceKey processes a key press while ModalStacktrace is open.
+func (m *model) handleStacktraceKey(msg tea.KeyMsg) tea.Cmd {
+	st := &m.stacktrace
+	if st.searching {
+		switch msg.Type {
+		case tea.KeyEsc:
+			st.searching = false
+			st.query = ""
+		case tea.KeyEnter:
+			st.searching = false
+			if !st.findMatch(1) {
+				return m.stacktraceStatus(footerStatusInfo, "No match for "+st.query)
+			}
+		case tea.KeyBackspace:
+			if st.query != "" {
+				r := []rune(st.query)
+				st.query = string(r[:len(r)-1])
+			}
+		case tea.KeySpace:
+			st.query += " "
+		case tea.KeyRunes:
+			st.query += string(msg.Runes)
+		}
+		m.scrollStacktraceToCursor()
+		return nil
+	}
+
+	n := len(st.visibleLines())
+	page := m.stacktraceViewHeight()
+	switch msg.String() {
+	case "esc", "q":
+		m.activeModal = st.returnTo
+		m.stacktrace = stacktraceState{}
+		return nil
+	case "down", "j":
+		st.cursor++
+	case "up", "k":
+		st.cursor--
+	case "pgdown", "ctrl+d":
+		st.cursor += page
+	case "pgup", "ctrl+u":
+		st.cursor -= page
+	case "home", "g":
+		st.cursor = 0
+	case "end", "G":
+		st.cursor = n - 1
+	case "enter", " ":
+		if st.cursor < n {
+			sec := st.visibleLines()[st.cursor].section
+			st.sections[sec].folded = !st.sections[sec].folded
+			// Keep the cursor on the header of the toggled section
+			for i, l := range st.visibleLines() {
+				if l.section == sec {
+					st.cursor = i
+					break
+				}
+			}
+		}
+	case "z":
+		// Fold all causes, or unfold everything if they are folded already
+		fold := false
+		for _, sec := range st.sections[min(1, len(st.sections)):] {
+			if !sec.folded {
+				fold = true
+			}
+		}
+		for i := 1; i < len(st.sections); i++ {
+			st.sections[i].folded = fold
+		}
+		st.cursor = 0
+	case "/":
+		st.searching = true
+		st.query = ""
+	case "n":
+		st.findMatch(1)
+	case "N":
+		st.findMatch(-1)
+	case "c":
+		if st.raw == "" {
+			return nil
+		}
+		_ = clipboard.WriteAll(st.raw)
+		return m.stacktraceStatus(footerStatusSuccess, "✓ Copied to clipboard")
+	case "s":
+		if st.raw == "" {
+			return nil
+		}
+		name, err := st.saveStacktrace()
+		if err != nil {
+			return m.stacktraceStatus(footerStatusError, "Save failed: "+err.Error())
+		}
+		return m.stacktraceStatus(footerStatusSuccess, "✓ Saved to "+name)
+	case "r":
+		st.loading = true
+		st.err = ""
+		return m.fetchStacktraceCmd(st.path)
+	}
+	m.scrollStacktraceToCursor()
+	return nil
+}
+
+// stacktraceStatus shows text in the footer for a few seconds.
+func (m *model) stacktraceStatus(kind footerStatusKind, text string) tea.Cmd {
+	msg, k, cmd := setFooterStatus(kind, text, 3*time.Second)
+	m.footerError = msg
+	m.footerStatusKind = k
+	return cmd
+}
+
+// scrollStacktraceToCursor clamps the cursor and scrolls it into view.
+func (m *model) scrollStacktraceToCursor() {
+	st := &m.stacktrace
+	n := len(st.visibleLines())
+	if st.cursor >= n {
+		st.cursor = n - 1
+	}
+	if st.cursor < 0 {
+		st.cursor = 0
+	}
+	h := m.stacktraceViewHeight()
+	if st.cursor < st.scroll {
+		st.scroll = st.cursor
+	} else if st.cursor >= st.scroll+h {
+		st.scroll = st.cursor - h + 1
+	}
+}
+
+// stacktraceViewHeight is the number of stacktrace lines shown at once.
+func (m *model) stacktraceViewHeight() int {
+	h := m.lastHeight - 12
+	if h < 3 {
+		h = 3
+	}
+	return h
+}
+
+// modalStacktraceBody renders the stacktrace with causes folded, application
+// frames highlighted, framework frames muted and search matches marked.
+func (m *model) modalStacktraceBody() string {
+	st := &m.stacktrace
+	var b strings.Builder
+	b.WriteString(m.styles.Accent.Render("Stacktrace — "+st.title) + "\n")
+	switch {
+	case st.searching:
+		b.WriteString("/" + m.styles.PopupInput.Render(st.query) + "█\n\n")
+	case st.query != "":
+		b.WriteString(m.styles.FgMuted.Render("search: "+st.query+"  (n/N next/previous)") + "\n\n")
+	default:
+		b.WriteString(m.styles.FgMuted.Render("▸ folded cause  ") + m.styles.Accent.Render("application frame") +
+			m.styles.FgMuted.Render("  framework frame") + "\n\n")
+	}
+	if st.loading {
+		b.WriteString("Loading…\n")
+	}
+	if st.err != "" {
+		b.WriteString(m.styles.ValidationError.Render(st.err) + "\n")
+	}
+	lines := st.visibleLines()
+	if len(lines) == 0 {
+		if !st.loading && st.err == "" {
+			b.WriteString(m.styles.FgMuted.Render("No stacktrace") + "\n")
+		}
+		return b.String()
+	}
+
+	innerW := int(float64(m.lastWidth)*0.80) - 6
+	if innerW < 54 {
+		innerW = 54
+	}
+	q := strings.ToLower(st.query)
+	end := st.scroll + m.stacktraceViewHeight()
+	if end > len(lines) {
+		end = len(lines)
+	}
+	for i := st.scroll; i < end; i++ {
+		l := lines[i]
+		sec := st.sections[l.section]
+		var text string
+		switch {
+		case l.index == -1:
+			text = m.styles.FgMuted.Render(fmt.Sprintf("      … %d lines folded", len(sec.lines)-1))
+		case l.index == 0:
+			marker := "  "
+			if l.section > 0 {
+				marker = "▾ "
+				if sec.folded {
+					marker = "▸ "
+				}
+			}
+			text = ansi.Truncate(marker+sec.lines[0], innerW-2, "…")
+			if l.section == 0 {
+				text = m.styles.ValidationError.Render(text)
+			} else {
+				text = m.styles.RowFailed.Render(text)
+			}
+		default:
+			line := sec.lines[l.index]
+			text = ansi.Truncate("  "+line, innerW-2, "…")
+			switch {
+			case q != "" && strings.Contains(strings.ToLower(line), q):
+				text = m.styles.PopupCursor.Render(text)
+			case !isStackFrame(line):
+			case isFrameworkFrame(line):
+				text = m.styles.FgMuted.Render(text)
+			default:
+				text = m.styles.Accent.Render(text)
+			}
+		}
+		prefix := "  "
+		if i == st.cursor {
+			prefix = m.styles.PopupCursor.Render("> ")
+		}
+		b.WriteString(prefix + text + "\n")
+	}
+	if len(lines) > m.stacktraceViewHeight() {
+		b.WriteString(m.styles.FgMuted.Render(fmt.Sprintf("\n%d–%d of %d lines", st.scroll+1, end, len(lines))))
+	}
+	return b.String()
+}
@@ -0,0 +1,143 @@
+package app
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/bubbles/table"
+)
+
+const testStacktrace = `org.operaton.bpm.engine.ProcessEngineException: Booking failed
+	at com.acme.booking.BookDelegate.execute(BookDelegate.java:42)
+	at org.operaton.bpm.engine.impl.delegate.JavaDelegateInvocation.invoke(JavaDelegateInvocation.java:40)
+	at java.base/java.lang.Thread.run(Thread.java:833)
+Caused by: java.net.ConnectException: Connection refused
+	at java.base/sun.nio.ch.Net.connect0(Native Method)
+	at com.acme.booking.RestClient.post(RestClient.java:17)
+	... 12 more
+`
+
+func TestParseStacktraceFoldsCauses(t *testing.T) {
+	sections := parseStacktrace(testStacktrace)
+	if len(sections) != 2 {
+		t.Fatalf("expected 2 sections, got %d", len(sections))
+	}
+	if sections[0].folded || !sections[1].folded {
+		t.Errorf("expected only the cause folded, got %v %v", sections[0].folded, sections[1].folded)
+	}
+	if !strings.HasPrefix(sections[1].lines[0], "Caused by: java.net.ConnectException") || len(sections[1].lines) != 4 {
+		t.Errorf("unexpected cause section %q", sections[1].lines)
+	}
+	st := stacktraceState{sections: sections}
+	// 4 lines of the top exception, the cause header and its folded placeholder
+	if n := len(st.visibleLines()); n != 6 {
+		t.Errorf("expected 6 visible lines, got %d", n)
+	}
+}
+
+func TestIsFrameworkFrame(t *testing.T) {
+	cases := map[string]bool{
+		"\tat com.acme.booking.BookDelegate.execute(BookDelegate.java:42)":                         false,
+		"\tat org.operaton.bpm.engine.impl.cmd.ExecuteJobsCmd.execute(ExecuteJobsCmd.java:80)":     true,
+		"\tat java.base/java.lang.Thread.run(Thread.java:833)":                                     true,
+		"\tat app//com.acme.Main.main(Main.java:3)":                                                false,
+		"\tat org.springframework.transaction.support.TransactionTemplate.execute(Unknown Source)": true,
+	}
+	for line, want := range cases {
+		if got := isFrameworkFrame(line); got != want {
+			t.Errorf("isFrameworkFrame(%q) = %v, want %v", line, got, want)
+		}
+	}
+}
+
+func TestStacktraceOfIncidentSearchAndSave(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/job/job-7/stacktrace" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		if !strings.HasPrefix(r.Header.Get("Accept"), "text/plain") {
+			t.Errorf("expected a text/plain Accept header, got %q", r.Header.Get("Accept"))
+		}
+		w.Header().Set("Content-Type", "text/plain")
+		_, _ = w.Write([]byte(testStacktrace))
+	}))
+	defer srv.Close()
+
+	m := tableTestModel(t, srv.URL, "incident", []string{"id"}, []table.Row{{"inc-1"}})
+	m.lastWidth, m.lastHeight = 140, 40
+	m.rowData = []map[string]interface{}{{"id": "inc-1", "incidentType": "failedJob", "configuration": "job-7"}}
+
+	var item actionItem
+	for _, it := range m.buildActionsForRoot() {
+		if it.key == "e" {
+			item = it
+		}
+	}
+	if item.cmd == nil {
+		t.Fatal("expected Stacktrace in the incident actions")
+	}
+	loaded := findMsg[stacktraceLoadedMsg](t, item.cmd(&m))
+	if loaded.err != nil {
+		t.Fatalf("load failed: %v", loaded.err)
+	}
+	res, _ := m.Update(loaded)
+	m = res.(model)
+	body := m.modalStacktraceBody()
+	for _, want := range []string{"Booking failed", "BookDelegate.java:42", "▸ Caused by: java.net.ConnectException", "3 lines folded"} {
+		if !strings.Contains(body, want) {
+			t.Errorf("expected %q in stacktrace view, got %q", want, body)
+		}
+	}
+	if strings.Contains(body, "RestClient") {
+		t.Error("expected the frames of the cause to be folded")
+	}
+
+	// Searching a folded frame unfolds its cause and moves the cursor to it
+	for _, k := range []string{"/", "R", "e", "s", "t", "C", "l", "i", "enter"} {
+		m, _ = sendKeyString(m, k)
+	}
+	if m.stacktrace.sections[1].folded {
+		t.Error("expected the cause to be unfolded by the search match")
+	}
+	if l := m.stacktrace.visibleLines()[m.stacktrace.cursor]; l.section != 1 || l.index != 2 {
+		t.Errorf("expected the cursor on the RestClient frame, got %+v", l)
+	}
+
+	t.Chdir(t.TempDir())
+	m, _ = sendKeyString(m, "s")
+	data, err := os.ReadFile("stacktrace-job-7.txt")
+	if err != nil || string(data) != testStacktrace {
+		t.Errorf("expected the stacktrace saved to stacktrace-job-7.txt, got %q, %v", data, err)
+	}
+	if !strings.Contains(m.footerError, "stacktrace-job-7.txt") {
+		t.Errorf("expected the file name in the footer, got %q", m.footerError)
+	}
+}
+
+func TestStacktraceSourceRejectsIncidentsWithoutStacktrace(t *testing.T) {
+	m := tableTestModel(t, "http://localhost:0", "incident", []string{"id"}, []table.Row{{"inc-2"}})
+	m.rowData = []map[string]interface{}{{"id": "inc-2", "incidentType": "failedStartEvent"}}
+	if _, _, err := m.stacktraceSource("incident"); err == nil {
+		t.Error("expected an error for an incident without a job or external task")
+	}
+	m.currentRoot = "history-job-log"
+	path, id, err := m.stacktraceSource("history-job-log")
+	if err != nil || path != "/history/job-log/inc-2/stacktrace" || id != "inc-2" {
+		t.Errorf("unexpected job log source %q %q %v", path, id, err)
+	}
+}
+
+func TestStacktraceSourceUsesSortedIncidentRow(t *testing.T) {
+	m := tableTestModel(t, "http://localhost:0", "incident", []string{"id"}, []table.Row{{"inc-2"}, {"inc-1"}})
+	m.rowData = []map[string]interface{}{
+		{"id": "inc-1", "incidentType": "failedJob", "configuration": "job-1"},
+		{"id": "inc-2", "incidentType": "failedJob", "configuration": "job-2"},
+	}
+	m.sortColumn = 0
+	if path, id, err := m.stacktraceSource("incident"); err != nil || id != "job-2" || path != "/job/job-2/stacktrace" {
+		t.Errorf("expected the job of the selected incident inc-2, got %q %q %v", path, id, err)
+	}
+}
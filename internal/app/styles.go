@@ -83,6 +83,10 @@ type StyleSet struct {
 	RowFailed    lipgloss.Style
 	RowEnded     lipgloss.Style
 
+	// Auto-refresh change highlighting
+	RowAdded   lipgloss.Style
+	RowChanged lipgloss.Style
+
 	// Edit modal buttons
 	BtnSave          lipgloss.Style
 	BtnSaveFocused   lipgloss.Style
@@ -178,6 +182,8 @@ func buildStyleSet(skin *Skin) StyleSet {
 	s.RowSuspended = lipgloss.NewStyle().Foreground(col(skin, "warning"))
 	s.RowFailed = lipgloss.NewStyle().Foreground(col(skin, "danger"))
 	s.RowEnded = lipgloss.NewStyle().Foreground(col(skin, "fgMuted"))
+	s.RowAdded = lipgloss.NewStyle().Foreground(col(skin, "success")).Bold(true)
+	s.RowChanged = lipgloss.NewStyle().Foreground(col(skin, "warning")).Bold(true)
 
 	// ── Edit modal buttons ────────────────────────────────────────────────────
 	s.BtnSave = lipgloss.NewStyle().
@@ -138,6 +138,114 @@ func (m *model) buildColumnsFor(tableName string, totalWidth int) []table.Column
 	return cols
 }
 
+// applyGenericItems renders the items of a generic collection into the table
+// using the table definition of root, or columns inferred from the data.
+func (m *model) applyGenericItems(root string, items []map[string]interface{}) {
+	def := m.findTableDef(root)
+	var cols []table.Column
+	if def != nil {
+		cols = m.buildColumnsFor(root, m.paneWidth-4)
+		// If buildColumnsFor returned only the "EMPTY" fallback (when all columns are invisible),
+		// infer columns from the data instead
+		if len(cols) == 1 && cols[0].Title == "EMPTY" {
+			cols = nil
+		}
+	}
+	if len(cols) == 0 {
+		// infer columns from first item keys (after stripping _meta_count)
+		if len(items) > 0 {
+			keys := make([]string, 0, len(items[0]))
+			for k := range items[0] {
+				keys = append(keys, k)
+			}
+			sort.Strings(keys)
+			for _, k := range keys {
+				cols = append(cols, table.Column{Title: strings.ToUpper(k), Width: 20})
+			}
+		}
+	}
+	// Build rows from items; also capture raw data for drilldown column lookup
+	rows := make([]table.Row, 0, len(items))
+	rd := make([]map[string]interface{}, 0, len(items))
+	hasDrilldown := def != nil && def.Drilldown != nil
+	for _, it := range items {
+		rd = append(rd, it)
+		if len(cols) == 0 {
+			// fallback: add single column with JSON representation
+			rows = append(rows, table.Row{fmt.Sprintf("%v", it)})
+			continue
+		}
+		r := genericRow(it, cols)
+		if hasDrilldown && len(r) > 0 {
+			r[0] = "▶ " + r[0]
+		}
+		rows = append(rows, r)
+	}
+	m.rowData = rd
+
+	if len(cols) > 0 {
+		m.table.SetColumns(cols)
+	}
+	m.applyPendingSort(cols)
+	normalized := normalizeRows(rows, len(cols))
+	colorized := colorizeRows(root, normalized, cols, RowStyles{
+		Running:   m.styles.RowRunning,
+		Suspended: m.styles.RowSuspended,
+		Failed:    m.styles.RowFailed,
+		Ended:     m.styles.RowEnded,
+	})
+	if m.rowDiff.root == root {
+		colorized = m.highlightRowDiff(rd, normalized, colorized, cols)
+	}
+	m.setTableRowsSorted(colorized)
+	if m.sortColumn >= 0 {
+		m.applySortIndicatorToColumns()
+	}
+	// Track which resource is currently displayed.
+	m.viewMode = root
+	// re-apply locked search filter if active
+	if !m.searchMode && m.searchTerm != "" {
+		filtered := filterRows(m.table.Rows(), m.searchTerm)
+		m.table.SetRows(filtered)
+	}
+}
+
+// genericRow renders the cells of item for cols, looking each value up by the
+// column title.
+func genericRow(it map[string]interface{}, cols []table.Column) table.Row {
+	r := make(table.Row, len(cols))
+	for i, col := range cols {
+		// prefer original column name from TableDef when available
+		key := strings.ToLower(col.Title)
+		val := ""
+		var v interface{}
+		var found bool
+		if vv, ok := it[key]; ok {
+			v = vv
+			found = true
+		} else if vv, ok := it[strings.ToLower(col.Title)]; ok {
+			v = vv
+			found = true
+		} else if vv, ok := it[col.Title]; ok {
+			v = vv
+			found = true
+		}
+		if found {
+			if v == nil {
+				val = ""
+			} else if s, ok := v.(string); ok {
+				val = s
+			} else {
+				val = fmt.Sprintf("%v", v)
+			}
+		} else {
+			val = ""
+		}
+		r[i] = val
+	}
+	return r
+}
+
 // Update applyDefinitions and applyInstances to recover from panics and show footer error
 func (m *model) applyDefinitions(defs []config.ProcessDefinition) {
 	defer func() {
@@ -0,0 +1,319 @@
+package app
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/kthoms/o6n/internal/config"
+)
+
+// timelineEntry is one historic activity instance of the timeline (ModalTimeline).
+type timelineEntry struct {
+	id           string
+	activityID   string
+	name         string
+	activityType string
+	start        time.Time
+	end          time.Time // zero while running
+	canceled     bool
+	incident     bool
+}
+
+// timelineState holds the instance timeline.
+type timelineState struct {
+	instanceID string
+	entries    []timelineEntry
+	cursor     int
+	scroll     int
+	loading    bool
+	err        string
+	now        time.Time // end of running bars, fixed at load time
+}
+
+// timelineLoadedMsg delivers the historic activity instances of a process instance.
+type timelineLoadedMsg struct {
+	instanceID string
+	entries    []timelineEntry
+	err        error
+}
+
+// operatonTimeLayouts are the timestamp formats of the engine's JSON dates.
+var operatonTimeLayouts = []string{"2006-01-02T15:04:05.000-0700", time.RFC3339Nano, "2006-01-02T15:04:05"}
+
+// parseOperatonTime parses an engine timestamp; ok is false for null or malformed values.
+func parseOperatonTime(v interface{}) (time.Time, bool) {
+	s, _ := v.(string)
+	if s == "" {
+		return time.Time{}, false
+	}
+	for _, layout := range operatonTimeLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// fetchTimelineCmd loads the historic activity instances of instanceID ordered by
+// start time, and marks activities that had incidents.
+func (m model) fetchTimelineCmd(instanceID string) tea.Cmd {
+	env, ok := m.config.Environments[m.currentEnv]
+	if !ok {
+		return nil
+	}
+	return func() tea.Msg {
+		items, err := fetchAllRows(env, "/history/activity-instance", map[string]string{
+			"processInstanceId": instanceID, "sortBy": "startTime", "sortOrder": "asc",
+		}, 0, 0)
+		if err != nil {
+			return timelineLoadedMsg{instanceID: instanceID, err: err}
+		}
+		incidents, err := fetchAllRows(env, "/history/incident", map[string]string{"processInstanceId": instanceID}, 0, 0)
+		if err != nil {
+			return timelineLoadedMsg{instanceID: instanceID, err: err}
+		}
+		// An incident marks the activity instance it was raised in: the same
+		// activity in a loop or multi-instance body runs in other executions.
+		failed := make(map[string]bool)
+		for _, inc := range incidents {
+			if id, _ := inc["activityId"].(string); id != "" {
+				exec, _ := inc["executionId"].(string)
+				failed[id+"\x00"+exec] = true
+			}
+		}
+		entries := make([]timelineEntry, 0, len(items))
+		for _, it := range items {
+			e := timelineEntry{}
+			e.id, _ = it["id"].(string)
+			e.activityID, _ = it["activityId"].(string)
+			e.name, _ = it["activityName"].(string)
+			e.activityType, _ = it["activityType"].(string)
+			e.canceled, _ = it["canceled"].(bool)
+			exec, _ := it["executionId"].(string)
+			e.incident = failed[e.activityID+"\x00"+exec]
+			var ok bool
+			if e.start, ok = parseOperatonTime(it["startTime"]); !ok {
+				continue
+			}
+			e.end, _ = parseOperatonTime(it["endTime"])
+			if e.name == "" {
+				e.name = e.activityID
+			}
+			entries = append(entries, e)
+		}
+		// The engine sorts by start time already; keep ties stable in document order.
+		sort.SliceStable(entries, func(i, j int) bool { return entries[i].start.Before(entries[j].start) })
+		return timelineLoadedMsg{instanceID: instanceID, entries: entries}
+	}
+}
+
+// openTimeline opens the timeline of the selected process instance.
+func (m *model) openTimeline() tea.Cmd {
+	id := m.resolveActionID(config.ActionDef{})
+	if id == "" {
+		return nil
+	}
+	m.timeline = timelineState{instanceID: id, loading: true}
+	m.activeModal = ModalTimeline
+	return m.fetchTimelineCmd(id)
+}
+
+// applyTimeline stores loaded timeline entries if the timeline is still open.
+func (m *model) applyTimeline(msg timelineLoadedMsg) {
+	if m.activeModal != ModalTimeline || msg.instanceID != m.timeline.instanceID {
+		return
+	}
+	m.timeline.loading = false
+	m.timeline.entries = msg.entries
+	m.timeline.now = time.Now()
+	m.timeline.cursor = 0
+	m.timeline.scroll = 0
+	if msg.err != nil {
+		m.timeline.err = friendlyError(m.currentEnv, msg.err)
+	}
+}
+
+// handleTimelineKey processes a key press while ModalTimeline is open.
+func (m *model) handleTimelineKey(msg tea.KeyMsg) tea.Cmd {
+	n := len(m.timeline.entries)
+	switch msg.String() {
+	case "esc", "q":
+		m.activeModal = ModalNone
+		m.timeline = timelineState{}
+		return nil
+	case "down", "j":
+		if m.timeline.cursor < n-1 {
+			m.timeline.cursor++
+		}
+	case "up", "k":
+		if m.timeline.cursor > 0 {
+			m.timeline.cursor--
+		}
+	case "home", "g":
+		m.timeline.cursor = 0
+	case "end", "G":
+		m.timeline.cursor = n - 1
+	case "r":
+		m.timeline.loading = true
+		m.timeline.err = ""
+		return m.fetchTimelineCmd(m.timeline.instanceID)
+	case "v", "l", "enter":
+		if m.timeline.cursor >= n {
+			return nil
+		}
+		e := m.timeline.entries[m.timeline.cursor]
+		instanceID := m.timeline.instanceID
+		m.activeModal = ModalNone
+		m.timeline = timelineState{}
+		if msg.String() == "l" {
+			// Job log of the activity (async continuations, timers, failed jobs)
+			return m.drillDownTo("history-job-log", "Job Log", e.name,
+				map[string]string{"processInstanceId": instanceID, "activityIdIn": e.activityID})
+		}
+		return m.drillDownTo("history-detail", "Variables", e.name,
+			map[string]string{"activityInstanceId": e.id})
+	}
+	if m.timeline.cursor < 0 {
+		m.timeline.cursor = 0
+	}
+	h := m.timelineViewHeight()
+	if m.timeline.cursor < m.timeline.scroll {
+		m.timeline.scroll = m.timeline.cursor
+	} else if m.timeline.cursor >= m.timeline.scroll+h {
+		m.timeline.scroll = m.timeline.cursor - h + 1
+	}
+	return nil
+}
+
+// timelineViewHeight is the number of bars shown at once.
+func (m *model) timelineViewHeight() int {
+	h := m.lastHeight - 14
+	if h < 3 {
+		h = 3
+	}
+	return h
+}
+
+// formatTimelineDuration renders d compactly, e.g. "850ms", "42s", "3m12s", "2h05m", "3d4h".
+func formatTimelineDuration(d time.Duration) string {
+	switch {
+	case d < time.Second:
+		return fmt.Sprintf("%dms", d.Milliseconds())
+	case d < time.Minute:
+		return fmt.Sprintf("%ds", int(d.Seconds()))
+	case d < time.Hour:
+		return fmt.Sprintf("%dm%02ds", int(d.Minutes()), int(d.Seconds())%60)
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh%02dm", int(d.Hours()), int(d.Minutes())%60)
+	default:
+		return fmt.Sprintf("%dd%dh", int(d.Hours())/24, int(d.Hours())%24)
+	}
+}
+
+// timelineBar renders the bar of an entry within [from, to] scaled to width cells.
+func timelineBar(e timelineEntry, from, to time.Time, width int) string {
+	span := to.Sub(from)
+	if span <= 0 {
+		span = 1
+	}
+	end := e.end
+	if end.IsZero() {
+		end = to
+	}
+	startCol := int(float64(e.start.Sub(from)) / float64(span) * float64(width))
+	endCol := int(float64(end.Sub(from)) / float64(span) * float64(width))
+	if startCol >= width {
+		startCol = width - 1
+	}
+	if endCol <= startCol {
+		endCol = startCol + 1
+	}
+	if endCol > width {
+		endCol = width
+	}
+	fill := "█"
+	if e.end.IsZero() {
+		fill = "▓"
+	}
+	return strings.Repeat(" ", startCol) + strings.Repeat(fill, endCol-startCol) + strings.Repeat(" ", width-endCol)
+}
+
+// modalTimelineBody renders the timeline: one bar per activity instance, scaled
+// from the first start to the last end (or now while the instance runs).
+func (m *model) modalTimelineBody() string {
+	var b strings.Builder
+	b.WriteString(m.styles.Accent.Render("Timeline — instance "+m.timeline.instanceID) + "\n")
+	b.WriteString(m.styles.FgMuted.Render("█ completed  ▓ running  ") + m.styles.RowFailed.Render("█ canceled / incident") + "\n\n")
+	if m.timeline.loading {
+		b.WriteString("Loading…\n")
+	}
+	if m.timeline.err != "" {
+		b.WriteString(m.styles.ValidationError.Render(m.timeline.err) + "\n")
+	}
+	entries := m.timeline.entries
+	if len(entries) == 0 {
+		if !m.timeline.loading && m.timeline.err == "" {
+			b.WriteString(m.styles.FgMuted.Render("No historic activity instances (is history enabled?)") + "\n")
+		}
+		return b.String()
+	}
+
+	from, to := entries[0].start, entries[0].start
+	for _, e := range entries {
+		end := e.end
+		if end.IsZero() {
+			end = m.timeline.now
+		}
+		if end.After(to) {
+			to = end
+		}
+	}
+
+	innerW := int(float64(m.lastWidth)*0.80) - 6
+	if innerW < 54 {
+		innerW = 54
+	}
+	nameW := innerW / 3
+	if nameW > 32 {
+		nameW = 32
+	}
+	const durW = 8
+	barW := innerW - nameW - durW - 4
+
+	h := m.timelineViewHeight()
+	end := m.timeline.scroll + h
+	if end > len(entries) {
+		end = len(entries)
+	}
+	for i := m.timeline.scroll; i < end; i++ {
+		e := entries[i]
+		stop := e.end
+		if stop.IsZero() {
+			stop = m.timeline.now
+		}
+		name := ansi.Truncate(e.name, nameW, "…")
+		name += strings.Repeat(" ", nameW-ansi.StringWidth(name))
+		bar := timelineBar(e, from, to, barW)
+		switch {
+		case e.canceled || e.incident:
+			bar = m.styles.RowFailed.Render(bar)
+		case e.end.IsZero():
+			bar = m.styles.RowRunning.Render(bar)
+		default:
+			bar = m.styles.Accent.Render(bar)
+		}
+		prefix := "  "
+		if i == m.timeline.cursor {
+			prefix = "> "
+			name = m.styles.PopupCursor.Render(name)
+		}
+		fmt.Fprintf(&b, "%s%s │%s│ %*s\n", prefix, name, bar, durW, formatTimelineDuration(stop.Sub(e.start)))
+	}
+	fmt.Fprintf(&b, "\n%s", m.styles.FgMuted.Render(fmt.Sprintf("%s → %s  (%s)",
+		from.Local().Format("2006-01-02 15:04:05"), to.Local().Format("15:04:05"), formatTimelineDuration(to.Sub(from)))))
+	return b.String()
+}
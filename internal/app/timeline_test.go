@@ -0,0 +1,132 @@
+package app
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/bubbles/table"
+)
+
+func TestTimelineBarScalesToWidth(t *testing.T) {
+	from := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	to := from.Add(10 * time.Second)
+	e := timelineEntry{start: from.Add(2 * time.Second), end: from.Add(7 * time.Second)}
+	if got := timelineBar(e, from, to, 10); got != "  █████   " {
+		t.Errorf("unexpected bar %q", got)
+	}
+	// Zero-length activities still get one cell; running ones extend to the end
+	e = timelineEntry{start: to, end: to}
+	if got := timelineBar(e, from, to, 10); got != "         █" {
+		t.Errorf("unexpected bar for instant activity %q", got)
+	}
+	e = timelineEntry{start: from.Add(5 * time.Second)}
+	if got := timelineBar(e, from, to, 10); got != "     ▓▓▓▓▓" {
+		t.Errorf("unexpected bar for running activity %q", got)
+	}
+}
+
+func TestTimelineLoadsOrdersAndJumpsToDetails(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/history/activity-instance":
+			if r.URL.Query().Get("processInstanceId") != "pi-1" || r.URL.Query().Get("sortBy") != "startTime" {
+				t.Errorf("unexpected query %v", r.URL.Query())
+			}
+			_, _ = w.Write([]byte(`[
+				{"id":"a1","activityId":"start","activityType":"startEvent","startTime":"2024-01-01T10:00:00.000+0000","endTime":"2024-01-01T10:00:00.000+0000"},
+				{"id":"a2","activityId":"review","activityName":"Review","startTime":"2024-01-01T10:00:01.000+0000","endTime":"2024-01-01T10:05:00.000+0000","canceled":true},
+				{"id":"a3","activityId":"book","executionId":"ex-1","activityName":"Book","startTime":"2024-01-01T10:05:00.000+0000","endTime":null}
+			]`))
+		case "/history/incident":
+			_, _ = w.Write([]byte(`[{"id":"inc-1","activityId":"book","executionId":"ex-1"}]`))
+		default:
+			_, _ = w.Write([]byte(`[]`))
+		}
+	}))
+	defer srv.Close()
+
+	m := tableTestModel(t, srv.URL, "process-instance", []string{"id"}, []table.Row{{"pi-1"}})
+
+	var item actionItem
+	for _, it := range m.buildActionsForRoot() {
+		if it.key == "T" {
+			item = it
+		}
+	}
+	if item.cmd == nil {
+		t.Fatal("expected Timeline in the process-instance actions")
+	}
+	loaded := findMsg[timelineLoadedMsg](t, item.cmd(&m))
+	if loaded.err != nil {
+		t.Fatalf("load failed: %v", loaded.err)
+	}
+	res, _ := m.Update(loaded)
+	m = res.(model)
+	if len(m.timeline.entries) != 3 {
+		t.Fatalf("expected 3 entries, got %+v", m.timeline.entries)
+	}
+	review, book := m.timeline.entries[1], m.timeline.entries[2]
+	if !review.canceled || !book.incident || !book.end.IsZero() {
+		t.Errorf("expected canceled review and running book with incident, got %+v %+v", review, book)
+	}
+	body := m.modalTimelineBody()
+	for _, want := range []string{"Timeline — instance pi-1", "Review", "4m59s", "▓"} {
+		if !strings.Contains(body, want) {
+			t.Errorf("expected %q in timeline, got %q", want, body)
+		}
+	}
+
+	m, _ = sendKeyString(m, "down")
+	m, _ = sendKeyString(m, "down")
+	m, _ = sendKeyString(m, "l")
+	if m.activeModal != ModalNone || m.currentRoot != "history-job-log" || m.genericParams["activityIdIn"] != "book" {
+		t.Fatalf("expected job log of book, got root %s %v", m.currentRoot, m.genericParams)
+	}
+	if len(m.navigationStack) != 1 {
+		t.Error("expected the instance view on the navigation stack")
+	}
+}
+
+func TestTimelineMarksIncidentOnlyInItsExecution(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/history/activity-instance":
+			// book runs twice in a loop; only the second pass fails
+			_, _ = w.Write([]byte(`[
+				{"id":"a1","activityId":"book","executionId":"ex-1","startTime":"2024-01-01T10:00:00.000+0000","endTime":"2024-01-01T10:01:00.000+0000"},
+				{"id":"a2","activityId":"book","executionId":"ex-2","startTime":"2024-01-01T10:02:00.000+0000","endTime":null}
+			]`))
+		case "/history/incident":
+			_, _ = w.Write([]byte(`[{"id":"inc-1","activityId":"book","executionId":"ex-2"}]`))
+		default:
+			_, _ = w.Write([]byte(`[]`))
+		}
+	}))
+	defer srv.Close()
+
+	m := tableTestModel(t, srv.URL, "process-instance", []string{"id"}, []table.Row{{"pi-1"}})
+	loaded := findMsg[timelineLoadedMsg](t, m.fetchTimelineCmd("pi-1"))
+	if len(loaded.entries) != 2 || loaded.entries[0].incident || !loaded.entries[1].incident {
+		t.Errorf("expected only the second pass of book marked, got %+v", loaded.entries)
+	}
+}
+
+func TestFormatTimelineDuration(t *testing.T) {
+	cases := map[time.Duration]string{
+		850 * time.Millisecond:         "850ms",
+		42 * time.Second:               "42s",
+		3*time.Minute + 12*time.Second: "3m12s",
+		2*time.Hour + 5*time.Minute:    "2h05m",
+		76 * time.Hour:                 "3d4h",
+	}
+	for d, want := range cases {
+		if got := formatTimelineDuration(d); got != want {
+			t.Errorf("formatTimelineDuration(%v) = %q, want %q", d, got, want)
+		}
+	}
+}
@@ -1,6 +1,10 @@
 package app
 
-import "github.com/charmbracelet/bubbles/table"
+import (
+	"context"
+
+	"github.com/charmbracelet/bubbles/table"
+)
 
 // TransitionType identifies the category of navigation state transition.
 // Every navigation path in internal/app/ MUST call prepareStateTransition before
@@ -20,7 +24,7 @@ const (
 
 	// TransitionPop pops the top viewState from navigationStack and restores all captured
 	// fields (viewMode, breadcrumb, contentHeader, selectedKeys, tableRows, tableColumns,
-	// tableCursor, genericParams, rowData). Performs no clearing.
+	// tableCursor, genericParams, queryFilters, rowData). Performs no clearing.
 	// Use for: Esc (back) and breadcrumb jump to non-root level (after caller truncates stack).
 	TransitionPop
 )
@@ -28,6 +32,7 @@ const (
 // prepareStateTransition is the single mandatory gate for all navigation changes.
 // Call it before modifying any view state in a navigation handler.
 func (m *model) prepareStateTransition(t TransitionType) {
+	m.cancelViewRequests()
 	switch t {
 	case TransitionFull:
 		// Clear all view state for a fresh navigation context.
@@ -36,6 +41,7 @@ func (m *model) prepareStateTransition(t TransitionType) {
 		m.footerStatusKind = footerStatusNone
 		m.sortColumn = -1
 		m.sortAscending = true
+		m.pendingSort = ""
 		m.searchTerm = ""
 		m.searchMode = false
 		m.searchInput.Blur()
@@ -43,9 +49,12 @@ func (m *model) prepareStateTransition(t TransitionType) {
 		m.filteredRows = nil
 		m.navigationStack = nil
 		m.genericParams = make(map[string]string)
+		m.queryFilters = nil
+		m.queryBar = queryBarState{}
 		m.selectedDefinitionKey = ""
 		m.selectedInstanceID = ""
 		m.table.SetCursor(0)
+		m.markedRows = nil
 		if m.popup.mode != popupModeNone {
 			m.popup.mode = popupModeNone
 			m.popup.input = ""
@@ -74,6 +83,7 @@ func (m *model) prepareStateTransition(t TransitionType) {
 			cachedDefinitions:     m.cachedDefinitions,
 			tableColumns:          append([]table.Column{}, cols...),
 			genericParams:         m.genericParams,
+			queryFilters:          m.queryFilters,
 			rowData:               append([]map[string]interface{}{}, m.rowData...),
 		}
 		m.navigationStack = append(m.navigationStack, snapshot)
@@ -83,11 +93,15 @@ func (m *model) prepareStateTransition(t TransitionType) {
 		m.footerStatusKind = footerStatusNone
 		m.sortColumn = -1
 		m.sortAscending = true
+		m.pendingSort = ""
 		m.searchTerm = ""
 		m.searchMode = false
 		m.searchInput.Blur()
 		m.originalRows = nil
 		m.filteredRows = nil
+		m.markedRows = nil
+		m.queryFilters = nil
+		m.queryBar = queryBarState{}
 		if m.popup.mode == popupModeSearch {
 			m.popup.mode = popupModeNone
 			m.popup.input = ""
@@ -109,7 +123,10 @@ func (m *model) prepareStateTransition(t TransitionType) {
 		m.selectedInstanceID = top.selectedInstanceID
 		m.cachedDefinitions = top.cachedDefinitions
 		m.genericParams = top.genericParams
+		m.queryFilters = top.queryFilters
+		m.queryBar = queryBarState{}
 		m.rowData = top.rowData
+		m.markedRows = nil
 		// Restore table widget state: columns first, then rows, then cursor.
 		if len(top.tableColumns) > 0 {
 			m.table.SetRows(normalizeRows(nil, len(top.tableColumns)))
@@ -125,6 +142,25 @@ func (m *model) prepareStateTransition(t TransitionType) {
 	}
 }
 
+// requestContext returns the context for fetches of the current view. It is
+// cancelled by the next navigation, so a slow response for a view the user has
+// already left never replaces the new view's data.
+func (m *model) requestContext() context.Context {
+	if m.viewCtx == nil {
+		return context.Background()
+	}
+	return m.viewCtx
+}
+
+// cancelViewRequests aborts the in-flight fetches of the current view and starts
+// a new request scope.
+func (m *model) cancelViewRequests() {
+	if m.viewCancel != nil {
+		m.viewCancel()
+	}
+	m.viewCtx, m.viewCancel = context.WithCancel(context.Background())
+}
+
 // clampCursorAfterRowRemoval ensures the table cursor stays within bounds after
 // rows are removed (e.g. after terminate, delete). Safe to call always.
 func (m *model) clampCursorAfterRowRemoval() {
@@ -0,0 +1,330 @@
+package app
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// triageMaxJobs caps the failed jobs loaded into the triage view.
+const triageMaxJobs = 5000
+
+// triageDrillMaxIDs caps the jobIds `o` puts into the job table's URL; larger
+// groups open every failed job of their activity instead.
+const triageDrillMaxIDs = 100
+
+// Volatile parts of exception messages that are dropped when grouping: ids,
+// timestamps and long numbers such as ports or row ids.
+var (
+	triageUUIDPattern   = regexp.MustCompile(`[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}`)
+	triageTimePattern   = regexp.MustCompile(`\d{4}-\d{2}-\d{2}[T ]\d{2}:\d{2}(:\d{2}(\.\d+)?)?(Z|[+-]\d{2}:?\d{2})?`)
+	triageNumberPattern = regexp.MustCompile(`\d{4,}`)
+)
+
+// normalizeExceptionMessage reduces msg to what jobs failing for the same
+// reason have in common, e.g. "Order 48213 not found" → "Order # not found".
+func normalizeExceptionMessage(msg string) string {
+	msg = triageUUIDPattern.ReplaceAllString(msg, "<id>")
+	msg = triageTimePattern.ReplaceAllString(msg, "<time>")
+	msg = triageNumberPattern.ReplaceAllString(msg, "#")
+	return strings.Join(strings.Fields(msg), " ")
+}
+
+// triageGroup is the failed jobs with one normalized exception message in one activity.
+type triageGroup struct {
+	message    string // normalized exception message
+	activityID string
+	definition string   // process definition key of the first job
+	example    string   // exception message of the first job
+	jobIDs     []string // the first job is the representative
+}
+
+// triageState holds the failed-job triage view (ModalTriage).
+type triageState struct {
+	groups    []triageGroup
+	total     int // failed jobs loaded
+	truncated bool
+	cursor    int
+	scroll    int
+	retrying  bool // typing the retries to set for the selected group
+	retries   string
+	loading   bool
+	err       string
+}
+
+// triageLoadedMsg delivers the failed jobs of the current environment, grouped.
+type triageLoadedMsg struct {
+	groups    []triageGroup
+	total     int
+	truncated bool
+	err       error
+}
+
+// openTriageGroup opens the job table on the failed jobs of g. A group too large
+// to list by jobIds shows every failed job of its activity, with a notice.
+func (m *model) openTriageGroup(g triageGroup) tea.Cmd {
+	title := ansi.Truncate(g.message, 40, "…")
+	if len(g.jobIDs) <= triageDrillMaxIDs {
+		return m.drillDownTo("job", "Failed Jobs", title, map[string]string{"jobIds": strings.Join(g.jobIDs, ",")})
+	}
+	params := map[string]string{"withException": "true", "noRetriesLeft": "true"}
+	scope := "every failed job"
+	if g.activityID != "" {
+		params["activityId"] = g.activityID
+		scope += " of " + g.activityID
+	}
+	nav := m.drillDownTo("job", "Failed Jobs", title, params)
+	msg, kind, cmd := setFooterStatus(footerStatusInfo,
+		fmt.Sprintf("%d jobs are too many to list: showing %s", len(g.jobIDs), scope), 5*time.Second)
+	m.footerError, m.footerStatusKind = msg, kind
+	return tea.Batch(nav, cmd)
+}
+
+// groupFailedJobs groups jobs by normalized exceptionMessage and
+// failedActivityId, largest groups first.
+func groupFailedJobs(jobs []map[string]interface{}) []triageGroup {
+	index := make(map[string]int)
+	var groups []triageGroup
+	for _, j := range jobs {
+		id, _ := j["id"].(string)
+		if id == "" {
+			continue
+		}
+		raw, _ := j["exceptionMessage"].(string)
+		activity, _ := j["failedActivityId"].(string)
+		msg := normalizeExceptionMessage(raw)
+		key := msg + "\x00" + activity
+		i, ok := index[key]
+		if !ok {
+			definition, _ := j["processDefinitionKey"].(string)
+			i = len(groups)
+			index[key] = i
+			groups = append(groups, triageGroup{message: msg, activityID: activity, definition: definition, example: raw})
+		}
+		groups[i].jobIDs = append(groups[i].jobIDs, id)
+	}
+	sort.SliceStable(groups, func(a, b int) bool { return len(groups[a].jobIDs) > len(groups[b].jobIDs) })
+	return groups
+}
+
+// fetchTriageCmd loads the failed jobs without retries left and groups them.
+func (m model) fetchTriageCmd() tea.Cmd {
+	env, ok := m.config.Environments[m.currentEnv]
+	if !ok {
+		return nil
+	}
+	return func() tea.Msg {
+		jobs, err := fetchAllRows(env, "/job", map[string]string{"withException": "true", "noRetriesLeft": "true"}, 0, triageMaxJobs)
+		if err != nil {
+			return triageLoadedMsg{err: err}
+		}
+		return triageLoadedMsg{groups: groupFailedJobs(jobs), total: len(jobs), truncated: len(jobs) >= triageMaxJobs}
+	}
+}
+
+// openTriage opens the failed-job triage view on the current environment.
+func (m *model) openTriage() tea.Cmd {
+	m.triage = triageState{loading: true}
+	m.activeModal = ModalTriage
+	return m.fetchTriageCmd()
+}
+
+// applyTriage stores loaded groups if the triage view is still open.
+func (m *model) applyTriage(msg triageLoadedMsg) {
+	if m.activeModal != ModalTriage {
+		return
+	}
+	m.triage.loading = false
+	m.triage.err = ""
+	if msg.err != nil {
+		m.triage.err = friendlyError(m.currentEnv, msg.err)
+		return
+	}
+	m.triage.groups = msg.groups
+	m.triage.total = msg.total
+	m.triage.truncated = msg.truncated
+	if m.triage.cursor >= len(msg.groups) {
+		m.triage.cursor, m.triage.scroll = 0, 0
+	}
+}
+
+// retryTriageGroup sets the retries of every job of the selected group: a
+// single job directly, larger groups as a tracked /job/retries batch.
+func (m *model) retryTriageGroup(retries int) tea.Cmd {
+	if m.triage.cursor >= len(m.triage.groups) {
+		return nil
+	}
+	g := m.triage.groups[m.triage.cursor]
+	label := fmt.Sprintf("Retry %d jobs", len(g.jobIDs))
+	if retries != 1 {
+		label = fmt.Sprintf("Set %d retries on %d jobs", retries, len(g.jobIDs))
+	}
+	ids := g.jobIDs
+	return m.guardMutation(label, "", ModalTriage, func(m *model) tea.Cmd {
+		m.activeModal = ModalNone
+		m.triage = triageState{}
+		if len(ids) == 1 {
+			return m.setJobRetriesCmd(ids[0], retries)
+		}
+		m.footerError, m.footerStatusKind, _ = setFooterStatus(footerStatusLoading, label+": submitting batch…", 0)
+		return tea.Batch(m.setJobRetriesBatchCmd(label, ids, retries), flashOnCmd())
+	})
+}
+
+// handleTriageKey processes a key press while ModalTriage is open.
+func (m *model) handleTriageKey(msg tea.KeyMsg) tea.Cmd {
+	t := &m.triage
+	if t.retrying {
+		switch msg.String() {
+		case "esc":
+			t.retrying = false
+		case "enter":
+			n, err := strconv.Atoi(t.retries)
+			if err != nil || n < 1 {
+				t.err = "retries must be a positive number"
+				return nil
+			}
+			t.retrying = false
+			t.err = ""
+			return m.retryTriageGroup(n)
+		case "backspace":
+			if t.retries != "" {
+				t.retries = t.retries[:len(t.retries)-1]
+			}
+		default:
+			if r := msg.Runes; len(r) == 1 && r[0] >= '0' && r[0] <= '9' && len(t.retries) < 3 {
+				t.retries += string(r)
+			}
+		}
+		return nil
+	}
+
+	n := len(t.groups)
+	switch msg.String() {
+	case "esc", "q":
+		m.activeModal = ModalNone
+		m.triage = triageState{}
+		return nil
+	case "down", "j":
+		if t.cursor < n-1 {
+			t.cursor++
+		}
+	case "up", "k":
+		if t.cursor > 0 {
+			t.cursor--
+		}
+	case "home", "g":
+		t.cursor = 0
+	case "end", "G":
+		t.cursor = n - 1
+	case "r":
+		t.loading = true
+		t.err = ""
+		return m.fetchTriageCmd()
+	case "enter", "e":
+		if t.cursor >= n {
+			return nil
+		}
+		id := t.groups[t.cursor].jobIDs[0]
+		return m.showStacktrace("job "+id, "/job/"+url.PathEscape(id)+"/stacktrace", id, ModalTriage)
+	case "o":
+		if t.cursor >= n {
+			return nil
+		}
+		g := t.groups[t.cursor]
+		m.activeModal = ModalNone
+		m.triage = triageState{}
+		return m.openTriageGroup(g)
+	case "R":
+		return m.retryTriageGroup(1)
+	case "+":
+		if t.cursor < n {
+			t.retrying = true
+			t.retries = "3"
+		}
+	}
+	if t.cursor < 0 {
+		t.cursor = 0
+	}
+	h := m.triageViewHeight()
+	if t.cursor < t.scroll {
+		t.scroll = t.cursor
+	} else if t.cursor >= t.scroll+h {
+		t.scroll = t.cursor - h + 1
+	}
+	return nil
+}
+
+// triageViewHeight is the number of groups listed at once; the rest of the
+// modal shows the selected group.
+func (m *model) triageViewHeight() int {
+	h := m.lastHeight - 20
+	if h < 3 {
+		h = 3
+	}
+	return h
+}
+
+// modalTriageBody renders the groups of failed jobs, largest first, with the
+// selected group's details below.
+func (m *model) modalTriageBody() string {
+	t := &m.triage
+	var b strings.Builder
+	b.WriteString(m.styles.Accent.Render("Failed job triage — "+m.currentEnv) + "\n")
+	summary := fmt.Sprintf("%d failed jobs without retries in %d groups", t.total, len(t.groups))
+	if t.truncated {
+		summary += fmt.Sprintf(" (first %d loaded)", triageMaxJobs)
+	}
+	b.WriteString(m.styles.FgMuted.Render(summary) + "\n\n")
+	if t.loading {
+		b.WriteString("Loading…\n")
+	}
+	if t.err != "" {
+		b.WriteString(m.styles.ValidationError.Render(t.err) + "\n")
+	}
+	if len(t.groups) == 0 {
+		if !t.loading && t.err == "" {
+			b.WriteString(m.styles.FgMuted.Render("No failed jobs") + "\n")
+		}
+		return b.String()
+	}
+
+	innerW := int(float64(m.lastWidth)*0.80) - 6
+	if innerW < 54 {
+		innerW = 54
+	}
+	end := t.scroll + m.triageViewHeight()
+	if end > len(t.groups) {
+		end = len(t.groups)
+	}
+	for i := t.scroll; i < end; i++ {
+		g := t.groups[i]
+		line := fmt.Sprintf("%6d  %-24s %s", len(g.jobIDs), ansi.Truncate(g.activityID, 24, "…"), g.message)
+		line = ansi.Truncate(line, innerW-2, "…")
+		if i == t.cursor {
+			line = "> " + m.styles.PopupCursor.Render(line)
+		} else {
+			line = "  " + line
+		}
+		b.WriteString(line + "\n")
+	}
+
+	if t.cursor < len(t.groups) {
+		g := t.groups[t.cursor]
+		b.WriteString("\n")
+		fmt.Fprintf(&b, "%d jobs in %s (%s), e.g. job %s\n", len(g.jobIDs), g.activityID, g.definition, g.jobIDs[0])
+		b.WriteString(m.styles.RowFailed.Render(ansi.Truncate(strings.Join(strings.Fields(g.example), " "), innerW, "…")) + "\n")
+	}
+	if t.retrying {
+		b.WriteString("\nRetries to set: " + m.styles.PopupInput.Render(t.retries) + "█  " +
+			m.styles.FgMuted.Render("Enter submit · Esc cancel"))
+	}
+	return b.String()
+}
@@ -0,0 +1,157 @@
+package app
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestNormalizeExceptionMessage(t *testing.T) {
+	cases := map[string]string{
+		"Order 48213 not found":                                   "Order # not found",
+		"Lock 0f8fad5b-d9cb-469f-a165-70867728950e expired":       "Lock <id> expired",
+		"Timeout at 2024-01-01T10:00:00.123+0100 after   30s":     "Timeout at <time> after 30s",
+		"HTTP 503 from http://billing:8080/api/invoices/99887766": "HTTP 503 from http://billing:#/api/invoices/#",
+	}
+	for in, want := range cases {
+		if got := normalizeExceptionMessage(in); got != want {
+			t.Errorf("normalizeExceptionMessage(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestGroupFailedJobsByMessageAndActivity(t *testing.T) {
+	groups := groupFailedJobs([]map[string]interface{}{
+		{"id": "j1", "exceptionMessage": "Order 1001 not found", "failedActivityId": "book"},
+		{"id": "j2", "exceptionMessage": "Connection refused", "failedActivityId": "charge"},
+		{"id": "j3", "exceptionMessage": "Order 2002 not found", "failedActivityId": "book"},
+		{"id": "j4", "exceptionMessage": "Order 3003 not found", "failedActivityId": "ship"},
+		{"id": "j5", "exceptionMessage": "Order 4004 not found", "failedActivityId": "book"},
+	})
+	if len(groups) != 3 {
+		t.Fatalf("expected 3 groups, got %+v", groups)
+	}
+	if g := groups[0]; g.activityID != "book" || len(g.jobIDs) != 3 || g.jobIDs[0] != "j1" || g.example != "Order 1001 not found" {
+		t.Errorf("expected the book group largest with j1 as representative, got %+v", g)
+	}
+}
+
+func TestTriageLoadsGroupsOpensStacktraceAndRetriesGroup(t *testing.T) {
+	var batchBody map[string]interface{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/job":
+			if r.URL.Query().Get("withException") != "true" || r.URL.Query().Get("noRetriesLeft") != "true" {
+				t.Errorf("unexpected query %v", r.URL.Query())
+			}
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`[
+				{"id":"j1","exceptionMessage":"Order 1001 not found","failedActivityId":"book","processDefinitionKey":"order"},
+				{"id":"j2","exceptionMessage":"Order 1002 not found","failedActivityId":"book","processDefinitionKey":"order"},
+				{"id":"j3","exceptionMessage":"Connection refused","failedActivityId":"charge","processDefinitionKey":"order"}
+			]`))
+		case r.URL.Path == "/job/j1/stacktrace":
+			w.Header().Set("Content-Type", "text/plain")
+			_, _ = w.Write([]byte("java.lang.IllegalStateException: Order 1001 not found\n\tat com.acme.Book.execute(Book.java:1)\n"))
+		case r.Method == http.MethodPost && r.URL.Path == "/job/retries":
+			_ = json.NewDecoder(r.Body).Decode(&batchBody)
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"id":"batch-9"}`))
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	defer srv.Close()
+
+	m := tableTestModel(t, srv.URL, "job", []string{"id"}, nil)
+	m.lastWidth, m.lastHeight = 140, 40
+
+	cmd, err := m.runCommand("triage")
+	if err != nil || m.activeModal != ModalTriage {
+		t.Fatalf("expected :triage to open the triage view, got %v %v", m.activeModal, err)
+	}
+	res, _ := m.Update(findMsg[triageLoadedMsg](t, cmd))
+	m = res.(model)
+	body := m.modalTriageBody()
+	for _, want := range []string{"3 failed jobs without retries in 2 groups", "Order # not found", "Connection refused"} {
+		if !strings.Contains(body, want) {
+			t.Errorf("expected %q in triage view, got %q", want, body)
+		}
+	}
+
+	// The representative stacktrace returns to the triage view
+	m, cmd = sendKeyString(m, "enter")
+	res, _ = m.Update(findMsg[stacktraceLoadedMsg](t, cmd))
+	m = res.(model)
+	if m.activeModal != ModalStacktrace || !strings.Contains(m.stacktrace.raw, "Order 1001") {
+		t.Fatalf("expected the stacktrace of j1, got modal %v %q", m.activeModal, m.stacktrace.err)
+	}
+	m, _ = sendKeyString(m, "esc")
+	if m.activeModal != ModalTriage || len(m.triage.groups) != 2 {
+		t.Fatalf("expected to return to the triage view, got %v", m.activeModal)
+	}
+
+	// Set 5 retries on the two jobs of the first group as a batch
+	for _, k := range []string{"+", "backspace", "5", "enter"} {
+		m, cmd = sendKeyString(m, k)
+	}
+	submitted := findMsg[batchSubmittedMsg](t, cmd)
+	if submitted.batchID != "batch-9" || m.activeModal != ModalNone {
+		t.Errorf("expected batch-9 submitted and the view closed, got %+v %v", submitted, m.activeModal)
+	}
+	ids, _ := batchBody["jobIds"].([]interface{})
+	if len(ids) != 2 || ids[0] != "j1" || ids[1] != "j2" || batchBody["retries"] != float64(5) {
+		t.Errorf("unexpected batch body %v", batchBody)
+	}
+}
+
+func TestTriageRetryOfSingleJobSetsRetriesDirectly(t *testing.T) {
+	var path string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.Method + " " + r.URL.Path
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	m := tableTestModel(t, srv.URL, "job", []string{"id"}, nil)
+	m.activeModal = ModalTriage
+	m.triage = triageState{groups: []triageGroup{{message: "Connection refused", jobIDs: []string{"j3"}}}, total: 1}
+
+	m, cmd := sendKeyString(m, "R")
+	if msg := findMsg[retriedMsg](t, cmd); msg.id != "j3" {
+		t.Errorf("expected j3 retried, got %+v", msg)
+	}
+	if path != "PUT /job/j3/retries" {
+		t.Errorf("expected PUT /job/j3/retries, got %q", path)
+	}
+	if m.activeModal != ModalNone {
+		t.Errorf("expected the triage view closed, got %v", m.activeModal)
+	}
+}
+
+func TestTriageOpenListsSmallGroupsAndFiltersLargeOnes(t *testing.T) {
+	m := tableTestModel(t, "http://localhost:0", "job", []string{"id"}, nil)
+	m.activeModal = ModalTriage
+	m.triage = triageState{groups: []triageGroup{{message: "Connection refused", activityID: "charge", jobIDs: []string{"j1", "j2"}}}, total: 2}
+
+	m, _ = sendKeyString(m, "o")
+	if m.currentRoot != "job" || m.genericParams["jobIds"] != "j1,j2" {
+		t.Fatalf("expected the job table on j1,j2, got %s %v", m.currentRoot, m.genericParams)
+	}
+
+	ids := make([]string, triageDrillMaxIDs+1)
+	for i := range ids {
+		ids[i] = "j" + strings.Repeat("x", i)
+	}
+	m.activeModal = ModalTriage
+	m.triage = triageState{groups: []triageGroup{{message: "Connection refused", activityID: "charge", jobIDs: ids}}, total: len(ids)}
+	m, _ = sendKeyString(m, "o")
+	if _, ok := m.genericParams["jobIds"]; ok || m.genericParams["activityId"] != "charge" || m.genericParams["noRetriesLeft"] != "true" {
+		t.Errorf("expected a large group filtered by activity, got %v", m.genericParams)
+	}
+	if !strings.Contains(m.footerError, "101 jobs are too many to list") {
+		t.Errorf("expected a notice, got %q", m.footerError)
+	}
+}
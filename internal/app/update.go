@@ -109,6 +109,11 @@ func (m model) Update(msg tea.Msg) (retModel tea.Model, retCmd tea.Cmd) {
 			}
 		}
 
+		// Query parameter filter bar keys
+		if m.queryBar.active && m.activeModal == ModalNone {
+			return m, m.handleQueryBarKey(msg)
+		}
+
 		// Handle sort popup keys
 		if m.activeModal == ModalSort {
 			switch s {
@@ -227,6 +232,10 @@ func (m model) Update(msg tea.Msg) (retModel tea.Model, retCmd tea.Cmd) {
 				m.firstRunNeeded = false
 				m.firstRunInput = ""
 				m.firstRunCursor = 0
+				m.dashboard.home = selected == dashboardContext
+				if m.dashboard.home {
+					return m, tea.Batch(m.openDashboard(), m.saveStateCmd())
+				}
 				m.prepareStateTransition(TransitionFull)
 				m.currentRoot = selected
 				m.breadcrumb = []string{selected}
@@ -282,54 +291,17 @@ func (m model) Update(msg tea.Msg) (retModel tea.Model, retCmd tea.Cmd) {
 				m.popup.input = ""
 				m.popup.cursor = -1
 				m.popup.offset = 0
+				m.commandErr = ""
 				return m, nil
 			case "enter":
-				items := m.popupItems()
-				if len(items) == 0 {
-					return m, nil
-				}
-				selected := ""
-				if m.popup.cursor >= 0 && m.popup.cursor < len(items) {
-					selected = items[m.popup.cursor]
-				} else if len(m.popup.input) > 0 {
-					// try to find exact match if cursor not set
-					for _, it := range items {
-						if it == m.popup.input {
-							selected = it
-							break
-						}
-					}
-				}
-
-				if selected != "" {
-					m.activeModal = ModalNone
-					m.popup.input = ""
-					m.popup.cursor = -1
-					m.popup.offset = 0
-
-					m.prepareStateTransition(TransitionFull)
-					m.currentRoot = selected
-					m.breadcrumb = []string{selected}
-					m.contentHeader = selected
-					m.viewMode = selected
-
-					// Set columns immediately if table def exists
-					if def := m.findTableDef(selected); def != nil {
-						cols := m.buildColumnsFor(selected, m.paneWidth-4)
-						if len(cols) > 0 {
-							m.table.SetRows([]table.Row{})
-							m.table.SetColumns(cols)
-							m.table.SetRows(normalizeRows(nil, len(cols)))
-						}
-					}
-					m.table.SetCursor(0)
-
-					m.isLoading = true
-					m.apiCallStarted = time.Now()
-					return m, tea.Batch(m.fetchForRoot(selected), flashOnCmd(), spinnerTickCmd(), m.saveStateCmd())
-				}
+				return m, m.submitCommand()
+			case "ctrl+p":
+				m.browseCommandHistory(-1)
 				return m, nil
-			case "up", "k":
+			case "ctrl+n":
+				m.browseCommandHistory(1)
+				return m, nil
+			case "up":
 				items := m.popupItems()
 				if len(items) > 0 {
 					if m.popup.cursor > 0 {
@@ -345,7 +317,7 @@ func (m model) Update(msg tea.Msg) (retModel tea.Model, retCmd tea.Cmd) {
 					}
 				}
 				return m, nil
-			case "down", "j":
+			case "down":
 				items := m.popupItems()
 				if len(items) > 0 {
 					if m.popup.cursor < len(items)-1 {
@@ -361,30 +333,46 @@ func (m model) Update(msg tea.Msg) (retModel tea.Model, retCmd tea.Cmd) {
 					}
 				}
 				return m, nil
+			case "ctrl+d":
+				// delete the selected saved view
+				items := m.popupItems()
+				if m.popup.cursor >= 0 && m.popup.cursor < len(items) {
+					if name, ok := strings.CutPrefix(items[m.popup.cursor], viewPrefix); ok {
+						m.popup.cursor = -1
+						m.popup.offset = 0
+						return m, m.deleteSavedView(name)
+					}
+				}
+				return m, nil
 			case "backspace":
 				if len(m.popup.input) > 0 {
 					runes := []rune(m.popup.input)
 					m.popup.input = string(runes[:len(runes)-1])
 					m.popup.cursor = -1
 					m.popup.offset = 0
+					m.commandErr = ""
 				}
 				return m, nil
 			case "tab":
 				items := m.popupItems()
 				if len(items) > 0 && len(m.popup.input) > 0 {
 					if m.popup.cursor >= 0 && m.popup.cursor < len(items) {
-						m.popup.input = items[m.popup.cursor]
+						m.completeCommand(items[m.popup.cursor])
 					} else {
-						m.popup.input = items[0]
+						m.completeCommand(items[0])
 					}
-					m.popup.cursor = -1
 				}
 				return m, nil
 			default:
-				if msg.Type == tea.KeyRunes {
-					m.popup.input += string(msg.Runes)
+				if msg.Type == tea.KeyRunes || msg.Type == tea.KeySpace {
+					if msg.Type == tea.KeySpace {
+						m.popup.input += " "
+					} else {
+						m.popup.input += string(msg.Runes)
+					}
 					m.popup.cursor = -1
 					m.popup.offset = 0
+					m.commandErr = ""
 				}
 				return m, nil
 			}
@@ -502,14 +490,7 @@ func (m model) Update(msg tea.Msg) (retModel tea.Model, retCmd tea.Cmd) {
 					targetEnv := m.envNames[m.envPopupCursor]
 					m.activeModal = ModalNone
 					if targetEnv != m.currentEnv {
-						m.switchToEnvironment(targetEnv)
-						m.prepareStateTransition(TransitionFull)
-						m.currentRoot = dao.ResourceProcessDefinitions
-						m.contentHeader = dao.ResourceProcessDefinitions
-						m.breadcrumb = []string{m.currentRoot}
-						m.isLoading = true
-						m.apiCallStarted = time.Now()
-						return m, tea.Batch(m.fetchDefinitionsCmd(), flashOnCmd(), m.checkEnvironmentHealthCmd(targetEnv), spinnerTickCmd(), m.saveStateCmd())
+						return m, m.changeEnvironment(targetEnv)
 					}
 				}
 				return m, nil
@@ -551,15 +532,13 @@ func (m model) Update(msg tea.Msg) (retModel tea.Model, retCmd tea.Cmd) {
 		if m.activeModal == ModalEdit {
 			row := m.currentEditRow()
 			col := m.currentEditColumn()
-			inputType, typeName := "text", "String"
+			inputType := "text"
 			if row != nil && col != nil {
-				inputType, typeName = m.resolveEditTypes(col.def, m.editTableKey, row)
+				inputType, _ = m.resolveEditTypes(col.def, m.editTableKey, row)
 			}
 			switch s {
 			case "esc":
-				m.activeModal = ModalNone
-				m.editError = ""
-				m.editInput.Blur()
+				m.closeEdit()
 				return m, nil
 			case "tab":
 				switch m.editFocus {
@@ -608,66 +587,13 @@ func (m model) Update(msg tea.Msg) (retModel tea.Model, retCmd tea.Cmd) {
 				}
 			case "enter":
 				if m.editFocus == editFocusCancel {
-					m.activeModal = ModalNone
-					m.editError = ""
-					m.editInput.Blur()
+					m.closeEdit()
 					return m, nil
 				}
 				// editFocusInput or editFocusSave → save
-				if row == nil || col == nil {
-					m.editError = "No selection"
-					return m, nil
-				}
-				// Generic save via EditAction config (preferred)
-				if def := m.findTableDef(m.editTableKey); def != nil && def.EditAction != nil {
-					act := *def.EditAction
-					varName := m.variableNameForRow(m.editTableKey, row)
-					parsedValue, err := parseInputValue(m.editInput.Value(), inputType)
-					if err != nil {
-						m.editError = err.Error()
-						return m, nil
-					}
-					valueStr := fmt.Sprintf("%v", parsedValue)
-					rowIndex := m.editRowIndex
-					colIndex := col.index
-					displayValue := m.editInput.Value()
-					idCol := act.IDColumn
-					if idCol == "" {
-						idCol = "id"
-					}
-					nameCol := act.NameColumn
-					if nameCol == "" {
-						nameCol = "name"
-					}
-					id := m.resolveRowValue(row, idCol)
-					name := m.resolveRowValue(row, nameCol)
-					m.activeModal = ModalNone
-					m.editError = ""
-					m.editInput.Blur()
-					return m, tea.Batch(m.executeEditActionCmd(act, id, name, m.selectedInstanceID, valueStr, typeName, rowIndex, colIndex, displayValue, varName), flashOnCmd())
-				}
-				// Fallback: legacy variable table save
-				if isVariableTable(m.editTableKey) {
-					varName := m.variableNameForRow(m.editTableKey, row)
-					if varName == "" {
-						m.editError = "Variable name not found"
-						return m, nil
-					}
-					parsedValue, err := parseInputValue(m.editInput.Value(), inputType)
-					if err != nil {
-						m.editError = err.Error()
-						return m, nil
-					}
-					rowIndex := m.editRowIndex
-					colIndex := col.index
-					displayValue := m.editInput.Value()
-					m.activeModal = ModalNone
-					m.editError = ""
-					m.editInput.Blur()
-					return m, tea.Batch(m.setVariableCmd(m.selectedInstanceID, varName, parsedValue, typeName, rowIndex, colIndex, displayValue, varName), flashOnCmd())
-				}
-				m.editError = "Editing not supported for this table"
-				return m, nil
+				return m, m.saveEdit(m.currentEditValue(inputType))
+			case "ctrl+o":
+				return m, m.openExternalEditor()
 			default:
 				var cmd tea.Cmd
 				m.editInput, cmd = m.editInput.Update(msg)
@@ -677,8 +603,44 @@ func (m model) Update(msg tea.Msg) (retModel tea.Model, retCmd tea.Cmd) {
 
 		if m.activeModal == ModalConfirmDelete {
 			confirmAction := func() (tea.Model, tea.Cmd) {
+				if !m.confirmationTyped() {
+					m.footerError, m.footerStatusKind, _ = setFooterStatus(footerStatusError,
+						fmt.Sprintf("Type %s to confirm", m.confirmationTarget()), 0)
+					return m, nil
+				}
 				m.activeModal = ModalNone
+				m.modify.confirming = false
 				m.confirmFocusedBtn = 1 // reset to cancel for next time
+				m.confirmTyped = ""
+				if run := m.pendingMutation; run != nil {
+					m.activeModal = m.pendingMutationReturn
+					m.pendingMutation = nil
+					m.pendingMutationLabel = ""
+					m.pendingMutationID = ""
+					m.footerError, m.footerStatusKind = "", footerStatusNone
+					return m, run(&m)
+				}
+				if m.migration.confirming {
+					cmd := m.executeMigrationCmd()
+					m.footerError, m.footerStatusKind, _ = setFooterStatus(footerStatusLoading, "Migrating process instances…", 0)
+					m.migration = migrationState{}
+					return m, tea.Batch(cmd, flashOnCmd())
+				}
+				if m.pendingAction != nil && m.pendingAction.Type == "batch" {
+					act := *m.pendingAction
+					params := m.pendingBatchParams
+					m.pendingAction = nil
+					m.pendingBatchParams = nil
+					m.footerError, m.footerStatusKind, _ = setFooterStatus(footerStatusLoading, fmt.Sprintf("%s: submitting batch…", act.Label), 0)
+					return m, tea.Batch(m.submitBatchCmd(act, params), flashOnCmd())
+				}
+				if m.pendingAction != nil && len(m.pendingBulkIDs) > 0 {
+					act := *m.pendingAction
+					ids := m.pendingBulkIDs
+					m.pendingAction = nil
+					m.pendingBulkIDs = nil
+					return m, m.startBulkAction(act, ids)
+				}
 				if m.pendingAction != nil {
 					act := *m.pendingAction
 					resolvedPath := m.pendingActionPath
@@ -700,10 +662,48 @@ func (m model) Update(msg tea.Msg) (retModel tea.Model, retCmd tea.Cmd) {
 				m.pendingAction = nil
 				m.pendingActionID = ""
 				m.pendingActionPath = ""
+				m.pendingBulkIDs = nil
+				m.pendingBatchParams = nil
+				m.confirmTyped = ""
+				if m.pendingMutation != nil {
+					// Back to where the change was started, e.g. the edit modal
+					m.activeModal = m.pendingMutationReturn
+					m.pendingMutation = nil
+					m.pendingMutationLabel = ""
+					m.pendingMutationID = ""
+				}
+				if m.modify.confirming {
+					// Back to the wizard with its instructions intact
+					m.modify.confirming = false
+					m.activeModal = ModalModifyInstance
+				}
+				if m.migration.confirming {
+					// Back to the planner with the edited plan intact
+					m.migration.confirming = false
+					m.activeModal = ModalMigration
+				}
 				m.footerError = "Cancelled"
 				return m, tea.Tick(2*time.Second, func(time.Time) tea.Msg { return clearErrorMsg{} })
 			}
 
+			if m.protected() {
+				// The typed confirmation takes the keyboard; Enter and Ctrl+D confirm
+				switch s {
+				case "ctrl+d", "enter":
+					return confirmAction()
+				case "esc":
+					return cancelAction()
+				case "backspace":
+					if r := []rune(m.confirmTyped); len(r) > 0 {
+						m.confirmTyped = string(r[:len(r)-1])
+					}
+				default:
+					if msg.Type == tea.KeyRunes {
+						m.confirmTyped += string(msg.Runes)
+					}
+				}
+				return m, nil
+			}
 			switch {
 			case s == "ctrl+d": // confirm key always confirms
 				return confirmAction()
@@ -724,6 +724,70 @@ func (m model) Update(msg tea.Msg) (retModel tea.Model, retCmd tea.Cmd) {
 			return m, nil
 		}
 
+		if m.activeModal == ModalModifyInstance {
+			return m, m.handleModifyKey(msg)
+		}
+
+		if m.activeModal == ModalMigration {
+			return m, m.handleMigrationKey(msg)
+		}
+
+		if m.activeModal == ModalDeploy {
+			return m, m.handleDeployKey(msg)
+		}
+
+		if m.activeModal == ModalDiagram {
+			return m, m.handleDiagramKey(msg)
+		}
+
+		if m.activeModal == ModalTimeline {
+			return m, m.handleTimelineKey(msg)
+		}
+
+		if m.activeModal == ModalAudit {
+			return m, m.handleAuditKey(msg)
+		}
+
+		if m.activeModal == ModalSaveView {
+			return m, m.handleSaveViewKey(msg)
+		}
+
+		if m.activeModal == ModalAlerts {
+			return m, m.handleAlertsKey(msg)
+		}
+
+		if m.activeModal == ModalDashboard {
+			return m, m.handleDashboardKey(msg)
+		}
+
+		if m.activeModal == ModalMetrics {
+			return m, m.handleMetricsKey(msg)
+		}
+
+		if m.activeModal == ModalStacktrace {
+			return m, m.handleStacktraceKey(msg)
+		}
+
+		if m.activeModal == ModalTriage {
+			return m, m.handleTriageKey(msg)
+		}
+
+		if m.activeModal == ModalBulkResult {
+			switch s {
+			case "esc", "q", "enter":
+				m.activeModal = ModalNone
+			case "down", "j":
+				if m.bulkResultScroll < len(m.bulkResults)-1 {
+					m.bulkResultScroll++
+				}
+			case "up", "k":
+				if m.bulkResultScroll > 0 {
+					m.bulkResultScroll--
+				}
+			}
+			return m, nil
+		}
+
 		if m.activeModal == ModalConfirmQuit {
 			switch {
 			case s == "ctrl+c": // confirm key always quits
@@ -835,6 +899,7 @@ func (m model) Update(msg tea.Msg) (retModel tea.Model, retCmd tea.Cmd) {
 				m.popup.input = ""
 				m.popup.cursor = -1
 				m.footerError = ""
+				m.cmdHistoryPos = len(m.cmdHistory)
 			}
 			return m, nil
 		}
@@ -942,17 +1007,7 @@ func (m model) Update(msg tea.Msg) (retModel tea.Model, retCmd tea.Cmd) {
 			return m, nil
 		case "ctrl+shift+r", "r":
 			// Toggle auto-refresh (Ctrl+Shift+R per story 3.7, or just R as shortcut)
-			m.autoRefresh = !m.autoRefresh
-			if m.autoRefresh {
-				m.isLoading = true
-				m.apiCallStarted = time.Now()
-				initialCmd := m.fetchForRoot(m.currentRoot)
-				if initialCmd == nil {
-					initialCmd = m.fetchDefinitionsCmd()
-				}
-				return m, tea.Batch(initialCmd, flashOnCmd(), tea.Tick(refreshInterval, func(time.Time) tea.Msg { return refreshMsg{} }), spinnerTickCmd())
-			}
-			return m, nil
+			return m, m.toggleAutoRefresh()
 		case "L":
 			// Toggle latency display in footer
 			m.showLatency = !m.showLatency
@@ -1011,11 +1066,54 @@ func (m model) Update(msg tea.Msg) (retModel tea.Model, retCmd tea.Cmd) {
 				return m, nil
 			}
 			return m, nil
+		case "H":
+			// Open the audit log of changes made through o6n
+			if m.popup.mode != popupModeNone {
+				m.popup.input += s
+				return m, nil
+			}
+			if m.activeModal == ModalNone {
+				return m, m.openAudit()
+			}
+			return m, nil
+		case "!":
+			// Open the alerts fired by the alert rules
+			if m.popup.mode != popupModeNone {
+				m.popup.input += s
+				return m, nil
+			}
+			if m.activeModal == ModalNone {
+				m.openAlerts()
+			}
+			return m, nil
+		case "F":
+			// Filter the current view by API query parameters
+			if m.popup.mode != popupModeNone {
+				m.popup.input += s
+				return m, nil
+			}
+			if m.activeModal == ModalNone {
+				m.openQueryBar()
+			}
+			return m, nil
+		case "B":
+			// Save the current view (table, filters, search, sort) under a name
+			if m.popup.mode != popupModeNone {
+				m.popup.input += s
+				return m, nil
+			}
+			if m.activeModal == ModalNone && m.currentRoot != "" {
+				m.openSaveView()
+			}
+			return m, nil
 		case "e":
 			if m.popup.mode != popupModeNone {
 				m.popup.input += s
 				return m, nil
 			}
+			if cmd, blocked := m.blockReadOnly("editing"); blocked {
+				return m, cmd
+			}
 			tableKey := m.currentTableKey()
 			if errMsg := m.startEdit(tableKey); errMsg != "" {
 				msg2, kind, cmd := setFooterStatus(footerStatusError, errMsg, 5*time.Second)
@@ -1052,9 +1150,11 @@ func (m model) Update(msg tea.Msg) (retModel tea.Model, retCmd tea.Cmd) {
 					return m, cmd
 				}
 				// assignee is empty — claim it
-				m.isLoading = true
-				m.apiCallStarted = time.Now()
-				return m, tea.Batch(m.claimTaskCmd(taskID, currentUser, taskName), spinnerTickCmd())
+				return m, m.guardMutation("Claim task "+taskName, taskID, ModalNone, func(m *model) tea.Cmd {
+					m.isLoading = true
+					m.apiCallStarted = time.Now()
+					return tea.Batch(m.claimTaskCmd(taskID, currentUser, taskName), spinnerTickCmd())
+				})
 			}
 			return m, nil
 		case "u":
@@ -1085,9 +1185,11 @@ func (m model) Update(msg tea.Msg) (retModel tea.Model, retCmd tea.Cmd) {
 					return m, cmd
 				}
 				// assignee == currentUser — unclaim
-				m.isLoading = true
-				m.apiCallStarted = time.Now()
-				return m, tea.Batch(m.unclaimTaskCmd(taskID, taskName), spinnerTickCmd())
+				return m, m.guardMutation("Unclaim task "+taskName, taskID, ModalNone, func(m *model) tea.Cmd {
+					m.isLoading = true
+					m.apiCallStarted = time.Now()
+					return tea.Batch(m.unclaimTaskCmd(taskID, taskName), spinnerTickCmd())
+				})
 			}
 			return m, nil
 		case "o":
@@ -1101,6 +1203,9 @@ func (m model) Update(msg tea.Msg) (retModel tea.Model, retCmd tea.Cmd) {
 				if len(row) == 0 {
 					return m, nil
 				}
+				if cmd, blocked := m.blockReadOnly("task completion"); blocked {
+					return m, cmd
+				}
 				taskID := m.resolveRowValue(row, "id")
 				taskName := m.resolveRowValue(row, "name")
 				// Open task completion modal and fetch variables
@@ -1148,6 +1253,11 @@ func (m model) Update(msg tea.Msg) (retModel tea.Model, retCmd tea.Cmd) {
 				m.popup.offset = 0
 				return m, nil
 			}
+			// Clear marks before leaving the view
+			if len(m.markedRows) > 0 {
+				m.markedRows = nil
+				return m, nil
+			}
 			// Pop from navigation stack and restore previous view state
 			if len(m.navigationStack) > 0 {
 				// TransitionPop pops the top viewState and restores all fields:
@@ -1344,6 +1454,9 @@ func (m model) Update(msg tea.Msg) (retModel tea.Model, retCmd tea.Cmd) {
 		case "ctrl+d":
 			// Half-page down (vim mode only) OR delete (always available for instance views)
 			if m.viewMode == "process-instance" {
+				if cmd, blocked := m.blockReadOnly("deleting"); blocked {
+					return m, cmd
+				}
 				row := m.table.SelectedRow()
 				if len(row) > 0 {
 					m.pendingDeleteID = stripFocusIndicatorPrefix(fmt.Sprintf("%v", row[0]))
@@ -1538,6 +1651,16 @@ func (m model) Update(msg tea.Msg) (retModel tea.Model, retCmd tea.Cmd) {
 				}
 				return m, nil
 			}
+			// Mark mode: Space toggles the selected row, A toggles all visible rows
+			if s == " " && m.activeModal == ModalNone {
+				m.toggleMark()
+				m.table.MoveDown(1)
+				return m, nil
+			}
+			if s == "A" && m.activeModal == ModalNone {
+				m.toggleMarkAll()
+				return m, nil
+			}
 			// otherwise don't intercept the key: let the list/table components handle navigation
 			// fall through to component updates below
 		}
@@ -1577,13 +1700,34 @@ func (m model) Update(msg tea.Msg) (retModel tea.Model, retCmd tea.Cmd) {
 		m.applyStyle()
 		return m, nil
 	case refreshMsg:
-		if m.autoRefresh {
+		if m.autoRefresh && msg.gen == m.refreshGen {
+			// keep polling, but don't reload under an open modal, popup or filter
+			if m.refreshPaused() {
+				return m, m.refreshTickCmd()
+			}
 			cmd := m.fetchForRoot(m.currentRoot)
 			if cmd == nil {
 				cmd = m.fetchDefinitionsCmd()
 			}
-			return m, tea.Batch(cmd, flashOnCmd(), tea.Tick(refreshInterval, func(time.Time) tea.Msg { return refreshMsg{} }), spinnerTickCmd())
+			m.refreshFetch = true
+			return m, tea.Batch(cmd, flashOnCmd(), m.refreshTickCmd(), spinnerTickCmd())
 		}
+	case rowDiffExpiredMsg:
+		m.expireRowDiff(msg.until)
+	case alertTickMsg:
+		return m, m.pollAlertsCmd()
+	case alertCountMsg:
+		return m, m.applyAlertCount(msg)
+	case dashboardLoadedMsg:
+		m.applyDashboard(msg)
+	case openDashboardMsg:
+		return m, m.openDashboard()
+	case metricsLoadedMsg:
+		m.applyMetrics(msg)
+	case stacktraceLoadedMsg:
+		m.applyStacktrace(msg)
+	case triageLoadedMsg:
+		m.applyTriage(msg)
 	case healthTickMsg:
 		return m, tea.Batch(
 			m.checkEnvironmentHealthCmd(m.currentEnv),
@@ -1669,98 +1813,8 @@ func (m model) Update(msg tea.Msg) (retModel tea.Model, retCmd tea.Cmd) {
 			}
 		}
 
-		def := m.findTableDef(msg.root)
-		var cols []table.Column
-		if def != nil {
-			cols = m.buildColumnsFor(msg.root, m.paneWidth-4)
-			// If buildColumnsFor returned only the "EMPTY" fallback (when all columns are invisible),
-			// infer columns from the data instead
-			if len(cols) == 1 && cols[0].Title == "EMPTY" {
-				cols = nil
-			}
-		}
-		if len(cols) == 0 {
-			// infer columns from first item keys (after stripping _meta_count)
-			if len(msg.items) > 0 {
-				keys := make([]string, 0, len(msg.items[0]))
-				for k := range msg.items[0] {
-					keys = append(keys, k)
-				}
-				sort.Strings(keys)
-				for _, k := range keys {
-					cols = append(cols, table.Column{Title: strings.ToUpper(k), Width: 20})
-				}
-			}
-		}
-		// Build rows from items; also capture raw data for drilldown column lookup
-		rows := make([]table.Row, 0, len(msg.items))
-		rd := make([]map[string]interface{}, 0, len(msg.items))
-		hasDrilldown := def != nil && def.Drilldown != nil
-		for _, it := range msg.items {
-			rd = append(rd, it)
-			if len(cols) == 0 {
-				// fallback: add single column with JSON representation
-				rows = append(rows, table.Row{fmt.Sprintf("%v", it)})
-				continue
-			}
-			r := make(table.Row, len(cols))
-			for i, col := range cols {
-				// prefer original column name from TableDef when available
-				key := strings.ToLower(col.Title)
-				val := ""
-				var v interface{}
-				var found bool
-				if vv, ok := it[key]; ok {
-					v = vv
-					found = true
-				} else if vv, ok := it[strings.ToLower(col.Title)]; ok {
-					v = vv
-					found = true
-				} else if vv, ok := it[col.Title]; ok {
-					v = vv
-					found = true
-				}
-				if found {
-					if v == nil {
-						val = ""
-					} else if s, ok := v.(string); ok {
-						val = s
-					} else {
-						val = fmt.Sprintf("%v", v)
-					}
-				} else {
-					val = ""
-				}
-				r[i] = val
-			}
-			if hasDrilldown && len(r) > 0 {
-				r[0] = "▶ " + r[0]
-			}
-			rows = append(rows, r)
-		}
-		m.rowData = rd
-
-		if len(cols) > 0 {
-			m.table.SetColumns(cols)
-		}
-		normalized := normalizeRows(rows, len(cols))
-		colorized := colorizeRows(msg.root, normalized, cols, RowStyles{
-			Running:   m.styles.RowRunning,
-			Suspended: m.styles.RowSuspended,
-			Failed:    m.styles.RowFailed,
-			Ended:     m.styles.RowEnded,
-		})
-		m.setTableRowsSorted(colorized)
-		if m.sortColumn >= 0 {
-			m.applySortIndicatorToColumns()
-		}
-		// Track which resource is currently displayed.
-		m.viewMode = msg.root
-		// re-apply locked search filter if active
-		if !m.searchMode && m.searchTerm != "" {
-			filtered := filterRows(m.table.Rows(), m.searchTerm)
-			m.table.SetRows(filtered)
-		}
+		diffCmd := m.trackRefreshChanges(msg.root, msg.items)
+		m.applyGenericItems(msg.root, msg.items)
 		// restore pending cursor after page operations for generic loads
 		if m.pendingCursorAfterPage >= 0 {
 			r := m.table.Rows()
@@ -1780,6 +1834,9 @@ func (m model) Update(msg tea.Msg) (retModel tea.Model, retCmd tea.Cmd) {
 			m.apiCallStarted = time.Time{}
 		}
 		m.isLoading = false
+		if diffCmd != nil {
+			return m, diffCmd
+		}
 	case editSavedMsg:
 		rows := m.table.Rows()
 		if msg.rowIndex >= 0 && msg.rowIndex < len(rows) {
@@ -1870,6 +1927,71 @@ func (m model) Update(msg tea.Msg) (retModel tea.Model, retCmd tea.Cmd) {
 			fetchCmd = m.fetchDefinitionsCmd()
 		}
 		return m, tea.Batch(statusCmd, fetchCmd, spinnerTickCmd())
+	case bulkProgressMsg:
+		m.bulkResults = append(m.bulkResults, msg.result)
+		m.footerError, m.footerStatusKind, _ = setFooterStatus(footerStatusLoading, m.bulkProgressText(), 0)
+		return m, waitForBulkResultCmd(msg.ch)
+	case bulkDoneMsg:
+		return m, m.finishBulkAction()
+	case activityTreeLoadedMsg:
+		if m.activeModal == ModalModifyInstance && msg.instanceID == m.modify.instanceID {
+			m.modify.loading = false
+			m.modify.nodes = msg.nodes
+			m.modify.cursor = 0
+			if msg.err != nil {
+				m.modify.err = friendlyError(m.currentEnv, msg.err)
+			}
+		}
+		return m, nil
+	case migrationVersionsMsg:
+		if m.activeModal == ModalMigration && msg.sourceID == m.migration.sourceID {
+			m.migration.loading = false
+			m.migration.versions = msg.versions
+			m.migration.cursor = 0
+			// Preselect the newest version other than the source
+			for i, v := range msg.versions {
+				if v.id != msg.sourceID {
+					m.migration.cursor = i
+					break
+				}
+			}
+			if msg.err != nil {
+				m.migration.err = friendlyError(m.currentEnv, msg.err)
+			}
+		}
+		return m, nil
+	case migrationPlanMsg:
+		if m.activeModal == ModalMigration && m.migration.phase == migrationEditPlan {
+			m.migration.loading = false
+			if msg.err != nil {
+				m.migration.err = friendlyError(m.currentEnv, msg.err)
+				return m, nil
+			}
+			m.migration.instructions = msg.instructions
+			m.migration.failures = msg.failures
+			m.migration.validated = true
+			if m.migration.cursor >= len(msg.instructions) {
+				m.migration.cursor = 0
+			}
+		}
+		return m, nil
+	case timelineLoadedMsg:
+		m.applyTimeline(msg)
+		return m, nil
+	case auditLoadedMsg:
+		m.applyAudit(msg)
+		return m, nil
+	case diagramLoadedMsg:
+		m.applyDiagram(msg)
+		return m, nil
+	case deployDoneMsg:
+		return m, m.finishDeploy(msg)
+	case editorFinishedMsg:
+		return m, m.finishExternalEdit(msg)
+	case batchSubmittedMsg:
+		return m, tea.Batch(m.startBatchTracking(msg.label, msg.batchID), m.saveStateCmd())
+	case batchProgressMsg:
+		return m, m.updateBatchProgress(msg)
 	case envStatusMsg:
 		// Update environment status
 		m.envStatus[msg.env] = msg.status
@@ -2171,9 +2293,12 @@ func (m *model) submitTaskComplete() tea.Cmd {
 		}
 		vars[f.name] = v
 	}
-	m.isLoading = true
-	m.apiCallStarted = time.Now()
-	return tea.Batch(m.completeTaskCmd(m.taskCompleteTaskID, m.taskCompleteTaskName, vars), spinnerTickCmd())
+	taskID, taskName := m.taskCompleteTaskID, m.taskCompleteTaskName
+	return m.guardMutation("Complete task "+taskName, taskID, ModalTaskComplete, func(m *model) tea.Cmd {
+		m.isLoading = true
+		m.apiCallStarted = time.Now()
+		return tea.Batch(m.completeTaskCmd(taskID, taskName, vars), spinnerTickCmd())
+	})
 }
 
 // mapAPITypeToVarType maps an Operaton API type name to the internal validation type string.
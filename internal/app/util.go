@@ -1,11 +1,15 @@
 package app
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"net/http"
 	"os"
 	"strings"
 
 	"github.com/charmbracelet/bubbles/table"
+	"github.com/kthoms/o6n/internal/client"
 )
 
 // truncateString truncates s to at most n visible characters (runes), safe for Unicode.
@@ -17,8 +21,34 @@ func truncateString(s string, n int) string {
 	return string(runes[:n])
 }
 
-// friendlyError translates raw Go network errors into user-friendly messages.
+// friendlyError translates raw Go network errors and engine error responses
+// into user-friendly messages.
 func friendlyError(env string, err error) string {
+	if errors.Is(err, context.Canceled) {
+		return "Request cancelled"
+	}
+	if errors.Is(err, client.ErrReadOnly) {
+		return fmt.Sprintf("%s is read-only — changes are disabled", env)
+	}
+	if apiErr, ok := client.AsAPIError(err); ok {
+		detail := apiErr.Message
+		if detail == "" {
+			detail = http.StatusText(apiErr.StatusCode)
+		}
+		switch apiErr.StatusCode {
+		case http.StatusUnauthorized:
+			return fmt.Sprintf("Authentication failed for %s — check the credentials", env)
+		case http.StatusForbidden:
+			return fmt.Sprintf("Not permitted on %s — %s", env, detail)
+		case http.StatusNotFound:
+			return fmt.Sprintf("Not found — %s", detail)
+		case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+			return fmt.Sprintf("%s is overloaded or unavailable (HTTP %d) — try again shortly", env, apiErr.StatusCode)
+		}
+		if apiErr.Message != "" {
+			return fmt.Sprintf("%s (HTTP %d)", apiErr.Message, apiErr.StatusCode)
+		}
+	}
 	msg := err.Error()
 	switch {
 	case strings.Contains(msg, "connection refused"):
@@ -29,6 +59,8 @@ func friendlyError(env string, err error) string {
 		return fmt.Sprintf("TLS/certificate error — check HTTPS config for %s", env)
 	case strings.Contains(msg, "no such host"):
 		return fmt.Sprintf("Unknown host for %s — check the base URL in config", env)
+	case strings.Contains(msg, "auth: "):
+		return fmt.Sprintf("Authentication failed for %s — %s", env, msg[strings.Index(msg, "auth: ")+len("auth: "):])
 	default:
 		return msg
 	}
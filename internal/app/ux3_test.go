@@ -1,11 +1,14 @@
 package app
 
 import (
+	"context"
 	"errors"
+	"fmt"
 	"strings"
 	"testing"
 
 	"github.com/charmbracelet/bubbles/table"
+	"github.com/kthoms/o6n/internal/client"
 	"github.com/kthoms/o6n/internal/config"
 )
 
@@ -210,6 +213,22 @@ func TestFriendlyErrorUnknownPassthrough(t *testing.T) {
 	}
 }
 
+func TestFriendlyErrorEngineResponses(t *testing.T) {
+	cases := map[error]string{
+		&client.APIError{StatusCode: 401}:                                                     "Authentication failed for local",
+		&client.APIError{StatusCode: 404, Message: "Process instance p1 not found"}:           "Not found — Process instance p1 not found",
+		&client.APIError{StatusCode: 503}:                                                     "local is overloaded or unavailable (HTTP 503)",
+		&client.APIError{StatusCode: 500, Message: "Cannot correlate message 'order'"}:        "Cannot correlate message 'order' (HTTP 500)",
+		fmt.Errorf("action failed: %w", &client.APIError{StatusCode: 403, Message: "denied"}): "Not permitted on local — denied",
+		context.Canceled: "Request cancelled",
+	}
+	for err, want := range cases {
+		if out := friendlyError("local", err); !strings.Contains(out, want) {
+			t.Errorf("friendlyError(%v) = %q, want %q", err, out, want)
+		}
+	}
+}
+
 // ── T8: healthTickMsg dispatches health check command ────────────────────────
 
 func TestHealthTickMsgReturnsNonNilCmd(t *testing.T) {
@@ -53,10 +53,16 @@ func (m *model) renderCompactHeader(width int) string {
 	}
 	envNameStyle := lipgloss.NewStyle().Foreground(envNameColor)
 	envInfo := fmt.Sprintf("%s %s", envNameStyle.Render(m.currentEnv), statusStyle.Render(statusSymbol))
+	if badge := m.renderModeBadge(); badge != "" {
+		envInfo += " " + badge
+	}
+	if m.alerts.unseen > 0 {
+		envInfo += " " + m.styles.RowFailed.Render(fmt.Sprintf("⚑ %d", m.alerts.unseen))
+	}
 
 	row1 := fmt.Sprintf("o6n %s │ %s", m.version, envInfo)
 	if m.autoRefresh {
-		badge := m.styles.Accent.Render("↺")
+		badge := m.styles.Accent.Render("↺ " + m.refreshIntervalFor(m.currentRoot).String())
 		row1 = row1 + " " + badge
 	}
 	if lipgloss.Width(row1) > width-4 {
@@ -98,11 +104,37 @@ func (m *model) renderCompactHeader(width int) string {
 	return headerStyle.Render(header)
 }
 
-// renderConfirmDeleteModal renders a modal for confirming delete action
+// renderConfirmDeleteModal renders a modal for confirming delete action, or any
+// other confirmed change; protected environments add the typed confirmation.
 func (m *model) renderConfirmDeleteModal(_, _ int) string {
+	content := m.renderConfirmContent()
+	if content == "" {
+		return ""
+	}
+	return m.renderProtectedPrompt() + content
+}
+
+// renderConfirmContent renders the body and buttons of the pending confirmation.
+func (m *model) renderConfirmContent() string {
+	if m.pendingMutation != nil {
+		return m.renderPendingMutationBody() + "\n" + m.renderRunButtons()
+	}
+	if m.migration.confirming {
+		return m.renderMigrationConfirmBody() + "\n" + m.renderRunButtons()
+	}
 	if m.pendingDeleteID == "" && m.pendingAction == nil {
 		return ""
 	}
+	if m.pendingAction != nil && (len(m.pendingBulkIDs) > 0 || m.pendingAction.Type == "batch") {
+		body := m.renderBulkConfirmBody()
+		if m.pendingAction.Type == "batch" {
+			body = m.renderBatchConfirmBody()
+		}
+		return body + "\n" + m.renderRunButtons()
+	}
+	if m.modify.confirming && m.pendingAction != nil {
+		return m.renderModificationConfirmBody() + "\n" + m.renderRunButtons()
+	}
 	resourceLabel := strings.ToUpper(m.currentRoot)
 
 	nameDetail := ""
@@ -132,6 +164,18 @@ func (m *model) renderConfirmDeleteModal(_, _ int) string {
 	return modalContent
 }
 
+// renderRunButtons renders the Run/Cancel buttons and key hint of a confirmation.
+func (m *model) renderRunButtons() string {
+	confirmBtn := m.styles.BtnSave.Render(" Run ")
+	cancelBtn := m.styles.BtnCancelFocused.Render(" Cancel ")
+	if m.confirmFocusedBtn == 0 {
+		confirmBtn = m.styles.BtnSaveFocused.Render(" Run ")
+		cancelBtn = m.styles.BtnCancel.Render(" Cancel ")
+	}
+	hint := m.styles.FgMuted.Render("Tab: switch  Enter: activate  Ctrl+d: run  Esc: cancel")
+	return confirmBtn + "  " + cancelBtn + "\n" + hint
+}
+
 // renderConfirmQuitModal renders a modal asking the user to confirm quitting.
 // Returns just the styled box; View() wraps it with overlayCenter.
 func (m *model) renderConfirmQuitModal(_, _ int) string {
@@ -167,7 +211,10 @@ SEARCH                   │  CONTEXT
 Esc      Clear filter    │  Enter  Confirm
 Enter    Lock filter     │  Esc    Cancel
 Ctrl+a   Search all pgs  │  s      Sort
-                         │  J      JSON view
+F        Query params    │  J      JSON view
+                         │  H      Audit log
+                         │  B      Save view
+                         │  !      Alerts
 
 STATUS INDICATORS
 ────────────────────────────────────────────
@@ -219,10 +266,10 @@ Ctrl+d   Half-page dn`
 			strings.Join(navLines, "\n")
 	}
 
-	enterLine := "Enter    Drill down      │  Ctrl+a  Search all pg  │"
+	enterLine := "Enter    Drill down      │  Ctrl+a  Search all pg  │  !      Alerts"
 	arrowLine := "→        Drill down      │                         │"
 	if def == nil || def.Drilldown == nil {
-		enterLine = "                          │  Ctrl+a  Search all pg  │"
+		enterLine = "                          │  Ctrl+a  Search all pg  │  !      Alerts"
 		arrowLine = "                          │                         │"
 	}
 
@@ -247,8 +294,10 @@ Esc      Go back         │  SEARCH                 │  CONTEXT
                           │  /       Search/filter  │  Tab    Complete
                           │  Esc     Clear filter   │  Enter  Confirm
                           │  Enter   Lock filter    │  Esc    Cancel
-                          │                         │  s      Sort
-                          │                         │  J      JSON view`, enterLine, arrowLine, breadcrumbLine) + vimSection + resourceActionsSection + viewsSection + `
+                          │  F       Query params   │  s      Sort
+                          │                         │  J      JSON view
+                          │                         │  H      Audit log
+                          │                         │  B      Save view`, enterLine, arrowLine, breadcrumbLine) + vimSection + resourceActionsSection + viewsSection + `
 
 STATUS INDICATORS
 ────────────────────────────────────────────
@@ -351,10 +400,10 @@ Ctrl+d   Half-page dn`
 			strings.Join(navLines, "\n")
 	}
 
-	enterLine := "Enter    Drill down      │  Ctrl+a  Search all pg  │"
+	enterLine := "Enter    Drill down      │  Ctrl+a  Search all pg  │  !      Alerts"
 	arrowLine := "→        Drill down      │                         │"
 	if def == nil || def.Drilldown == nil {
-		enterLine = "                          │  Ctrl+a  Search all pg  │"
+		enterLine = "                          │  Ctrl+a  Search all pg  │  !      Alerts"
 		arrowLine = "                          │                         │"
 	}
 
@@ -379,8 +428,10 @@ Esc      Go back         │  SEARCH                 │  CONTEXT
                           │  /       Search/filter  │  Tab    Complete
                           │  Esc     Clear filter   │  Enter  Confirm
                           │  Enter   Lock filter    │  Esc    Cancel
-                          │                         │  s      Sort
-                          │                         │  J      JSON view`, enterLine, arrowLine, breadcrumbLine) +
+                          │  F       Query params   │  s      Sort
+                          │                         │  J      JSON view
+                          │                         │  H      Audit log
+                          │                         │  B      Save view`, enterLine, arrowLine, breadcrumbLine) +
 		vimSection + resourceActionsSection + viewsSection + `
 
 STATUS INDICATORS
@@ -536,7 +587,11 @@ func (m *model) renderEditModal(_, _ int) string {
 			suggestionLine = "Suggestions: " + strings.Join(sugg, ", ") + "\n\n"
 		}
 	}
-	body = singleColumnHeader + columnsLine + m.editInput.View() + "\n\n" + errorLine + suggestionLine
+	externalLine := ""
+	if strings.Contains(m.editExternal, "\n") {
+		externalLine = m.styles.FgMuted.Render(fmt.Sprintf("%d lines from $EDITOR — Ctrl+O to reopen", strings.Count(m.editExternal, "\n")+1)) + "\n\n"
+	}
+	body = singleColumnHeader + columnsLine + m.editInput.View() + "\n\n" + externalLine + errorLine + suggestionLine
 
 	// Render buttons with focus styles
 	savedFocusedStyle := m.styles.BtnSaveFocused
@@ -759,6 +814,13 @@ func (m model) View() string {
 		}
 	}
 
+	if n := len(m.markedIndexes()); n > 0 {
+		baseTitle = fmt.Sprintf("%s [%d marked]", baseTitle, n)
+	}
+	if chips := renderQueryChips(sortedQueryFilters(m.queryFilters)); chips != "" {
+		baseTitle += " " + chips
+	}
+
 	title := baseTitle
 
 	// Render search bar when in search mode
@@ -776,7 +838,7 @@ func (m model) View() string {
 	}
 
 	// Build table content — show empty state message when no rows
-	tableContent := m.table.View()
+	tableContent := m.renderTableWithMarks()
 	if len(m.table.Rows()) == 0 && !m.isLoading {
 		displayName := strings.ReplaceAll(m.currentRoot, "-", " ")
 		emptyMsg := "No " + displayName + " found"
@@ -957,6 +1019,9 @@ func (m model) View() string {
 
 	// Compose final vertical layout: header, context box (always 1 row), filter/search bars, main content, footer (1 row)
 	layoutParts := []string{headerStack, contextSelectionBox}
+	if queryBar := m.renderQueryBar(); queryBar != "" {
+		layoutParts = append(layoutParts, queryBar)
+	}
 	if filterBar != "" {
 		layoutParts = append(layoutParts, filterBar)
 	}
@@ -1231,6 +1296,9 @@ func (m *model) renderEnvPopup(_, _ int) string {
 		url := ""
 		if env, ok := m.config.Environments[name]; ok {
 			url = env.URL
+			if env.ReadOnly() || env.Protected() {
+				url += "  [" + env.Mode + "]"
+			}
 		}
 
 		activeMarker := ""
@@ -1616,6 +1684,9 @@ func (m model) renderContextSwitcherBody() string {
 
 		for i := offset; i < end; i++ {
 			item := items[i]
+			if aliases := m.tableAliases(item); len(aliases) > 0 && !strings.Contains(item, " ") {
+				item += " (" + strings.Join(aliases, ", ") + ")"
+			}
 			if i == m.popup.cursor {
 				b.WriteString(m.styles.PopupCursor.Render("► " + item))
 			} else {
@@ -1624,6 +1695,13 @@ func (m model) renderContextSwitcherBody() string {
 			b.WriteString("\n")
 		}
 	}
+	if m.commandErr != "" {
+		b.WriteString("\n" + m.styles.ValidationError.Render(m.commandErr) + "\n")
+	}
+	b.WriteString("\n" + m.styles.FgMuted.Render("table [value|name=value…] · env/skin/view <name> · q · Ctrl+P/N history"))
+	if len(m.savedViews) > 0 {
+		b.WriteString("\n" + m.styles.FgMuted.Render(viewPrefix+"name: saved view (B saves, Ctrl+D deletes)"))
+	}
 
 	return b.String()
 }
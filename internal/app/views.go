@@ -0,0 +1,364 @@
+package app
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/charmbracelet/bubbles/table"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/kthoms/o6n/internal/config"
+	"gopkg.in/yaml.v3"
+)
+
+// viewPrefix marks saved views among the entries of the context switcher.
+const viewPrefix = "@"
+
+// saveViewState holds the save view dialog (ModalSaveView).
+type saveViewState struct {
+	input textinput.Model
+	view  config.SavedView // the view being saved, named on Enter
+	err   string
+}
+
+// captureView returns the current view as a saved view called name.
+func (m *model) captureView(name string) config.SavedView {
+	v := config.SavedView{
+		Name:                  name,
+		Env:                   m.currentEnv,
+		Root:                  m.currentRoot,
+		Title:                 m.contentHeader,
+		Breadcrumb:            append([]string(nil), m.breadcrumb...),
+		SelectedDefinitionKey: m.selectedDefinitionKey,
+		SelectedInstanceID:    m.selectedInstanceID,
+		Search:                m.searchTerm,
+	}
+	if len(m.genericParams) > 0 {
+		v.GenericParams = copyStringMap(m.genericParams)
+	}
+	if len(m.queryFilters) > 0 {
+		v.QueryFilters = copyStringMap(m.queryFilters)
+	}
+	if cols := m.table.Columns(); m.sortColumn >= 0 && m.sortColumn < len(cols) {
+		v.Sort = stripSortIndicator(cols[m.sortColumn].Title)
+		v.SortDesc = !m.sortAscending
+	}
+	return v
+}
+
+func copyStringMap(in map[string]string) map[string]string {
+	out := make(map[string]string, len(in))
+	for k, v := range in {
+		out[k] = v
+	}
+	return out
+}
+
+func stripSortIndicator(title string) string {
+	return strings.TrimSuffix(strings.TrimSuffix(title, " ▲"), " ▼")
+}
+
+// restoreSavedView puts the model into the state of v without fetching. The
+// sort column is resolved by title once the view's columns are loaded.
+func (m *model) restoreSavedView(v config.SavedView) error {
+	if v.Env != "" && v.Env != m.currentEnv {
+		if _, ok := m.config.Environments[v.Env]; !ok {
+			return fmt.Errorf("view %s: unknown environment %q", v.Name, v.Env)
+		}
+		m.switchToEnvironment(v.Env)
+	}
+	m.prepareStateTransition(TransitionFull)
+	m.currentRoot = v.Root
+	m.viewMode = v.Root
+	m.contentHeader = v.Title
+	if m.contentHeader == "" {
+		m.contentHeader = v.Root
+	}
+	m.breadcrumb = append([]string(nil), v.Breadcrumb...)
+	if len(m.breadcrumb) == 0 {
+		m.breadcrumb = []string{v.Root}
+	}
+	m.selectedDefinitionKey = v.SelectedDefinitionKey
+	m.selectedInstanceID = v.SelectedInstanceID
+	m.genericParams = copyStringMap(v.GenericParams)
+	if len(v.QueryFilters) > 0 {
+		m.queryFilters = copyStringMap(v.QueryFilters)
+	}
+	m.searchTerm = v.Search
+	m.pendingSort = v.Sort
+	m.sortAscending = !v.SortDesc
+	if m.pageOffsets != nil {
+		m.pageOffsets[v.Root] = 0
+	}
+	return nil
+}
+
+// applyPendingSort sorts by the column titled m.pendingSort, if the loaded
+// columns have one.
+func (m *model) applyPendingSort(cols []table.Column) {
+	if m.pendingSort == "" {
+		return
+	}
+	for i, c := range cols {
+		if strings.EqualFold(stripSortIndicator(c.Title), m.pendingSort) {
+			m.sortColumn = i
+			break
+		}
+	}
+	m.pendingSort = ""
+}
+
+// openSavedView switches to v and loads it.
+func (m *model) openSavedView(v config.SavedView) tea.Cmd {
+	envBefore := m.currentEnv
+	if err := m.restoreSavedView(v); err != nil {
+		msg, kind, cmd := setFooterStatus(footerStatusError, err.Error(), 5*time.Second)
+		m.footerError, m.footerStatusKind = msg, kind
+		return cmd
+	}
+	cols := m.buildColumnsFor(v.Root, m.paneWidth-4)
+	if len(cols) > 0 {
+		m.table.SetRows([]table.Row{})
+		m.table.SetColumns(cols)
+		m.table.SetRows(normalizeRows(nil, len(cols)))
+	}
+	m.table.SetCursor(0)
+	m.isLoading = true
+	m.apiCallStarted = time.Now()
+	cmds := []tea.Cmd{m.fetchForRoot(v.Root), flashOnCmd(), spinnerTickCmd(), m.saveStateCmd()}
+	if m.currentEnv != envBefore {
+		cmds = append(cmds, m.checkEnvironmentHealthCmd(m.currentEnv))
+	}
+	return tea.Batch(cmds...)
+}
+
+// openSaveView opens the dialog that names the current view.
+func (m *model) openSaveView() {
+	input := textinput.New()
+	input.Prompt = "> "
+	input.Placeholder = "view name"
+	input.CharLimit = 64
+	input.Width = 40
+	input.Focus()
+	m.saveView = saveViewState{input: input, view: m.captureView("")}
+	m.activeModal = ModalSaveView
+}
+
+// validViewName reports why name cannot name a view, or nil.
+func validViewName(name string) error {
+	switch {
+	case name == "":
+		return errors.New("enter a name")
+	case strings.ContainsAny(name, " \t"+viewPrefix):
+		return fmt.Errorf("name must not contain spaces or %q", viewPrefix)
+	}
+	return nil
+}
+
+// handleSaveViewKey processes a key press while ModalSaveView is open.
+func (m *model) handleSaveViewKey(msg tea.KeyMsg) tea.Cmd {
+	switch msg.String() {
+	case "esc":
+		m.activeModal = ModalNone
+		m.saveView = saveViewState{}
+		return nil
+	case "enter":
+		name := strings.TrimSpace(m.saveView.input.Value())
+		if err := validViewName(name); err != nil {
+			m.saveView.err = err.Error()
+			return nil
+		}
+		v := m.saveView.view
+		v.Name = name
+		_, replaced := config.FindView(m.savedViews, name)
+		m.savedViews = config.PutView(m.savedViews, v)
+		m.activeModal = ModalNone
+		m.saveView = saveViewState{}
+		if m.viewsPath != "" {
+			if err := config.SaveViews(m.viewsPath, m.savedViews); err != nil {
+				msg, kind, cmd := setFooterStatus(footerStatusError, err.Error(), 5*time.Second)
+				m.footerError, m.footerStatusKind = msg, kind
+				return cmd
+			}
+		}
+		text := "✓ Saved view " + viewPrefix + name
+		if replaced {
+			text = "✓ Updated view " + viewPrefix + name
+		}
+		msg, kind, cmd := setFooterStatus(footerStatusSuccess, text, 3*time.Second)
+		m.footerError, m.footerStatusKind = msg, kind
+		return cmd
+	}
+	var cmd tea.Cmd
+	m.saveView.input, cmd = m.saveView.input.Update(msg)
+	m.saveView.err = ""
+	return cmd
+}
+
+// deleteSavedView removes the view called name and rewrites the views file.
+func (m *model) deleteSavedView(name string) tea.Cmd {
+	kept := m.savedViews[:0:0]
+	for _, v := range m.savedViews {
+		if v.Name != name {
+			kept = append(kept, v)
+		}
+	}
+	m.savedViews = kept
+	if m.viewsPath != "" {
+		if err := config.SaveViews(m.viewsPath, kept); err != nil {
+			msg, kind, cmd := setFooterStatus(footerStatusError, err.Error(), 5*time.Second)
+			m.footerError, m.footerStatusKind = msg, kind
+			return cmd
+		}
+	}
+	msg, kind, cmd := setFooterStatus(footerStatusInfo, "Deleted view "+viewPrefix+name, 3*time.Second)
+	m.footerError, m.footerStatusKind = msg, kind
+	return cmd
+}
+
+// viewFilterText renders the drill-down params and query filters of v as
+// name=value pairs.
+func viewFilterText(v config.SavedView) string {
+	var filters []string
+	for _, f := range append(sortedQueryFilters(v.GenericParams), sortedQueryFilters(v.QueryFilters)...) {
+		filters = append(filters, f.name+"="+f.value)
+	}
+	return strings.Join(filters, " ")
+}
+
+// describeView summarises what a saved view restores.
+func describeView(v config.SavedView) []string {
+	lines := []string{"Table:  " + v.Root}
+	if v.Env != "" {
+		lines = append(lines, "Env:    "+v.Env)
+	}
+	if filter := viewFilterText(v); filter != "" {
+		lines = append(lines, "Filter: "+filter)
+	}
+	if v.Search != "" {
+		lines = append(lines, "Search: /"+v.Search+"/")
+	}
+	if v.Sort != "" {
+		dir := "▲"
+		if v.SortDesc {
+			dir = "▼"
+		}
+		lines = append(lines, "Sort:   "+v.Sort+" "+dir)
+	}
+	return lines
+}
+
+// renderSaveViewBody renders the save view dialog.
+func (m model) renderSaveViewBody() string {
+	var b strings.Builder
+	b.WriteString("Save View\n\n")
+	for _, line := range describeView(m.saveView.view) {
+		b.WriteString(m.styles.FgMuted.Render(line) + "\n")
+	}
+	b.WriteString("\n" + m.saveView.input.View() + "\n")
+	if m.saveView.err != "" {
+		b.WriteString(m.styles.ValidationError.Render(m.saveView.err) + "\n")
+	}
+	b.WriteString("\n" + m.styles.PopupHint.Render("Enter save  Esc cancel  — recall with : "+viewPrefix+"name or --view name"))
+	return b.String()
+}
+
+// runViews implements `o6n views`: list the saved views, export them as YAML
+// for sharing, or import views shared by others.
+func runViews(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("views", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	file := fs.String("file", viewsPath, "views file")
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: o6n views [list] | export [name...] | import <file>")
+		fs.PrintDefaults()
+	}
+	positional, err := parseInterspersed(fs, args)
+	if err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		return 2
+	}
+	cmd := "list"
+	if len(positional) > 0 {
+		cmd, positional = positional[0], positional[1:]
+	}
+	views, err := config.LoadViews(*file)
+	if err != nil {
+		fmt.Fprintf(stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	switch cmd {
+	case "list":
+		tw := tabwriter.NewWriter(stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, "NAME\tENV\tTABLE\tFILTER")
+		for _, v := range views {
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", v.Name, v.Env, v.Root, viewFilterText(v))
+		}
+		if err := tw.Flush(); err != nil {
+			fmt.Fprintf(stderr, "Error: %v\n", err)
+			return 1
+		}
+		return 0
+
+	case "export":
+		selected := views
+		if len(positional) > 0 {
+			selected = nil
+			for _, name := range positional {
+				v, ok := config.FindView(views, name)
+				if !ok {
+					fmt.Fprintf(stderr, "Error: unknown view %q\n", name)
+					return 1
+				}
+				selected = append(selected, v)
+			}
+		}
+		data, err := yaml.Marshal(config.ViewsFile{Views: selected})
+		if err != nil {
+			fmt.Fprintf(stderr, "Error: %v\n", err)
+			return 1
+		}
+		_, _ = stdout.Write(data)
+		return 0
+
+	case "import":
+		if len(positional) != 1 {
+			fs.Usage()
+			return 2
+		}
+		data, err := os.ReadFile(positional[0])
+		if err != nil {
+			fmt.Fprintf(stderr, "Error: %v\n", err)
+			return 1
+		}
+		imported, err := config.ParseViews(data)
+		if err != nil {
+			fmt.Fprintf(stderr, "Error: %s: %v\n", positional[0], err)
+			return 1
+		}
+		for _, v := range imported {
+			status := "added"
+			if _, ok := config.FindView(views, v.Name); ok {
+				status = "replaced"
+			}
+			views = config.PutView(views, v)
+			fmt.Fprintf(stdout, "%s %s\n", status, v.Name)
+		}
+		if err := config.SaveViews(*file, views); err != nil {
+			fmt.Fprintf(stderr, "Error: %v\n", err)
+			return 1
+		}
+		return 0
+	}
+	fs.Usage()
+	return 2
+}
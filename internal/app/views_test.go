@@ -0,0 +1,117 @@
+package app
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/bubbles/table"
+	"github.com/kthoms/o6n/internal/config"
+)
+
+func TestSaveAndRecallView(t *testing.T) {
+	m := modeTestModel(t, config.ModeNormal)
+	m.config.Environments["prod"] = config.Environment{URL: "http://prod:8080"}
+	m.viewsPath = filepath.Join(t.TempDir(), "o6n-views.yaml")
+	m.paneWidth, m.lastWidth, m.lastHeight = 120, 120, 40
+	m.queryFilters = map[string]string{"suspended": "true"}
+	m.searchTerm = "inst"
+	m.table.SetColumns([]table.Column{{Title: "ID ▼", Width: 20}, {Title: "DEFINITIONID", Width: 20}})
+	m.sortColumn, m.sortAscending = 0, false
+
+	m, _ = sendKeyString(m, "B")
+	if m.activeModal != ModalSaveView {
+		t.Fatal("expected B to open the save view dialog")
+	}
+	if body := m.renderSaveViewBody(); !strings.Contains(body, "Filter: suspended=true") || !strings.Contains(body, "Sort:   ID ▼") {
+		t.Errorf("expected the dialog to describe the view, got:\n%s", body)
+	}
+	m, _ = sendKeyString(m, "enter")
+	if m.activeModal != ModalSaveView || m.saveView.err == "" {
+		t.Fatal("expected an empty name to be rejected")
+	}
+	m = typeQuery(m, "suspended")
+	m, _ = sendKeyString(m, "enter")
+	if m.activeModal != ModalNone || !strings.Contains(m.footerError, "Saved view @suspended") {
+		t.Fatalf("expected the view to be saved, footer %q", m.footerError)
+	}
+	saved, err := config.LoadViews(m.viewsPath)
+	if err != nil || len(saved) != 1 || saved[0].Env != "local" || saved[0].Sort != "ID" || !saved[0].SortDesc {
+		t.Fatalf("expected the view in the views file, got %+v %v", saved, err)
+	}
+
+	// leave the view, then recall it from the context switcher
+	m.savedViews[0].Env = "prod"
+	m.prepareStateTransition(TransitionFull)
+	m.currentRoot = "job"
+	m.activeModal = ModalContextSwitcher
+	m.popup.input = "@susp"
+	m.popup.cursor = 0
+	m, cmd := sendKeyString(m, "enter")
+	if cmd == nil || m.currentEnv != "prod" || m.currentRoot != "process-instance" {
+		t.Fatalf("expected the view to open in prod, got %s/%s", m.currentEnv, m.currentRoot)
+	}
+	if m.queryFilters["suspended"] != "true" || m.searchTerm != "inst" {
+		t.Errorf("expected filters and search to be restored, got %v %q", m.queryFilters, m.searchTerm)
+	}
+	res, _ := m.Update(genericLoadedMsg{root: "process-instance", items: []map[string]interface{}{
+		{"id": "inst-a", "definitionId": "a"}, {"id": "inst-b", "definitionId": "b"},
+	}})
+	m = res.(model)
+	if m.sortColumn != 0 || m.sortAscending {
+		t.Errorf("expected the saved sort to apply once loaded, got column %d asc %v", m.sortColumn, m.sortAscending)
+	}
+	if rows := m.table.Rows(); len(rows) != 2 || !strings.Contains(rows[0][0], "inst-b") {
+		t.Errorf("expected rows sorted descending, got %v", rows)
+	}
+
+	m.activeModal = ModalContextSwitcher
+	m.popup.input = "@"
+	m.popup.cursor = 0
+	m, _ = sendKeyString(m, "ctrl+d")
+	if len(m.savedViews) != 0 {
+		t.Errorf("expected Ctrl+D to delete the view, got %v", m.savedViews)
+	}
+}
+
+func TestRestoreViewRejectsUnknownEnvironment(t *testing.T) {
+	m := modeTestModel(t, config.ModeNormal)
+	if err := m.restoreSavedView(config.SavedView{Name: "x", Env: "nope", Root: "job"}); err == nil {
+		t.Error("expected an unknown environment to be rejected")
+	}
+}
+
+func TestRunViewsExportImport(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "o6n-views.yaml")
+	if err := config.SaveViews(file, []config.SavedView{{Name: "stuck", Root: "job", QueryFilters: map[string]string{"noRetriesLeft": "true"}}}); err != nil {
+		t.Fatal(err)
+	}
+	var stdout, stderr bytes.Buffer
+	if code := runViews([]string{"--file", file}, &stdout, &stderr); code != 0 || !strings.Contains(stdout.String(), "noRetriesLeft=true") {
+		t.Fatalf("expected the view to be listed, got %d:\n%s%s", code, stdout.String(), stderr.String())
+	}
+	stdout.Reset()
+	if code := runViews([]string{"export", "stuck", "--file", file}, &stdout, &stderr); code != 0 {
+		t.Fatalf("expected export to succeed: %s", stderr.String())
+	}
+	shared := filepath.Join(dir, "shared.yaml")
+	if err := os.WriteFile(shared, bytes.Replace(stdout.Bytes(), []byte("name: stuck"), []byte("name: stuck-jobs"), 1), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	other := filepath.Join(dir, "teammate.yaml")
+	stdout.Reset()
+	if code := runViews([]string{"import", shared, "--file", other}, &stdout, &stderr); code != 0 || !strings.Contains(stdout.String(), "added stuck-jobs") {
+		t.Fatalf("expected the view to be imported, got %d: %s%s", code, stdout.String(), stderr.String())
+	}
+	views, err := config.LoadViews(other)
+	if err != nil || len(views) != 1 || views[0].QueryFilters["noRetriesLeft"] != "true" {
+		t.Errorf("expected the imported view, got %+v %v", views, err)
+	}
+	if code := runViews([]string{"export", "missing", "--file", file}, &stdout, &stderr); code != 1 {
+		t.Errorf("expected an unknown view to fail, got %d", code)
+	}
+}
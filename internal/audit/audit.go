@@ -0,0 +1,172 @@
+package audit
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/user"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Results of a Record.
+const (
+	ResultOK      = "ok"
+	ResultError   = "error"
+	ResultRefused = "refused" // blocked before reaching the engine, e.g. read-only
+)
+
+// Record is one changing request as written to the audit log, one JSON object
+// per line.
+type Record struct {
+	Time     time.Time `json:"time"`
+	OSUser   string    `json:"os_user"`
+	Env      string    `json:"env"`
+	User     string    `json:"user,omitempty"` // Operaton user or OAuth2 client
+	Action   string    `json:"action"`
+	Method   string    `json:"method"`
+	Path     string    `json:"path"`
+	Body     string    `json:"body,omitempty"`
+	Status   int       `json:"status,omitempty"`
+	Result   string    `json:"result"`
+	Error    string    `json:"error,omitempty"`
+	Duration string    `json:"duration,omitempty"`
+}
+
+var (
+	appendMu   sync.Mutex
+	osUserOnce sync.Once
+	osUser     string
+)
+
+// OSUser returns the name of the operating system user running o6n.
+func OSUser() string {
+	osUserOnce.Do(func() {
+		if u, err := user.Current(); err == nil && u.Username != "" {
+			osUser = u.Username
+			return
+		}
+		osUser = os.Getenv("USER")
+	})
+	return osUser
+}
+
+// Append adds r to the log at path. The file is only ever appended to and is
+// created with mode 0600.
+func Append(path string, r Record) error {
+	data, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+	appendMu.Lock()
+	defer appendMu.Unlock()
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// Read returns the records of the log at path, oldest first. A missing file is
+// an empty log; malformed lines are skipped.
+func Read(path string) ([]Record, error) {
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	var out []Record
+	sc := bufio.NewScanner(f)
+	sc.Buffer(make([]byte, 0, 64*1024), 4*1024*1024)
+	for sc.Scan() {
+		var r Record
+		if json.Unmarshal(sc.Bytes(), &r) == nil {
+			out = append(out, r)
+		}
+	}
+	return out, sc.Err()
+}
+
+// Filter selects records. Empty fields match everything; Action and User match
+// case-insensitive substrings.
+type Filter struct {
+	Env    string
+	Action string
+	User   string // Operaton or OS user
+	Since  time.Time
+	Failed bool // only errors and refused requests
+}
+
+// Match reports whether r passes the filter.
+func (f Filter) Match(r Record) bool {
+	if f.Env != "" && r.Env != f.Env {
+		return false
+	}
+	if f.Action != "" && !containsFold(r.Action, f.Action) {
+		return false
+	}
+	if f.User != "" && !containsFold(r.User, f.User) && !containsFold(r.OSUser, f.User) {
+		return false
+	}
+	if !f.Since.IsZero() && r.Time.Before(f.Since) {
+		return false
+	}
+	if f.Failed && r.Result == ResultOK {
+		return false
+	}
+	return true
+}
+
+// Apply returns the records passing the filter, newest first.
+func (f Filter) Apply(records []Record) []Record {
+	out := make([]Record, 0, len(records))
+	for i := len(records) - 1; i >= 0; i-- {
+		if f.Match(records[i]) {
+			out = append(out, records[i])
+		}
+	}
+	return out
+}
+
+// ParseSince parses a --since value: a duration back from now ("24h", "7d") or a
+// date ("2006-01-02") or timestamp (RFC 3339).
+func ParseSince(s string, now time.Time) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return time.Time{}, nil
+	}
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		if n, err := strconv.Atoi(days); err == nil && n >= 0 {
+			return now.AddDate(0, 0, -n), nil
+		}
+	}
+	if d, err := time.ParseDuration(s); err == nil {
+		return now.Add(-d), nil
+	}
+	if t, err := time.ParseInLocation("2006-01-02", s, time.Local); err == nil {
+		return t, nil
+	}
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("invalid since %q (use e.g. 24h, 7d, 2006-01-02)", s)
+}
+
+func containsFold(s, sub string) bool {
+	return strings.Contains(strings.ToLower(s), strings.ToLower(sub))
+}
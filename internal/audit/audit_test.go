@@ -0,0 +1,50 @@
+package audit
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestAppendReadAndFilter(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "o6n", "audit.jsonl")
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	records := []Record{
+		{Time: now.Add(-48 * time.Hour), OSUser: "alice", Env: "prod", User: "demo", Action: "Suspend instance", Method: "PUT", Path: "/process-instance/p1/suspended", Result: ResultOK, Status: 204},
+		{Time: now.Add(-time.Hour), OSUser: "bob", Env: "prod", User: "ops", Action: "Set job retries", Method: "PUT", Path: "/job/j1/retries", Result: ResultError, Status: 404},
+		{Time: now, OSUser: "alice", Env: "local", User: "demo", Action: "Claim task Review", Method: "POST", Path: "/task/t1/claim", Result: ResultOK, Status: 204},
+	}
+	for _, r := range records {
+		if err := Append(path, r); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if info, err := os.Stat(path); err != nil || info.Mode().Perm() != 0o600 {
+		t.Fatalf("expected a 0600 audit log, got %v %v", info, err)
+	}
+
+	got, err := Read(path)
+	if err != nil || len(got) != 3 || got[1].Action != "Set job retries" || got[1].Status != 404 {
+		t.Fatalf("expected the three records in order, got %+v (%v)", got, err)
+	}
+	if out := (Filter{Env: "prod"}).Apply(got); len(out) != 2 || out[0].OSUser != "bob" {
+		t.Errorf("expected the prod records newest first, got %+v", out)
+	}
+	since, err := ParseSince("1d", now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out := (Filter{User: "ALICE", Since: since}).Apply(got); len(out) != 1 || out[0].Env != "local" {
+		t.Errorf("expected alice's record of the last day, got %+v", out)
+	}
+	if out := (Filter{Failed: true, Action: "retries"}).Apply(got); len(out) != 1 || out[0].User != "ops" {
+		t.Errorf("expected the failed retries change, got %+v", out)
+	}
+	if _, err := ParseSince("yesterday", now); err == nil {
+		t.Error("expected an invalid since value to be rejected")
+	}
+	if missing, err := Read(filepath.Join(t.TempDir(), "none.jsonl")); err != nil || len(missing) != 0 {
+		t.Errorf("expected a missing log to be empty, got %v %v", missing, err)
+	}
+}
@@ -0,0 +1,342 @@
+// Package bpmn parses the flow of a BPMN 2.0 process and lays it out as
+// terminal text lines.
+package bpmn
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Node is a flow node (event, activity or gateway) of a process.
+type Node struct {
+	ID         string
+	Name       string
+	Type       string // BPMN element name, e.g. "userTask", "exclusiveGateway"
+	Scope      string // id of the enclosing sub process ("" = process level)
+	AttachedTo string // host activity of a boundary event
+}
+
+// Label returns the node's name, or its id when unnamed.
+func (n *Node) Label() string {
+	if name := strings.Join(strings.Fields(n.Name), " "); name != "" {
+		return name
+	}
+	return n.ID
+}
+
+// Flow is a sequence flow between two nodes of the same scope.
+type Flow struct {
+	ID        string
+	Name      string
+	Source    string
+	Target    string
+	Condition string
+}
+
+// Process is the parsed flow of one BPMN process.
+type Process struct {
+	ID    string
+	Name  string
+	Nodes []*Node // document order
+	Flows []Flow
+
+	byID map[string]*Node
+}
+
+// Node returns the node with the given id, or nil.
+func (p *Process) Node(id string) *Node {
+	return p.byID[id]
+}
+
+// scopeElements contain nested flow nodes.
+var scopeElements = map[string]bool{
+	"subProcess":      true,
+	"transaction":     true,
+	"adHocSubProcess": true,
+}
+
+// flowNodeElements are the BPMN elements parsed as nodes.
+var flowNodeElements = map[string]bool{
+	"startEvent": true, "endEvent": true, "intermediateCatchEvent": true,
+	"intermediateThrowEvent": true, "boundaryEvent": true,
+	"task": true, "userTask": true, "serviceTask": true, "scriptTask": true,
+	"sendTask": true, "receiveTask": true, "manualTask": true, "businessRuleTask": true,
+	"callActivity": true, "subProcess": true, "transaction": true, "adHocSubProcess": true,
+	"exclusiveGateway": true, "parallelGateway": true, "inclusiveGateway": true,
+	"eventBasedGateway": true, "complexGateway": true,
+}
+
+// Parse reads the process with the given id (the process definition key) from a
+// BPMN 2.0 XML document. An empty processID selects the first executable process.
+func Parse(data []byte, processID string) (*Process, error) {
+	dec := xml.NewDecoder(bytes.NewReader(data))
+	var (
+		proc    *Process
+		scopes  []string // open process / sub process ids
+		inFlow  *Flow    // sequence flow whose condition is being read
+		inCond  bool
+		skipAll bool // inside a process that is not the one requested
+	)
+	for {
+		tok, err := dec.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid BPMN XML: %w", err)
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			local := t.Name.Local
+			attrs := attrMap(t.Attr)
+			if local == "process" {
+				if proc != nil || (processID != "" && attrs["id"] != processID) ||
+					(processID == "" && attrs["isExecutable"] == "false") {
+					skipAll = true
+					continue
+				}
+				proc = &Process{ID: attrs["id"], Name: attrs["name"], byID: map[string]*Node{}}
+				scopes = []string{""}
+				continue
+			}
+			if proc == nil || skipAll || len(scopes) == 0 {
+				continue
+			}
+			scope := scopes[len(scopes)-1]
+			switch {
+			case flowNodeElements[local]:
+				n := &Node{ID: attrs["id"], Name: attrs["name"], Type: local, Scope: scope, AttachedTo: attrs["attachedToRef"]}
+				proc.Nodes = append(proc.Nodes, n)
+				proc.byID[n.ID] = n
+				if scopeElements[local] {
+					scopes = append(scopes, n.ID)
+				}
+			case local == "sequenceFlow":
+				proc.Flows = append(proc.Flows, Flow{ID: attrs["id"], Name: attrs["name"], Source: attrs["sourceRef"], Target: attrs["targetRef"]})
+				inFlow = &proc.Flows[len(proc.Flows)-1]
+			case local == "conditionExpression" && inFlow != nil:
+				inCond = true
+			}
+		case xml.CharData:
+			if inCond && inFlow != nil {
+				inFlow.Condition += string(t)
+			}
+		case xml.EndElement:
+			local := t.Name.Local
+			switch {
+			case local == "process":
+				if skipAll {
+					skipAll = false
+				} else {
+					scopes = nil
+				}
+			case scopeElements[local] && len(scopes) > 1 && !skipAll:
+				scopes = scopes[:len(scopes)-1]
+			case local == "sequenceFlow":
+				inFlow = nil
+			case local == "conditionExpression":
+				inCond = false
+			}
+		}
+	}
+	if proc == nil {
+		if processID != "" {
+			return nil, fmt.Errorf("process %q not found in BPMN XML", processID)
+		}
+		return nil, errors.New("no executable process in BPMN XML")
+	}
+	for i := range proc.Flows {
+		proc.Flows[i].Condition = strings.Join(strings.Fields(proc.Flows[i].Condition), " ")
+	}
+	return proc, nil
+}
+
+func attrMap(attrs []xml.Attr) map[string]string {
+	m := make(map[string]string, len(attrs))
+	for _, a := range attrs {
+		m[a.Name.Local] = a.Value
+	}
+	return m
+}
+
+// Glyph returns the symbol a node type is drawn with.
+func Glyph(nodeType string) string {
+	switch nodeType {
+	case "startEvent":
+		return "○"
+	case "endEvent":
+		return "◉"
+	case "intermediateCatchEvent", "intermediateThrowEvent":
+		return "◎"
+	case "boundaryEvent":
+		return "◌"
+	case "exclusiveGateway":
+		return "◇×"
+	case "parallelGateway":
+		return "◇+"
+	case "inclusiveGateway":
+		return "◇○"
+	case "eventBasedGateway":
+		return "◇◎"
+	case "complexGateway":
+		return "◇*"
+	case "subProcess", "transaction", "adHocSubProcess":
+		return "▣"
+	case "callActivity":
+		return "▭⇢"
+	default:
+		return "▭"
+	}
+}
+
+// Line is one line of a laid out diagram. Node lines carry the id of the node
+// they draw so callers can overlay runtime state.
+type Line struct {
+	NodeID string
+	Text   string
+}
+
+// edge is an outgoing connection drawn below a node.
+type edge struct {
+	target   string
+	label    string
+	boundary bool
+}
+
+// Lines lays out the process top to bottom. Nodes of each scope are ordered
+// depth first from their start nodes so that branches stay together; a node
+// continuing straight into the next line is joined with "│", every other
+// connection is listed below its source ("├─▶ target [label]", "↺" for loops,
+// "┈▶" for boundary events). Sub process contents are indented below the sub process.
+func (p *Process) Lines() []Line {
+	outgoing := make(map[string][]edge)
+	for _, f := range p.Flows {
+		label := f.Name
+		if label == "" && f.Condition != "" {
+			label = truncate(f.Condition, 30)
+		}
+		outgoing[f.Source] = append(outgoing[f.Source], edge{target: f.Target, label: label})
+	}
+	for _, n := range p.Nodes {
+		if n.AttachedTo != "" {
+			outgoing[n.AttachedTo] = append(outgoing[n.AttachedTo], edge{target: n.ID, boundary: true})
+		}
+	}
+	var lines []Line
+	p.layoutScope("", 0, outgoing, &lines)
+	return lines
+}
+
+func (p *Process) layoutScope(scope string, depth int, outgoing map[string][]edge, lines *[]Line) {
+	var nodes []*Node
+	for _, n := range p.Nodes {
+		if n.Scope == scope {
+			nodes = append(nodes, n)
+		}
+	}
+	incoming := make(map[string]int)
+	for _, n := range nodes {
+		for _, e := range outgoing[n.ID] {
+			incoming[e.target]++
+		}
+	}
+
+	// Depth-first reverse postorder from the scope's entry nodes; back edges
+	// (to a node still on the DFS stack) are loops.
+	visited := make(map[string]bool)
+	onStack := make(map[string]bool)
+	back := make(map[[2]string]bool)
+	var post []*Node
+	var visit func(n *Node)
+	visit = func(n *Node) {
+		visited[n.ID] = true
+		onStack[n.ID] = true
+		outs := outgoing[n.ID]
+		// Visit in reverse so that the first outgoing flow ends up first in the order
+		for i := len(outs) - 1; i >= 0; i-- {
+			t := p.byID[outs[i].target]
+			if t == nil || t.Scope != scope {
+				continue
+			}
+			if onStack[t.ID] {
+				back[[2]string{n.ID, t.ID}] = true
+				continue
+			}
+			if !visited[t.ID] {
+				visit(t)
+			}
+		}
+		onStack[n.ID] = false
+		post = append(post, n)
+	}
+	var roots []*Node
+	for _, n := range nodes {
+		if n.Type == "startEvent" {
+			roots = append(roots, n)
+		}
+	}
+	for _, n := range nodes {
+		if n.Type != "startEvent" && incoming[n.ID] == 0 {
+			roots = append(roots, n)
+		}
+	}
+	roots = append(roots, nodes...) // unreachable cycles, in document order
+	var order []*Node
+	for _, r := range roots {
+		if visited[r.ID] {
+			continue
+		}
+		post = post[:0]
+		visit(r)
+		for i := len(post) - 1; i >= 0; i-- {
+			order = append(order, post[i])
+		}
+	}
+
+	indent := strings.Repeat("  ", depth)
+	for i, n := range order {
+		*lines = append(*lines, Line{NodeID: n.ID, Text: indent + Glyph(n.Type) + " " + n.Label()})
+		if scopeElements[n.Type] {
+			p.layoutScope(n.ID, depth+1, outgoing, lines)
+		}
+		outs := outgoing[n.ID]
+		if len(outs) == 1 && i+1 < len(order) && outs[0].target == order[i+1].ID &&
+			outs[0].label == "" && !outs[0].boundary {
+			*lines = append(*lines, Line{Text: indent + "│"})
+			continue
+		}
+		for j, e := range outs {
+			branch := "├"
+			if j == len(outs)-1 {
+				branch = "└"
+			}
+			arrow := "─▶ "
+			if e.boundary {
+				arrow = "┈▶ "
+			} else if back[[2]string{n.ID, e.target}] {
+				arrow = "─↺ "
+			}
+			target := e.target
+			if t := p.byID[e.target]; t != nil {
+				target = t.Label()
+			}
+			text := indent + branch + arrow + target
+			if e.label != "" {
+				text += " [" + e.label + "]"
+			}
+			*lines = append(*lines, Line{Text: text})
+		}
+	}
+}
+
+func truncate(s string, n int) string {
+	r := []rune(s)
+	if len(r) <= n {
+		return s
+	}
+	return string(r[:n-1]) + "…"
+}
@@ -0,0 +1,114 @@
+package bpmn
+
+import (
+	"strings"
+	"testing"
+)
+
+const invoiceXML = `<?xml version="1.0" encoding="UTF-8"?>
+<bpmn:definitions xmlns:bpmn="http://www.omg.org/spec/BPMN/20100524/MODEL" id="defs">
+  <bpmn:collaboration id="collab"><bpmn:participant id="p1" processRef="invoice"/></bpmn:collaboration>
+  <bpmn:process id="other" isExecutable="true">
+    <bpmn:startEvent id="otherStart"/>
+  </bpmn:process>
+  <bpmn:process id="invoice" name="Invoice" isExecutable="true">
+    <bpmn:startEvent id="start" name="Invoice&#10;received"/>
+    <bpmn:sequenceFlow id="f1" sourceRef="start" targetRef="review"/>
+    <bpmn:userTask id="review" name="Review invoice"/>
+    <bpmn:boundaryEvent id="timeout" name="2 days" attachedToRef="review"/>
+    <bpmn:sequenceFlow id="f2" sourceRef="review" targetRef="approved"/>
+    <bpmn:exclusiveGateway id="approved" name="Approved?"/>
+    <bpmn:sequenceFlow id="f3" name="yes" sourceRef="approved" targetRef="pay">
+      <bpmn:conditionExpression>${approved}</bpmn:conditionExpression>
+    </bpmn:sequenceFlow>
+    <bpmn:sequenceFlow id="f4" sourceRef="approved" targetRef="clarify">
+      <bpmn:conditionExpression>${!approved}</bpmn:conditionExpression>
+    </bpmn:sequenceFlow>
+    <bpmn:userTask id="clarify" name="Clarify"/>
+    <bpmn:sequenceFlow id="f5" sourceRef="clarify" targetRef="review"/>
+    <bpmn:subProcess id="pay" name="Payment">
+      <bpmn:startEvent id="payStart"/>
+      <bpmn:sequenceFlow id="p1" sourceRef="payStart" targetRef="transfer"/>
+      <bpmn:serviceTask id="transfer" name="Transfer"/>
+    </bpmn:subProcess>
+    <bpmn:sequenceFlow id="f6" sourceRef="pay" targetRef="end"/>
+    <bpmn:endEvent id="end" name="Paid"/>
+    <bpmn:endEvent id="late" name="Escalated"/>
+    <bpmn:sequenceFlow id="f7" sourceRef="timeout" targetRef="late"/>
+  </bpmn:process>
+</bpmn:definitions>`
+
+func TestParseSelectsProcessAndScopes(t *testing.T) {
+	p, err := Parse([]byte(invoiceXML), "invoice")
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if p.ID != "invoice" || p.Node("otherStart") != nil {
+		t.Fatalf("expected only process invoice, got %s", p.ID)
+	}
+	if n := p.Node("transfer"); n == nil || n.Scope != "pay" {
+		t.Errorf("expected transfer inside sub process pay, got %+v", n)
+	}
+	if n := p.Node("end"); n == nil || n.Scope != "" {
+		t.Errorf("expected end at process level after the sub process, got %+v", n)
+	}
+	if p.Node("timeout").AttachedTo != "review" {
+		t.Error("expected boundary event attached to review")
+	}
+	if p.Node("start").Label() != "Invoice received" {
+		t.Errorf("expected whitespace-normalised label, got %q", p.Node("start").Label())
+	}
+	if p.Flows[3].Condition != "${!approved}" {
+		t.Errorf("expected condition of f4, got %q", p.Flows[3].Condition)
+	}
+
+	if first, err := Parse([]byte(invoiceXML), ""); err != nil || first.ID != "other" {
+		t.Errorf("expected first executable process without id, got %v %v", first, err)
+	}
+	if _, err := Parse([]byte(invoiceXML), "missing"); err == nil {
+		t.Error("expected error for a missing process")
+	}
+}
+
+func TestLinesLayoutFlowBranchesAndLoops(t *testing.T) {
+	p, err := Parse([]byte(invoiceXML), "invoice")
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	var texts []string
+	nodeLines := map[string]int{}
+	for i, l := range p.Lines() {
+		texts = append(texts, l.Text)
+		if l.NodeID != "" {
+			nodeLines[l.NodeID] = i
+		}
+	}
+	got := strings.Join(texts, "\n")
+	want := strings.Join([]string{
+		"○ Invoice received",
+		"│",
+		"▭ Review invoice",
+		"├─▶ Approved?",
+		"└┈▶ 2 days",
+		"◇× Approved?",
+		"├─▶ Payment [yes]",
+		"└─▶ Clarify [${!approved}]",
+		"▣ Payment",
+		"  ○ payStart",
+		"  │",
+		"  ▭ Transfer",
+		"│",
+		"◉ Paid",
+		"▭ Clarify",
+		"└─↺ Review invoice",
+		"◌ 2 days",
+		"│",
+		"◉ Escalated",
+	}, "\n")
+	if got != want {
+		t.Errorf("unexpected layout:\n%s\n\nwant:\n%s", got, want)
+	}
+	if nodeLines["transfer"] != 11 {
+		t.Errorf("expected node id on node lines, got %v", nodeLines)
+	}
+}
@@ -0,0 +1,157 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/kthoms/o6n/internal/audit"
+	cfgpkg "github.com/kthoms/o6n/internal/config"
+)
+
+// AuditFile is the append-only JSONL log every changing request is recorded in
+// (see package audit); "" disables the audit log.
+var AuditFile = ""
+
+// maxAuditBody bounds the request body kept per audit record.
+const maxAuditBody = 4096
+
+type auditKey struct{}
+
+// auditInfo names the environment and action a request is recorded under.
+type auditInfo struct {
+	env    string
+	action string
+}
+
+// WithAudit returns ctx labelling its changing requests in the audit log with
+// the environment name and action. Unlabelled requests are recorded under the
+// environment URL and their method and path.
+func WithAudit(ctx context.Context, env, action string) context.Context {
+	return context.WithValue(ctx, auditKey{}, auditInfo{env: env, action: action})
+}
+
+// auditTransport records every request except reads (GET, HEAD, OPTIONS and
+// the POST queries in auditSkipped) with its outcome in AuditFile. It is the
+// outermost transport, so a request is recorded once however often it was
+// retried, and requests refused for a read-only environment are recorded too.
+type auditTransport struct {
+	base http.RoundTripper
+	env  cfgpkg.Environment
+}
+
+// auditSkipped are POST endpoints that only read.
+var auditSkipped = []string{"/migration/generate", "/migration/validate"}
+
+func auditable(req *http.Request) bool {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return false
+	}
+	for _, suffix := range auditSkipped {
+		if strings.HasSuffix(req.URL.Path, suffix) {
+			return false
+		}
+	}
+	return true
+}
+
+func (t *auditTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	path := AuditFile
+	if path == "" || !auditable(req) {
+		return t.base.RoundTrip(req)
+	}
+	rec := audit.Record{
+		Time:   time.Now().UTC(),
+		OSUser: audit.OSUser(),
+		Env:    t.env.URL,
+		User:   t.env.Username,
+		Method: req.Method,
+		Path:   t.relativePath(req.URL),
+		Body:   auditBody(req),
+	}
+	if rec.User == "" && t.env.Auth != nil {
+		rec.User = t.env.Auth.ClientID
+	}
+	rec.Action = rec.Method + " " + rec.Path
+	if info, ok := req.Context().Value(auditKey{}).(auditInfo); ok {
+		if info.env != "" {
+			rec.Env = info.env
+		}
+		if info.action != "" {
+			rec.Action = info.action
+		}
+	}
+
+	start := time.Now()
+	resp, err := t.base.RoundTrip(req)
+	rec.Duration = time.Since(start).Round(time.Millisecond).String()
+	switch {
+	case errors.Is(err, ErrReadOnly):
+		rec.Result, rec.Error = audit.ResultRefused, err.Error()
+	case err != nil:
+		rec.Result, rec.Error = audit.ResultError, err.Error()
+	default:
+		rec.Status = resp.StatusCode
+		rec.Result = audit.ResultOK
+		if resp.StatusCode >= 300 {
+			// keep the body readable for the caller
+			data, _ := io.ReadAll(resp.Body)
+			resp.Body.Close()
+			resp.Body = io.NopCloser(bytes.NewReader(data))
+			rec.Result = audit.ResultError
+			rec.Error = newAPIError("", "", resp.StatusCode, data).Error()
+		}
+	}
+	if appendErr := audit.Append(path, rec); appendErr != nil {
+		log.Printf("audit: %v", appendErr)
+	}
+	return resp, err
+}
+
+// relativePath returns the request path below the environment URL, with its query.
+func (t *auditTransport) relativePath(u *url.URL) string {
+	p := u.Path
+	if base, err := url.Parse(t.env.URL); err == nil {
+		if rest, ok := strings.CutPrefix(p, strings.TrimRight(base.Path, "/")); ok && rest != "" {
+			p = rest
+		}
+	}
+	if u.RawQuery != "" {
+		p += "?" + u.RawQuery
+	}
+	return p
+}
+
+// auditBody returns the JSON body of req without consuming it; other bodies
+// (e.g. deployment uploads) are summarised by size and type.
+func auditBody(req *http.Request) string {
+	if req.Body == nil || req.Body == http.NoBody || req.GetBody == nil {
+		return ""
+	}
+	rc, err := req.GetBody()
+	if err != nil {
+		return ""
+	}
+	defer rc.Close()
+	data, _ := io.ReadAll(rc)
+	if len(data) == 0 {
+		return ""
+	}
+	mediaType, _, _ := mime.ParseMediaType(req.Header.Get("Content-Type"))
+	if mediaType != "" && !strings.Contains(mediaType, "json") {
+		return fmt.Sprintf("<%d bytes %s>", len(data), mediaType)
+	}
+	if len(data) > maxAuditBody {
+		return string(data[:maxAuditBody]) + "…"
+	}
+	return string(data)
+}
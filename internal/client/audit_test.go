@@ -0,0 +1,63 @@
+package client
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/kthoms/o6n/internal/audit"
+	cfgpkg "github.com/kthoms/o6n/internal/config"
+)
+
+func TestAuditLogRecordsChangingRequests(t *testing.T) {
+	saved := AuditFile
+	AuditFile = filepath.Join(t.TempDir(), "audit.jsonl")
+	t.Cleanup(func() { AuditFile = saved })
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/engine-rest/job/missing/retries" {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = io.WriteString(w, `{"type":"InvalidRequestException","message":"Job missing not found"}`)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		if r.Method == http.MethodPut && string(body) != `{"retries":3}` {
+			t.Errorf("expected the body to reach the engine, got %q", body)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+	env := cfgpkg.Environment{URL: srv.URL + "/engine-rest", Username: "demo"}
+
+	ctx := WithAudit(context.Background(), "local", "Set job retries")
+	if _, err := Do(ctx, env, http.MethodPut, env.URL+"/job/j1/retries", []byte(`{"retries":3}`)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Do(ctx, env, http.MethodPut, env.URL+"/job/missing/retries", []byte(`{"retries":3}`)); err == nil {
+		t.Fatal("expected the 404 to be returned")
+	}
+	if _, err := Do(context.Background(), env, http.MethodGet, env.URL+"/job", nil); err != nil {
+		t.Fatal(err)
+	}
+	readOnly := cfgpkg.Environment{URL: srv.URL + "/engine-rest", Mode: cfgpkg.ModeReadOnly}
+	_, _ = Do(context.Background(), readOnly, http.MethodDelete, readOnly.URL+"/process-instance/p1", nil)
+
+	records, err := audit.Read(AuditFile)
+	if err != nil || len(records) != 3 {
+		t.Fatalf("expected three changing requests to be recorded, got %+v (%v)", records, err)
+	}
+	ok := records[0]
+	if ok.Env != "local" || ok.Action != "Set job retries" || ok.User != "demo" || ok.Method != http.MethodPut ||
+		ok.Path != "/job/j1/retries" || ok.Body != `{"retries":3}` || ok.Status != 204 || ok.Result != audit.ResultOK {
+		t.Errorf("unexpected record %+v", ok)
+	}
+	if failed := records[1]; failed.Result != audit.ResultError || failed.Status != 404 || failed.Error != "HTTP 404: Job missing not found" {
+		t.Errorf("expected the engine error to be recorded, got %+v", failed)
+	}
+	if refused := records[2]; refused.Result != audit.ResultRefused || refused.Action != "DELETE /process-instance/p1" || refused.Env != readOnly.URL {
+		t.Errorf("expected the refused unlabelled delete to be recorded, got %+v", refused)
+	}
+}
@@ -0,0 +1,291 @@
+package client
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	cfgpkg "github.com/kthoms/o6n/internal/config"
+)
+
+// tokenExpiryLeeway renews tokens shortly before they expire so in-flight
+// requests do not race the expiry.
+const tokenExpiryLeeway = 30 * time.Second
+
+// token is an access token as cached in memory and in TokenCacheFile.
+type token struct {
+	AccessToken  string    `json:"access_token"`
+	RefreshToken string    `json:"refresh_token,omitempty"`
+	Expiry       time.Time `json:"expiry,omitempty"`
+}
+
+func (t *token) valid() bool {
+	return t != nil && t.AccessToken != "" && (t.Expiry.IsZero() || time.Until(t.Expiry) > tokenExpiryLeeway)
+}
+
+// TokenCacheFile persists fetched tokens between runs (0600); "" disables the
+// file cache and keeps tokens in memory only.
+var TokenCacheFile = defaultTokenCacheFile()
+
+// tokenMu guards tokenCache, tokenFetches and the token cache file. It is never
+// held during a token request: a fetch runs once per cache key and concurrent
+// callers for that key wait for its result, while other keys proceed.
+var (
+	tokenMu      sync.Mutex
+	tokenCache   = map[string]*token{}
+	tokenFetches = map[string]*tokenFetch{}
+)
+
+// tokenFetch is a token request in flight; done is closed once t or err is set.
+type tokenFetch struct {
+	done chan struct{}
+	t    *token
+	err  error
+}
+
+func defaultTokenCacheFile() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "o6n", "tokens.json")
+}
+
+// tokenCacheKey identifies the token of an auth block without storing its secrets.
+func tokenCacheKey(a *cfgpkg.AuthConfig) string {
+	h := sha256.Sum256([]byte(strings.Join([]string{
+		a.Type, a.TokenURL, a.ClientID, a.Audience, strings.Join(a.Scopes, " "), a.RefreshToken,
+	}, "\x00")))
+	return hex.EncodeToString(h[:16])
+}
+
+func readTokenCacheFile() map[string]*token {
+	out := map[string]*token{}
+	if TokenCacheFile == "" {
+		return out
+	}
+	if data, err := os.ReadFile(TokenCacheFile); err == nil {
+		_ = json.Unmarshal(data, &out)
+	}
+	return out
+}
+
+func writeTokenCacheFile(key string, t *token) {
+	if TokenCacheFile == "" {
+		return
+	}
+	all := readTokenCacheFile()
+	for k, v := range all {
+		if !v.valid() && v.RefreshToken == "" {
+			delete(all, k)
+		}
+	}
+	all[key] = t
+	data, err := json.MarshalIndent(all, "", "  ")
+	if err != nil {
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(TokenCacheFile), 0o700); err != nil {
+		return
+	}
+	_ = os.WriteFile(TokenCacheFile, data, 0o600)
+}
+
+// bearerToken returns the access token for a bearer or client_credentials auth
+// block: from memory, the token cache file, the static token, or a new token
+// from the token endpoint. rejected is a token the engine answered 401 for; it
+// is never returned again.
+func bearerToken(hc *http.Client, a *cfgpkg.AuthConfig, rejected string) (string, error) {
+	key := tokenCacheKey(a)
+	tokenMu.Lock()
+	if f := tokenFetches[key]; f != nil {
+		tokenMu.Unlock()
+		<-f.done
+		if f.err != nil {
+			return "", f.err
+		}
+		return f.t.AccessToken, nil
+	}
+	form, cached, static, err := tokenRequestForm(a, key, rejected)
+	if err != nil || form == nil {
+		tokenMu.Unlock()
+		return static, err
+	}
+	f := &tokenFetch{done: make(chan struct{})}
+	tokenFetches[key] = f
+	tokenMu.Unlock()
+
+	f.t, f.err = requestToken(hc, a, form)
+
+	tokenMu.Lock()
+	delete(tokenFetches, key)
+	if f.err == nil {
+		if f.t.RefreshToken == "" && cached != nil {
+			f.t.RefreshToken = cached.RefreshToken
+		}
+		tokenCache[key] = f.t
+		writeTokenCacheFile(key, f.t)
+	}
+	tokenMu.Unlock()
+	close(f.done)
+	if f.err != nil {
+		return "", f.err
+	}
+	return f.t.AccessToken, nil
+}
+
+// tokenRequestForm decides how to obtain the token of a under key; the caller
+// holds tokenMu. It returns the usable token as static with a nil form, or the
+// form of the token request to make together with the cached token it renews.
+func tokenRequestForm(a *cfgpkg.AuthConfig, key, rejected string) (form url.Values, cached *token, static string, err error) {
+	cached = tokenCache[key]
+	if cached == nil {
+		if cached = readTokenCacheFile()[key]; cached != nil {
+			tokenCache[key] = cached
+		}
+	}
+	if cached.valid() && cached.AccessToken != rejected {
+		return nil, cached, cached.AccessToken, nil
+	}
+
+	switch a.Type {
+	case cfgpkg.AuthBearer:
+		if a.Token != "" && a.Token != rejected {
+			return nil, cached, a.Token, nil
+		}
+		if a.RefreshToken == "" || a.TokenURL == "" {
+			if a.Token == "" {
+				return nil, cached, "", fmt.Errorf("bearer auth requires a token")
+			}
+			return nil, cached, "", fmt.Errorf("bearer token rejected and no refresh_token configured")
+		}
+		refresh := a.RefreshToken
+		if cached != nil && cached.RefreshToken != "" {
+			refresh = cached.RefreshToken // rotated by the last refresh
+		}
+		form = url.Values{"grant_type": {"refresh_token"}, "refresh_token": {refresh}}
+	case cfgpkg.AuthClientCredentials:
+		form = url.Values{"grant_type": {"client_credentials"}}
+	default:
+		return nil, cached, "", a.Validate()
+	}
+	return form, cached, "", nil
+}
+
+// requestToken posts an OAuth2 token request to the auth block's token endpoint.
+func requestToken(hc *http.Client, a *cfgpkg.AuthConfig, form url.Values) (*token, error) {
+	if a.ClientID != "" {
+		form.Set("client_id", a.ClientID)
+	}
+	if a.ClientSecret != "" {
+		form.Set("client_secret", a.ClientSecret)
+	}
+	if len(a.Scopes) > 0 {
+		form.Set("scope", strings.Join(a.Scopes, " "))
+	}
+	if a.Audience != "" {
+		form.Set("audience", a.Audience)
+	}
+	req, err := http.NewRequest(http.MethodPost, a.TokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, fmt.Errorf("token request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	resp, err := hc.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("token request: %w", err)
+	}
+	defer resp.Body.Close()
+	data, _ := io.ReadAll(resp.Body)
+	var body struct {
+		AccessToken      string `json:"access_token"`
+		RefreshToken     string `json:"refresh_token"`
+		ExpiresIn        int    `json:"expires_in"`
+		Error            string `json:"error"`
+		ErrorDescription string `json:"error_description"`
+	}
+	_ = json.Unmarshal(data, &body)
+	if resp.StatusCode >= 400 || body.AccessToken == "" {
+		reason := strings.TrimSpace(body.Error + " " + body.ErrorDescription)
+		if reason == "" {
+			reason = strings.TrimSpace(string(data))
+		}
+		return nil, fmt.Errorf("token endpoint %s: HTTP %d: %s", a.TokenURL, resp.StatusCode, reason)
+	}
+	t := &token{AccessToken: body.AccessToken, RefreshToken: body.RefreshToken}
+	if body.ExpiresIn > 0 {
+		t.Expiry = time.Now().Add(time.Duration(body.ExpiresIn) * time.Second)
+	}
+	return t, nil
+}
+
+// authTransport authenticates every request to an environment: basic auth with
+// Username/Password, or a bearer token from the auth block. A password_cmd is
+// run on the first request unless secret commands are frozen (see
+// Environment.WithCommandSecrets and config.FreezeSecretCommands). When the engine
+// answers 401 to a bearer token that can be renewed, a new token is fetched and
+// the request replayed once.
+type authTransport struct {
+	base        http.RoundTripper
+	env         cfgpkg.Environment
+	tokenClient *http.Client // token endpoint requests: the environment's TLS and proxy, no auth
+}
+
+func (t *authTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	env, err := t.env.WithCommandSecrets()
+	if err != nil {
+		return nil, fmt.Errorf("auth: %w", err)
+	}
+	a := env.Auth
+	if !a.IsBearer() {
+		if env.Username == "" {
+			return t.base.RoundTrip(req)
+		}
+		r := req.Clone(req.Context())
+		r.SetBasicAuth(env.Username, env.Password)
+		return t.base.RoundTrip(r)
+	}
+
+	tok, err := bearerToken(t.tokenClient, a, "")
+	if err != nil {
+		return nil, fmt.Errorf("auth: %w", err)
+	}
+	resp, err := t.base.RoundTrip(withBearer(req, tok))
+	if err != nil || resp.StatusCode != http.StatusUnauthorized {
+		return resp, err
+	}
+	renewable := a.Type == cfgpkg.AuthClientCredentials || (a.RefreshToken != "" && a.TokenURL != "")
+	replayable := req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+	if !renewable || !replayable {
+		return resp, nil
+	}
+	fresh, err := bearerToken(t.tokenClient, a, tok)
+	if err != nil {
+		return resp, nil // report the engine's 401, not the failed renewal
+	}
+	retry := withBearer(req, fresh)
+	if req.GetBody != nil {
+		if retry.Body, err = req.GetBody(); err != nil {
+			return resp, nil
+		}
+	}
+	_, _ = io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+	return t.base.RoundTrip(retry)
+}
+
+func withBearer(req *http.Request, tok string) *http.Request {
+	r := req.Clone(req.Context())
+	r.Header.Set("Authorization", "Bearer "+tok)
+	return r
+}
@@ -0,0 +1,200 @@
+package client
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	cfgpkg "github.com/kthoms/o6n/internal/config"
+)
+
+// isolateTokenCache points the token cache at a fresh temp file for one test.
+func isolateTokenCache(t *testing.T) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "tokens.json")
+	saved := TokenCacheFile
+	TokenCacheFile = path
+	tokenMu.Lock()
+	tokenCache = map[string]*token{}
+	tokenMu.Unlock()
+	t.Cleanup(func() { TokenCacheFile = saved })
+	return path
+}
+
+func TestClientCredentialsTokenIsCachedAndRenewedOn401(t *testing.T) {
+	cachePath := isolateTokenCache(t)
+	var issued int32
+	idp := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		if r.Form.Get("grant_type") != "client_credentials" || r.Form.Get("client_id") != "o6n" ||
+			r.Form.Get("client_secret") != "s3cret" || r.Form.Get("scope") != "openid engine" {
+			t.Errorf("unexpected token request %v", r.Form)
+		}
+		n := atomic.AddInt32(&issued, 1)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = io.WriteString(w, `{"access_token":"tok-`+string(rune('0'+n))+`","expires_in":300}`)
+	}))
+	defer idp.Close()
+
+	var revoked atomic.Bool
+	engine := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth := r.Header.Get("Authorization")
+		if revoked.Load() && auth == "Bearer tok-1" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		_, _ = io.WriteString(w, auth+"|"+string(body))
+	}))
+	defer engine.Close()
+
+	env := cfgpkg.Environment{URL: engine.URL, Auth: &cfgpkg.AuthConfig{
+		Type: cfgpkg.AuthClientCredentials, TokenURL: idp.URL, ClientID: "o6n", ClientSecret: "s3cret",
+		Scopes: []string{"openid", "engine"},
+	}}
+	post := func() string {
+		resp, err := HTTPClient(env).Post(engine.URL+"/x", "application/json", strings.NewReader(`{"a":1}`))
+		if err != nil {
+			t.Fatalf("request failed: %v", err)
+		}
+		defer resp.Body.Close()
+		data, _ := io.ReadAll(resp.Body)
+		return string(data)
+	}
+
+	if got := post(); got != `Bearer tok-1|{"a":1}` {
+		t.Fatalf("unexpected first response %q", got)
+	}
+	if got := post(); got != `Bearer tok-1|{"a":1}` || issued != 1 {
+		t.Fatalf("expected the cached token to be reused, got %q after %d token requests", got, issued)
+	}
+	if info, err := os.Stat(cachePath); err != nil || info.Mode().Perm() != 0o600 {
+		t.Errorf("expected a 0600 token cache file, got %v %v", info, err)
+	}
+
+	// The engine rejects the token: renew once and replay the request with its body
+	revoked.Store(true)
+	if got := post(); got != `Bearer tok-2|{"a":1}` || issued != 2 {
+		t.Fatalf("expected renewal and replay, got %q after %d token requests", got, issued)
+	}
+
+	// A new process starts from the token cache file
+	tokenMu.Lock()
+	tokenCache = map[string]*token{}
+	tokenMu.Unlock()
+	if got := post(); got != `Bearer tok-2|{"a":1}` || issued != 2 {
+		t.Errorf("expected the token from the cache file, got %q after %d token requests", got, issued)
+	}
+}
+
+func TestBearerTokenRefreshAndBasicFallback(t *testing.T) {
+	isolateTokenCache(t)
+	idp := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		if r.Form.Get("grant_type") != "refresh_token" || r.Form.Get("refresh_token") != "rt-1" {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = io.WriteString(w, `{"error":"invalid_grant","error_description":"Token is not active"}`)
+			return
+		}
+		_, _ = io.WriteString(w, `{"access_token":"fresh","refresh_token":"rt-2","expires_in":60}`)
+	}))
+	defer idp.Close()
+	engine := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if u, p, ok := r.BasicAuth(); ok {
+			_, _ = io.WriteString(w, "basic "+u+":"+p)
+			return
+		}
+		if r.Header.Get("Authorization") != "Bearer fresh" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_, _ = io.WriteString(w, "ok")
+	}))
+	defer engine.Close()
+
+	get := func(env cfgpkg.Environment) (int, string) {
+		resp, err := HTTPClient(env).Get(engine.URL)
+		if err != nil {
+			t.Fatalf("request failed: %v", err)
+		}
+		defer resp.Body.Close()
+		data, _ := io.ReadAll(resp.Body)
+		return resp.StatusCode, string(data)
+	}
+
+	bearer := cfgpkg.Environment{Auth: &cfgpkg.AuthConfig{Type: cfgpkg.AuthBearer, Token: "expired", RefreshToken: "rt-1", TokenURL: idp.URL}}
+	if code, body := get(bearer); code != http.StatusOK || body != "ok" {
+		t.Fatalf("expected refreshed token to be accepted, got %d %q", code, body)
+	}
+	static := cfgpkg.Environment{Auth: &cfgpkg.AuthConfig{Type: cfgpkg.AuthBearer, Token: "static"}}
+	if code, _ := get(static); code != http.StatusUnauthorized {
+		t.Errorf("expected the engine's 401 for a static token without refresh, got %d", code)
+	}
+	if _, body := get(cfgpkg.Environment{Username: "demo", Password: "demo"}); body != "basic demo:demo" {
+		t.Errorf("expected basic auth without an auth block, got %q", body)
+	}
+
+	broken := cfgpkg.Environment{Auth: &cfgpkg.AuthConfig{Type: cfgpkg.AuthClientCredentials, TokenURL: idp.URL, ClientID: "x"}}
+	if _, err := HTTPClient(broken).Get(engine.URL); err == nil || !strings.Contains(err.Error(), "auth: token endpoint") ||
+		!strings.Contains(err.Error(), "invalid_grant") {
+		t.Errorf("expected token endpoint error, got %v", err)
+	}
+}
+
+func TestTokenRequestsOnlyWaitForTheirOwnKey(t *testing.T) {
+	isolateTokenCache(t)
+	release := make(chan struct{})
+	var slowRequests int32
+	idp := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		if r.Form.Get("client_id") == "slow" {
+			atomic.AddInt32(&slowRequests, 1)
+			<-release
+		}
+		_, _ = io.WriteString(w, `{"access_token":"tok-`+r.Form.Get("client_id")+`","expires_in":300}`)
+	}))
+	defer idp.Close()
+
+	slow := &cfgpkg.AuthConfig{Type: cfgpkg.AuthClientCredentials, TokenURL: idp.URL, ClientID: "slow"}
+	fast := &cfgpkg.AuthConfig{Type: cfgpkg.AuthClientCredentials, TokenURL: idp.URL, ClientID: "fast"}
+	results := make(chan string, 2)
+	for i := 0; i < 2; i++ {
+		go func() {
+			tok, _ := bearerToken(idp.Client(), slow, "")
+			results <- tok
+		}()
+	}
+	for atomic.LoadInt32(&slowRequests) == 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	done := make(chan string)
+	go func() {
+		tok, _ := bearerToken(idp.Client(), fast, "")
+		done <- tok
+	}()
+	select {
+	case tok := <-done:
+		if tok != "tok-fast" {
+			t.Errorf("expected tok-fast, got %q", tok)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("expected a token request for another key not to wait for the slow one")
+	}
+
+	close(release)
+	for i := 0; i < 2; i++ {
+		if tok := <-results; tok != "tok-slow" {
+			t.Errorf("expected tok-slow for both callers, got %q", tok)
+		}
+	}
+	if n := atomic.LoadInt32(&slowRequests); n != 1 {
+		t.Errorf("expected one token request for concurrent callers of a key, got %d", n)
+	}
+}
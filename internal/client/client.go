@@ -4,9 +4,11 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log"
+	"mime/multipart"
 	"net/http"
 	"net/url"
 	"os"
@@ -363,20 +365,8 @@ func (c *CompatClient) SuspendProcessInstance(instanceID string, suspend bool) e
 	c.logf("API: SuspendProcessInstance(%s, %v)", instanceID, suspend)
 	body := map[string]interface{}{"suspended": suspend}
 	b, _ := json.Marshal(body)
-	urlStr := fmt.Sprintf("%s/process-instance/%s/suspended", strings.TrimRight(c.env.URL, "/"), instanceID)
-	r, err := http.NewRequestWithContext(c.authContext, http.MethodPut, urlStr, bytes.NewReader(b))
-	if err != nil {
-		return err
-	}
-	r.Header.Set("Content-Type", "application/json")
-	resp, err := c.httpClient.Do(r)
-	if err != nil {
-		return err
-	}
-	defer resp.Body.Close()
-	if resp.StatusCode >= 400 {
-		data, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("error suspending instance: %s", string(data))
+	if _, err := c.send(http.MethodPut, fmt.Sprintf("/process-instance/%s/suspended", instanceID), b); err != nil {
+		return fmt.Errorf("error suspending instance: %w", err)
 	}
 	return nil
 }
@@ -386,20 +376,8 @@ func (c *CompatClient) SetJobRetries(jobID string, retries int) error {
 	c.logf("API: SetJobRetries(%s, %d)", jobID, retries)
 	body := map[string]interface{}{"retries": retries}
 	b, _ := json.Marshal(body)
-	urlStr := fmt.Sprintf("%s/job/%s/retries", strings.TrimRight(c.env.URL, "/"), jobID)
-	r, err := http.NewRequestWithContext(c.authContext, http.MethodPut, urlStr, bytes.NewReader(b))
-	if err != nil {
-		return err
-	}
-	r.Header.Set("Content-Type", "application/json")
-	resp, err := c.httpClient.Do(r)
-	if err != nil {
-		return err
-	}
-	defer resp.Body.Close()
-	if resp.StatusCode >= 400 {
-		data, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("error setting job retries: %s", string(data))
+	if _, err := c.send(http.MethodPut, fmt.Sprintf("/job/%s/retries", jobID), b); err != nil {
+		return fmt.Errorf("error setting job retries: %w", err)
 	}
 	return nil
 }
@@ -492,21 +470,12 @@ func (c *CompatClient) AuthContext() context.Context {
 	return c.authContext
 }
 
-// NewClient creates a CompatClient from the environment config.
+// NewClient creates a CompatClient from the environment config on the shared
+// HTTP client of the environment (see HTTPClient).
 // All HTTP requests are always logged (method, URL, body, status, response) to debug/o6n.log.
 // When debug is true, requests are additionally logged to debug/access.log.
 func NewClient(env cfgpkg.Environment, debug bool) *CompatClient {
-	httpClient := &http.Client{Timeout: 10 * time.Second}
-
-	transport := &loggingTransport{base: http.DefaultTransport}
-	if debug {
-		_ = os.MkdirAll("./debug", 0o755)
-		fpath := filepath.Join(".", "debug", "access.log")
-		if f, err := os.OpenFile(fpath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644); err == nil {
-			transport.writer = f
-		}
-	}
-	httpClient.Transport = transport
+	httpClient := sharedHTTPClient(env, true, debug)
 
 	cfg := operaton.NewConfiguration()
 	cfg.HTTPClient = httpClient
@@ -525,11 +494,8 @@ func NewClient(env cfgpkg.Environment, debug bool) *CompatClient {
 
 	apiClient := operaton.NewAPIClient(cfg)
 
-	auth := operaton.BasicAuth{
-		UserName: env.Username,
-		Password: env.Password,
-	}
-	authContext := context.WithValue(context.Background(), operaton.ContextBasicAuth, auth)
+	// Credentials are applied by authTransport, for SDK calls and manual requests alike.
+	authContext := context.Background()
 
 	return &CompatClient{
 		env:         env,
@@ -688,26 +654,8 @@ func (c *CompatClient) TerminateInstance(instanceID string) error {
 // body is an optional JSON string to send as the request body.
 func (c *CompatClient) ExecuteAction(method, path, body string) error {
 	c.logf("API: ExecuteAction(%s %s)", method, path)
-	urlStr := strings.TrimRight(c.env.URL, "/") + path
-	var bodyReader io.Reader
-	if body != "" {
-		bodyReader = strings.NewReader(body)
-	}
-	r, err := http.NewRequestWithContext(c.authContext, method, urlStr, bodyReader)
-	if err != nil {
-		return err
-	}
-	if body != "" {
-		r.Header.Set("Content-Type", "application/json")
-	}
-	resp, err := c.httpClient.Do(r)
-	if err != nil {
-		return err
-	}
-	defer resp.Body.Close()
-	if resp.StatusCode >= 400 {
-		data, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("action failed (%d): %s", resp.StatusCode, string(data))
+	if _, err := c.send(method, path, []byte(body)); err != nil {
+		return fmt.Errorf("action failed: %w", err)
 	}
 	return nil
 }
@@ -727,3 +675,348 @@ func (c *CompatClient) SetProcessInstanceVariable(instanceID, varName string, va
 	}
 	return nil
 }
+
+// Server-side batch operations supported by SubmitBatch. The names are used as
+// the `batch:` value of config actions with `type: batch`.
+const (
+	BatchJobRetries        = "job-retries"
+	BatchSuspendInstances  = "suspend-instances"
+	BatchActivateInstances = "activate-instances"
+	BatchDeleteInstances   = "delete-instances"
+)
+
+// SubmitBatch starts an asynchronous batch that applies operation to every resource
+// matched by params, the GET query parameters of the current table view (e.g.
+// {"withException": "true"}). It returns the id of the created batch.
+func (c *CompatClient) SubmitBatch(operation string, params map[string]string) (string, error) {
+	c.logf("API: SubmitBatch(%s, %v)", operation, params)
+	var batch *operaton.BatchDto
+	var err error
+	switch operation {
+	case BatchJobRetries:
+		var query operaton.JobQueryDto
+		if err := queryDtoFromParams(params, &query); err != nil {
+			return "", err
+		}
+		dto := operaton.NewSetJobRetriesDto()
+		dto.SetRetries(1)
+		dto.SetJobQuery(query)
+		batch, _, err = c.operatonAPI.JobAPI.SetJobRetriesAsyncOperation(c.authContext).SetJobRetriesDto(*dto).Execute()
+	case BatchSuspendInstances, BatchActivateInstances:
+		var query operaton.ProcessInstanceQueryDto
+		if err := queryDtoFromParams(params, &query); err != nil {
+			return "", err
+		}
+		dto := operaton.NewProcessInstanceSuspensionStateAsyncDto()
+		dto.SetSuspended(operation == BatchSuspendInstances)
+		dto.SetProcessInstanceQuery(query)
+		batch, _, err = c.operatonAPI.ProcessInstanceAPI.UpdateSuspensionStateAsyncOperation(c.authContext).ProcessInstanceSuspensionStateAsyncDto(*dto).Execute()
+	case BatchDeleteInstances:
+		var query operaton.ProcessInstanceQueryDto
+		if err := queryDtoFromParams(params, &query); err != nil {
+			return "", err
+		}
+		dto := operaton.NewDeleteProcessInstancesDto()
+		dto.SetDeleteReason("Deleted via o6n batch")
+		dto.SetProcessInstanceQuery(query)
+		batch, _, err = c.operatonAPI.ProcessInstanceAPI.DeleteProcessInstancesAsyncOperation(c.authContext).DeleteProcessInstancesDto(*dto).Execute()
+	default:
+		return "", fmt.Errorf("unknown batch operation %q", operation)
+	}
+	if err != nil {
+		return "", withAPIErrorBody(fmt.Sprintf("failed to submit %s batch", operation), err)
+	}
+	if batch == nil || GetStringValue(batch.Id) == "" {
+		return "", fmt.Errorf("failed to submit %s batch: no batch id in response", operation)
+	}
+	return GetStringValue(batch.Id), nil
+}
+
+// SubmitJobRetriesBatch starts an asynchronous batch (POST /job/retries) that
+// sets the retries of the jobs jobIDs. It returns the id of the created batch.
+func (c *CompatClient) SubmitJobRetriesBatch(jobIDs []string, retries int) (string, error) {
+	c.logf("API: SubmitJobRetriesBatch(%d jobs, %d)", len(jobIDs), retries)
+	dto := operaton.NewSetJobRetriesDto()
+	dto.SetRetries(int32(retries))
+	dto.SetJobIds(jobIDs)
+	batch, _, err := c.operatonAPI.JobAPI.SetJobRetriesAsyncOperation(c.authContext).SetJobRetriesDto(*dto).Execute()
+	if err != nil {
+		return "", withAPIErrorBody("failed to submit job retries batch", err)
+	}
+	if batch == nil || GetStringValue(batch.Id) == "" {
+		return "", fmt.Errorf("failed to submit job retries batch: no batch id in response")
+	}
+	return GetStringValue(batch.Id), nil
+}
+
+// FetchBatchStatistics returns the progress of a running batch. found is false when
+// the engine no longer lists the batch, which happens once all of its jobs completed.
+func (c *CompatClient) FetchBatchStatistics(batchID string) (stats operaton.BatchStatisticsDto, found bool, err error) {
+	c.logf("API: FetchBatchStatistics(%s)", batchID)
+	list, _, err := c.operatonAPI.BatchAPI.GetBatchStatistics(c.authContext).BatchId(batchID).Execute()
+	if err != nil {
+		return stats, false, fmt.Errorf("failed to fetch batch statistics: %w", err)
+	}
+	if len(list) == 0 {
+		return stats, false, nil
+	}
+	return list[0], true, nil
+}
+
+// queryDtoFromParams fills the generated query DTO dst from GET-style string params.
+// Each value is tried as a JSON string first, then as a literal (booleans, numbers)
+// and finally as a comma-separated list, so the DTO's own field types decide the
+// conversion. A param unknown to the DTO is an error: dropping it would widen
+// the query beyond the filter the user confirmed.
+func queryDtoFromParams(params map[string]string, dst interface{}) error {
+	fields := jsonFieldNames(reflect.TypeOf(dst).Elem())
+	for k := range params {
+		if !fields[k] {
+			return fmt.Errorf("filter %s is not supported by the engine query", k)
+		}
+	}
+	for k, v := range params {
+		str, _ := json.Marshal(v)
+		candidates := [][]byte{str}
+		if json.Valid([]byte(v)) {
+			candidates = append(candidates, []byte(v))
+		}
+		list, _ := json.Marshal(strings.Split(v, ","))
+		candidates = append(candidates, list)
+
+		var lastErr error
+		decoded := false
+		for _, cand := range candidates {
+			fragment, _ := json.Marshal(map[string]json.RawMessage{k: cand})
+			if lastErr = json.Unmarshal(fragment, dst); lastErr == nil {
+				decoded = true
+				break
+			}
+		}
+		if !decoded {
+			return fmt.Errorf("invalid value %q for query parameter %s: %w", v, k, lastErr)
+		}
+	}
+	return nil
+}
+
+// jsonFieldNames returns the JSON names of the fields of struct type t.
+func jsonFieldNames(t reflect.Type) map[string]bool {
+	names := make(map[string]bool, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+		if name != "" && name != "-" {
+			names[name] = true
+		}
+	}
+	return names
+}
+
+// withAPIErrorBody wraps err with context and, for errors of the generated client,
+// the engine's response body (which carries the actual reason).
+func withAPIErrorBody(context string, err error) error {
+	var apiErr *operaton.GenericOpenAPIError
+	if errors.As(err, &apiErr) && len(apiErr.Body()) > 0 {
+		return fmt.Errorf("%s: %w: %s", context, err, string(apiErr.Body()))
+	}
+	return fmt.Errorf("%s: %w", context, err)
+}
+
+// FetchProcessDefinitionVersions returns every version of the definition with the
+// given id's key, newest first.
+func (c *CompatClient) FetchProcessDefinitionVersions(definitionID string) ([]operaton.ProcessDefinitionDto, error) {
+	c.logf("API: FetchProcessDefinitionVersions(%s)", definitionID)
+	def, _, err := c.operatonAPI.ProcessDefinitionAPI.GetProcessDefinition(c.authContext, definitionID).Execute()
+	if err != nil {
+		return nil, withAPIErrorBody("failed to fetch process definition", err)
+	}
+	versions, _, err := c.operatonAPI.ProcessDefinitionAPI.GetProcessDefinitions(c.authContext).
+		Key(GetStringValue(def.Key)).SortBy("version").SortOrder("desc").Execute()
+	if err != nil {
+		return nil, withAPIErrorBody("failed to fetch process definition versions", err)
+	}
+	return versions, nil
+}
+
+// GenerateMigrationPlan asks the engine for a migration plan mapping equal activities
+// of the source definition to the target definition.
+func (c *CompatClient) GenerateMigrationPlan(sourceID, targetID string) (*operaton.MigrationPlanDto, error) {
+	c.logf("API: GenerateMigrationPlan(%s -> %s)", sourceID, targetID)
+	dto := operaton.NewMigrationPlanGenerationDto()
+	dto.SetSourceProcessDefinitionId(sourceID)
+	dto.SetTargetProcessDefinitionId(targetID)
+	plan, _, err := c.operatonAPI.MigrationAPI.GenerateMigrationPlan(c.authContext).MigrationPlanGenerationDto(*dto).Execute()
+	if err != nil {
+		return nil, withAPIErrorBody("failed to generate migration plan", err)
+	}
+	return plan, nil
+}
+
+// ValidateMigrationPlan returns the engine's validation report for plan.
+func (c *CompatClient) ValidateMigrationPlan(plan operaton.MigrationPlanDto) (*operaton.MigrationPlanReportDto, error) {
+	c.logf("API: ValidateMigrationPlan(%d instructions)", len(plan.Instructions))
+	report, _, err := c.operatonAPI.MigrationAPI.ValidateMigrationPlan(c.authContext).MigrationPlanDto(plan).Execute()
+	if err != nil {
+		return nil, withAPIErrorBody("failed to validate migration plan", err)
+	}
+	return report, nil
+}
+
+// ValidateInstanceQuery reports an error if params cannot be sent as a process
+// instance query, e.g. because a filter is unknown to the engine query.
+func ValidateInstanceQuery(params map[string]string) error {
+	var query operaton.ProcessInstanceQueryDto
+	return queryDtoFromParams(params, &query)
+}
+
+// ExecuteMigration migrates the instances given by instanceIDs or, when empty, by the
+// process instance query params. Async executions run as a batch whose id is
+// returned; synchronous executions return "".
+func (c *CompatClient) ExecuteMigration(plan operaton.MigrationPlanDto, instanceIDs []string, params map[string]string, async bool) (string, error) {
+	c.logf("API: ExecuteMigration(%d ids, %v, async=%v)", len(instanceIDs), params, async)
+	dto := operaton.NewMigrationExecutionDto()
+	dto.SetMigrationPlan(plan)
+	if len(instanceIDs) > 0 {
+		dto.SetProcessInstanceIds(instanceIDs)
+	} else {
+		var query operaton.ProcessInstanceQueryDto
+		if err := queryDtoFromParams(params, &query); err != nil {
+			return "", err
+		}
+		dto.SetProcessInstanceQuery(query)
+	}
+	if !async {
+		if _, err := c.operatonAPI.MigrationAPI.ExecuteMigrationPlan(c.authContext).MigrationExecutionDto(*dto).Execute(); err != nil {
+			return "", withAPIErrorBody("failed to execute migration", err)
+		}
+		return "", nil
+	}
+	batch, _, err := c.operatonAPI.MigrationAPI.ExecuteMigrationPlanAsync(c.authContext).MigrationExecutionDto(*dto).Execute()
+	if err != nil {
+		return "", withAPIErrorBody("failed to submit migration batch", err)
+	}
+	if batch == nil || GetStringValue(batch.Id) == "" {
+		return "", fmt.Errorf("failed to submit migration batch: no batch id in response")
+	}
+	return GetStringValue(batch.Id), nil
+}
+
+// DeployOptions are the form fields sent with CreateDeployment.
+type DeployOptions struct {
+	Name                     string
+	TenantID                 string
+	Source                   string
+	EnableDuplicateFiltering bool
+	DeployChangedOnly        bool
+}
+
+// deployableExtensions are the resource types collected from directories.
+var deployableExtensions = []string{".bpmn", ".bpmn20.xml", ".dmn", ".dmn11.xml", ".cmmn", ".cmmn11.xml", ".form"}
+
+// isDeployable reports whether name has a deployable resource extension.
+func isDeployable(name string) bool {
+	lower := strings.ToLower(name)
+	for _, ext := range deployableExtensions {
+		if strings.HasSuffix(lower, ext) {
+			return true
+		}
+	}
+	return false
+}
+
+// CollectDeploymentFiles expands paths to the files of a deployment. Files are taken
+// as given; directories contribute their BPMN, DMN, CMMN and form files recursively.
+// Resource names (the file base names) must be unique within a deployment.
+func CollectDeploymentFiles(paths []string) ([]string, error) {
+	var files []string
+	seen := make(map[string]string)
+	add := func(p string) error {
+		name := filepath.Base(p)
+		if prev, ok := seen[name]; ok {
+			return fmt.Errorf("duplicate resource name %q (%s and %s)", name, prev, p)
+		}
+		seen[name] = p
+		files = append(files, p)
+		return nil
+	}
+	for _, p := range paths {
+		info, err := os.Stat(p)
+		if err != nil {
+			return nil, err
+		}
+		if !info.IsDir() {
+			if err := add(p); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		err = filepath.WalkDir(p, func(path string, d os.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if d.IsDir() || !isDeployable(d.Name()) {
+				return nil
+			}
+			return add(path)
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	if len(files) == 0 {
+		return nil, errors.New("no deployable files found (.bpmn, .dmn, .cmmn, .form)")
+	}
+	return files, nil
+}
+
+// CreateDeployment deploys files as one deployment via the multipart
+// POST /deployment/create. The generated client only supports a single file, so the
+// request is built here.
+func (c *CompatClient) CreateDeployment(files []string, opts DeployOptions) (*operaton.DeploymentWithDefinitionsDto, error) {
+	c.logf("API: CreateDeployment(%s, %d files)", opts.Name, len(files))
+	var body bytes.Buffer
+	w := multipart.NewWriter(&body)
+	fields := [][2]string{
+		{"deployment-name", opts.Name},
+		{"tenant-id", opts.TenantID},
+		{"deployment-source", opts.Source},
+		{"enable-duplicate-filtering", strconv.FormatBool(opts.EnableDuplicateFiltering)},
+		{"deploy-changed-only", strconv.FormatBool(opts.DeployChangedOnly)},
+	}
+	for _, f := range fields {
+		if f[1] == "" {
+			continue
+		}
+		if err := w.WriteField(f[0], f[1]); err != nil {
+			return nil, err
+		}
+	}
+	for _, path := range files {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		name := filepath.Base(path)
+		part, err := w.CreateFormFile(name, name)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := part.Write(data); err != nil {
+			return nil, err
+		}
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+
+	urlStr := strings.TrimRight(c.env.URL, "/") + "/deployment/create"
+	data, err := send(c.authContext, c.httpClient, http.MethodPost, urlStr, body.Bytes(), w.FormDataContentType(), "application/json")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create deployment: %w", err)
+	}
+	var dep operaton.DeploymentWithDefinitionsDto
+	if err := json.Unmarshal(data, &dep); err != nil {
+		return nil, fmt.Errorf("failed to decode deployment: %w", err)
+	}
+	return &dep, nil
+}
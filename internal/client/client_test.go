@@ -5,8 +5,12 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
+	cfgpkg "github.com/kthoms/o6n/internal/config"
 	operaton "github.com/kthoms/o6n/internal/operaton"
 )
 
@@ -87,3 +91,225 @@ func TestFetchInstancesWithParams_GeneratedClient(t *testing.T) {
 		t.Fatalf("unexpected id: %v", out[0]["id"])
 	}
 }
+
+func TestQueryDtoFromParamsUsesDtoFieldTypes(t *testing.T) {
+	var q operaton.JobQueryDto
+	params := map[string]string{
+		"withException":              "true",
+		"processDefinitionKey":       "invoice",
+		"priorityHigherThanOrEquals": "5",
+		"tenantIdIn":                 "t1,t2",
+	}
+	if err := queryDtoFromParams(params, &q); err != nil {
+		t.Fatalf("queryDtoFromParams: %v", err)
+	}
+	if !q.GetWithException() || q.GetProcessDefinitionKey() != "invoice" || q.GetPriorityHigherThanOrEquals() != 5 {
+		t.Errorf("unexpected scalar fields: %+v", q)
+	}
+	if len(q.TenantIdIn) != 2 || q.TenantIdIn[1] != "t2" {
+		t.Errorf("expected tenantIdIn split into a list, got %v", q.TenantIdIn)
+	}
+
+	if err := queryDtoFromParams(map[string]string{"withException": "maybe"}, &q); err == nil {
+		t.Error("expected error for a non-boolean value of a boolean field")
+	}
+	if err := queryDtoFromParams(map[string]string{"notAQueryField": "x"}, &q); err == nil {
+		t.Error("expected error for a param unknown to the DTO")
+	}
+}
+
+func TestSubmitBatchRejectsUnknownFilter(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("no request expected, got %s %s", r.Method, r.URL.Path)
+	}))
+	defer srv.Close()
+
+	c := NewClient(cfgpkg.Environment{URL: srv.URL}, false)
+	// A typo must not widen "Delete All Matching" to every instance with the business key
+	_, err := c.SubmitBatch(BatchDeleteInstances, map[string]string{"processDefinitionKeyy": "invoice", "businessKeyLike": "%x%"})
+	if err == nil || !strings.Contains(err.Error(), "processDefinitionKeyy") {
+		t.Errorf("expected an error naming the unknown filter, got %v", err)
+	}
+}
+
+func TestSubmitBatchPostsQueryAndReturnsBatchID(t *testing.T) {
+	var got map[string]interface{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/process-instance/suspended-async" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		_ = json.NewDecoder(r.Body).Decode(&got)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":"batch-1","type":"process-set-suspension-state","totalJobs":3}`))
+	}))
+	defer srv.Close()
+
+	c := NewClient(cfgpkg.Environment{URL: srv.URL}, false)
+	id, err := c.SubmitBatch(BatchSuspendInstances, map[string]string{"processDefinitionKey": "invoice", "active": "true"})
+	if err != nil {
+		t.Fatalf("SubmitBatch: %v", err)
+	}
+	if id != "batch-1" {
+		t.Errorf("expected batch-1, got %q", id)
+	}
+	if got["suspended"] != true {
+		t.Errorf("expected suspended=true in body, got %v", got)
+	}
+	query, _ := got["processInstanceQuery"].(map[string]interface{})
+	if query["processDefinitionKey"] != "invoice" || query["active"] != true {
+		t.Errorf("expected filter as typed query, got %v", got["processInstanceQuery"])
+	}
+}
+
+func TestSubmitJobRetriesBatchPostsJobIDs(t *testing.T) {
+	var got map[string]interface{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/job/retries" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		_ = json.NewDecoder(r.Body).Decode(&got)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":"batch-2","type":"set-job-retries","totalJobs":2}`))
+	}))
+	defer srv.Close()
+
+	c := NewClient(cfgpkg.Environment{URL: srv.URL}, false)
+	id, err := c.SubmitJobRetriesBatch([]string{"j1", "j2"}, 3)
+	if err != nil {
+		t.Fatalf("SubmitJobRetriesBatch: %v", err)
+	}
+	if id != "batch-2" {
+		t.Errorf("expected batch-2, got %q", id)
+	}
+	ids, _ := got["jobIds"].([]interface{})
+	if len(ids) != 2 || ids[0] != "j1" || got["retries"] != float64(3) {
+		t.Errorf("expected job ids and retries in body, got %v", got)
+	}
+	if _, ok := got["jobQuery"]; ok {
+		t.Errorf("expected no job query, got %v", got["jobQuery"])
+	}
+}
+
+func TestSubmitBatchUnknownOperation(t *testing.T) {
+	c := NewClient(cfgpkg.Environment{URL: "http://localhost:0"}, false)
+	if _, err := c.SubmitBatch("frobnicate", nil); err == nil {
+		t.Error("expected error for unknown batch operation")
+	}
+}
+
+func TestExecuteMigrationAsyncUsesQueryWithoutIDs(t *testing.T) {
+	var got map[string]interface{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/migration/executeAsync" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		_ = json.NewDecoder(r.Body).Decode(&got)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":"batch-9","type":"instance-migration"}`))
+	}))
+	defer srv.Close()
+
+	c := NewClient(cfgpkg.Environment{URL: srv.URL}, false)
+	plan := operaton.MigrationPlanDto{Instructions: []operaton.MigrationInstructionDto{
+		{SourceActivityIds: []string{"review"}, TargetActivityIds: []string{"approve"}},
+	}}
+	plan.SetSourceProcessDefinitionId("invoice:1")
+	plan.SetTargetProcessDefinitionId("invoice:2")
+	id, err := c.ExecuteMigration(plan, nil, map[string]string{"processDefinitionId": "invoice:1", "suspended": "false"}, true)
+	if err != nil {
+		t.Fatalf("ExecuteMigration: %v", err)
+	}
+	if id != "batch-9" {
+		t.Errorf("expected batch-9, got %q", id)
+	}
+	if _, ok := got["processInstanceIds"]; ok {
+		t.Errorf("expected no explicit ids, got %v", got["processInstanceIds"])
+	}
+	query, _ := got["processInstanceQuery"].(map[string]interface{})
+	if query["processDefinitionId"] != "invoice:1" || query["suspended"] != false {
+		t.Errorf("expected typed instance query, got %v", got["processInstanceQuery"])
+	}
+	mp, _ := got["migrationPlan"].(map[string]interface{})
+	if mp["targetProcessDefinitionId"] != "invoice:2" {
+		t.Errorf("expected plan in body, got %v", got["migrationPlan"])
+	}
+}
+
+func TestExecuteMigrationRejectsUnknownFilter(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("no request expected, got %s %s", r.Method, r.URL.Path)
+	}))
+	defer srv.Close()
+
+	c := NewClient(cfgpkg.Environment{URL: srv.URL}, false)
+	params := map[string]string{"processDefinitionId": "invoice:1", "businesKeyLike": "%x%"}
+	if _, err := c.ExecuteMigration(operaton.MigrationPlanDto{}, nil, params, false); err == nil {
+		t.Error("expected the migration to fail on an unknown filter")
+	}
+	if err := ValidateInstanceQuery(params); err == nil || !strings.Contains(err.Error(), "businesKeyLike") {
+		t.Errorf("expected ValidateInstanceQuery to name the unknown filter, got %v", err)
+	}
+}
+
+func TestCollectDeploymentFilesWalksDirectoriesAndRejectsDuplicates(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"invoice.bpmn", "rules.dmn", "notes.txt", "sub/approve.form"} {
+		p := filepath.Join(dir, name)
+		_ = os.MkdirAll(filepath.Dir(p), 0o755)
+		if err := os.WriteFile(p, []byte("x"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	files, err := CollectDeploymentFiles([]string{dir})
+	if err != nil {
+		t.Fatalf("CollectDeploymentFiles: %v", err)
+	}
+	if len(files) != 3 {
+		t.Errorf("expected bpmn, dmn and form files, got %v", files)
+	}
+	if _, err := CollectDeploymentFiles([]string{dir, filepath.Join(dir, "invoice.bpmn")}); err == nil || !strings.Contains(err.Error(), "duplicate") {
+		t.Errorf("expected duplicate resource name error, got %v", err)
+	}
+	if _, err := CollectDeploymentFiles([]string{filepath.Join(dir, "sub", "missing")}); err == nil {
+		t.Error("expected error for a missing path")
+	}
+}
+
+func TestCreateDeploymentSendsMultipartForm(t *testing.T) {
+	dir := t.TempDir()
+	bpmn := filepath.Join(dir, "invoice.bpmn")
+	_ = os.WriteFile(bpmn, []byte("<definitions/>"), 0o644)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/deployment/create" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		if err := r.ParseMultipartForm(1 << 20); err != nil {
+			t.Fatalf("parse multipart: %v", err)
+		}
+		if got := r.FormValue("deployment-name"); got != "hotfix" {
+			t.Errorf("expected deployment-name hotfix, got %q", got)
+		}
+		if r.FormValue("enable-duplicate-filtering") != "true" || r.FormValue("deploy-changed-only") != "false" {
+			t.Errorf("unexpected flags %v", r.MultipartForm.Value)
+		}
+		if _, ok := r.MultipartForm.Value["tenant-id"]; ok {
+			t.Error("expected no tenant-id field when unset")
+		}
+		if fh := r.MultipartForm.File["invoice.bpmn"]; len(fh) != 1 || fh[0].Filename != "invoice.bpmn" {
+			t.Errorf("expected invoice.bpmn file part, got %v", r.MultipartForm.File)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":"dep-1","name":"hotfix","deployedProcessDefinitions":{"invoice:2:x":{"id":"invoice:2:x","key":"invoice","version":2}}}`))
+	}))
+	defer srv.Close()
+
+	c := NewClient(cfgpkg.Environment{URL: srv.URL}, false)
+	dep, err := c.CreateDeployment([]string{bpmn}, DeployOptions{Name: "hotfix", EnableDuplicateFiltering: true})
+	if err != nil {
+		t.Fatalf("CreateDeployment: %v", err)
+	}
+	if GetStringValue(dep.Id) != "dep-1" || len(dep.DeployedProcessDefinitions) != 1 {
+		t.Errorf("unexpected deployment %+v", dep)
+	}
+}
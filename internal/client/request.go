@@ -0,0 +1,315 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	cfgpkg "github.com/kthoms/o6n/internal/config"
+	operaton "github.com/kthoms/o6n/internal/operaton"
+)
+
+// Request layer settings. Every client of an environment (the generated API
+// client, CompatClient helpers and Do/GetJSON) shares them.
+var (
+	// MaxRetries is how often a GET, HEAD or OPTIONS request is retried after a
+	// network error or a 429/502/503/504 response. PUT and DELETE are only
+	// retried when the connection could not be made.
+	MaxRetries = 2
+	// RetryBackoff is the delay before the first retry; it doubles per attempt.
+	RetryBackoff = 300 * time.Millisecond
+	// MaxConcurrentRequests bounds the in-flight requests per environment.
+	MaxConcurrentRequests = 6
+)
+
+// maxRetryAfter caps a server-requested Retry-After delay.
+const maxRetryAfter = 5 * time.Second
+
+// APIError is a request the engine answered with an HTTP error status. Type and
+// Message are taken from the engine's exception body when present.
+type APIError struct {
+	Method     string
+	URL        string
+	StatusCode int
+	Type       string // engine exception type, e.g. "InvalidRequestException"
+	Message    string
+	Body       string
+}
+
+func (e *APIError) Error() string {
+	detail := e.Message
+	if detail == "" {
+		detail = strings.TrimSpace(e.Body)
+	}
+	if detail == "" {
+		detail = http.StatusText(e.StatusCode)
+	}
+	if e.URL == "" {
+		return fmt.Sprintf("HTTP %d: %s", e.StatusCode, detail)
+	}
+	return fmt.Sprintf("%s %s: HTTP %d: %s", e.Method, e.URL, e.StatusCode, detail)
+}
+
+// RequestError is a request that got no response: connection, TLS, timeout or
+// authentication failures.
+type RequestError struct {
+	Method string
+	URL    string
+	Err    error
+}
+
+func (e *RequestError) Error() string {
+	return fmt.Sprintf("%s %s: %v", e.Method, e.URL, e.Err)
+}
+
+func (e *RequestError) Unwrap() error { return e.Err }
+
+// newAPIError reads the body of an error response.
+func newAPIError(method, rawURL string, status int, body []byte) *APIError {
+	e := &APIError{Method: method, URL: rawURL, StatusCode: status, Body: string(body)}
+	var exc struct {
+		Type    string `json:"type"`
+		Message string `json:"message"`
+	}
+	if json.Unmarshal(body, &exc) == nil {
+		e.Type, e.Message = exc.Type, exc.Message
+	}
+	return e
+}
+
+// AsAPIError returns the HTTP error status behind err: an *APIError from this
+// package or an error of the generated API client.
+func AsAPIError(err error) (*APIError, bool) {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr, true
+	}
+	var genErr *operaton.GenericOpenAPIError
+	if errors.As(err, &genErr) {
+		// the generated client reports the status line, e.g. "404 Not Found"
+		code, _, _ := strings.Cut(genErr.Error(), " ")
+		if status, convErr := strconv.Atoi(code); convErr == nil {
+			return newAPIError("", "", status, genErr.Body()), true
+		}
+		if len(genErr.Body()) > 0 {
+			return newAPIError("", "", 0, genErr.Body()), true
+		}
+	}
+	return nil, false
+}
+
+// Do sends a request through the shared client of env and returns the response
+// body. body is sent as JSON when non-empty. HTTP error statuses are returned as
+// *APIError, requests without a response as *RequestError; cancelling ctx
+// aborts the request.
+func Do(ctx context.Context, env cfgpkg.Environment, method, rawURL string, body []byte) ([]byte, error) {
+	return send(ctx, HTTPClient(env), method, rawURL, body, "application/json", "application/json")
+}
+
+// GetText GETs rawURL from env as plain text, e.g. a stacktrace.
+func GetText(ctx context.Context, env cfgpkg.Environment, rawURL string) (string, error) {
+	data, err := send(ctx, HTTPClient(env), http.MethodGet, rawURL, nil, "", "text/plain, */*")
+	return string(data), err
+}
+
+// GetJSON GETs rawURL from env and decodes the JSON response into v.
+func GetJSON(ctx context.Context, env cfgpkg.Environment, rawURL string, v any) error {
+	data, err := Do(ctx, env, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("GET %s: decode: %w", rawURL, err)
+	}
+	return nil
+}
+
+// send performs one request on hc accepting the media types accept; see Do.
+func send(ctx context.Context, hc *http.Client, method, rawURL string, body []byte, contentType, accept string) ([]byte, error) {
+	var reader io.Reader
+	if len(body) > 0 {
+		reader = bytes.NewReader(body)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, rawURL, reader)
+	if err != nil {
+		return nil, &RequestError{Method: method, URL: rawURL, Err: err}
+	}
+	req.Header.Set("Accept", accept)
+	if len(body) > 0 {
+		req.Header.Set("Content-Type", contentType)
+	}
+	resp, err := hc.Do(req)
+	if err != nil {
+		var ue *url.Error
+		if errors.As(err, &ue) {
+			err = ue.Err // the URL is part of RequestError already
+		}
+		return nil, &RequestError{Method: method, URL: rawURL, Err: err}
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, &RequestError{Method: method, URL: rawURL, Err: err}
+	}
+	if resp.StatusCode >= 300 {
+		return nil, newAPIError(method, rawURL, resp.StatusCode, data)
+	}
+	return data, nil
+}
+
+// WithContext returns a copy of c whose requests are cancelled with ctx.
+func (c *CompatClient) WithContext(ctx context.Context) *CompatClient {
+	cc := *c
+	cc.authContext = ctx
+	return &cc
+}
+
+// send performs a request relative to the environment URL; see Do.
+func (c *CompatClient) send(method, path string, body []byte) ([]byte, error) {
+	return send(c.authContext, c.httpClient, method, strings.TrimRight(c.env.URL, "/")+path, body, "application/json", "application/json")
+}
+
+// safeMethod reports whether a request changes nothing on the engine, so it
+// may be sent again after any failure.
+func safeMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return true
+	}
+	return false
+}
+
+// idempotent reports whether a request may be sent again once it is known not
+// to have reached the engine. A PUT or DELETE that may have been applied is
+// not resent: the engine could have acted on it before the failure, and e.g. a
+// retried DELETE would then fail with 404.
+func idempotent(method string) bool {
+	return safeMethod(method) || method == http.MethodPut || method == http.MethodDelete
+}
+
+// notSent reports whether err means the request never left the client because
+// no connection could be made, e.g. connection refused.
+func notSent(err error) bool {
+	var opErr *net.OpError
+	return errors.As(err, &opErr) && opErr.Op == "dial"
+}
+
+func retryableStatus(code int) bool {
+	switch code {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// retryTransport retries safe requests, and idempotent ones that were never
+// sent, with exponential backoff. The backoff is aborted when the request's
+// context is cancelled.
+type retryTransport struct {
+	base http.RoundTripper
+}
+
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	replayable := req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+	if !idempotent(req.Method) || !replayable {
+		return t.base.RoundTrip(req)
+	}
+	delay := RetryBackoff
+	for attempt := 0; ; attempt++ {
+		r := req
+		if attempt > 0 && req.GetBody != nil {
+			r = req.Clone(req.Context())
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			r.Body = body
+		}
+		resp, err := t.base.RoundTrip(r)
+		if attempt >= MaxRetries || req.Context().Err() != nil {
+			return resp, err
+		}
+		wait := delay
+		switch {
+		case err != nil:
+			var certErr *tls.CertificateVerificationError
+			if errors.Is(err, context.Canceled) || errors.As(err, &certErr) {
+				return resp, err
+			}
+			if !safeMethod(req.Method) && !notSent(err) {
+				return resp, err
+			}
+		case !safeMethod(req.Method):
+			return resp, nil
+		case retryableStatus(resp.StatusCode):
+			if s, convErr := strconv.Atoi(resp.Header.Get("Retry-After")); convErr == nil && s >= 0 {
+				wait = min(time.Duration(s)*time.Second, maxRetryAfter)
+			}
+			_, _ = io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		default:
+			return resp, nil
+		}
+		timer := time.NewTimer(wait)
+		select {
+		case <-req.Context().Done():
+			timer.Stop()
+			return nil, req.Context().Err()
+		case <-timer.C:
+		}
+		delay *= 2
+	}
+}
+
+// limitTransport bounds the concurrent requests of an environment. A slot is
+// held until the response body is read or closed.
+type limitTransport struct {
+	base http.RoundTripper
+	sem  chan struct{}
+}
+
+func (t *limitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	select {
+	case t.sem <- struct{}{}:
+	case <-req.Context().Done():
+		return nil, req.Context().Err()
+	}
+	var once sync.Once
+	release := func() { once.Do(func() { <-t.sem }) }
+	resp, err := t.base.RoundTrip(req)
+	if err != nil || resp.Body == nil {
+		release()
+		return resp, err
+	}
+	resp.Body = &releaseBody{ReadCloser: resp.Body, release: release}
+	return resp, nil
+}
+
+type releaseBody struct {
+	io.ReadCloser
+	release func()
+}
+
+func (b *releaseBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	if err == io.EOF {
+		b.release()
+	}
+	return n, err
+}
+
+func (b *releaseBody) Close() error {
+	b.release()
+	return b.ReadCloser.Close()
+}
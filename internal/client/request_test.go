@@ -0,0 +1,147 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	cfgpkg "github.com/kthoms/o6n/internal/config"
+)
+
+func TestDoRetriesIdempotentRequestsAndReportsAPIError(t *testing.T) {
+	saved := RetryBackoff
+	RetryBackoff = time.Millisecond
+	t.Cleanup(func() { RetryBackoff = saved })
+
+	var gets, posts int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			atomic.AddInt32(&posts, 1)
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		if atomic.AddInt32(&gets, 1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = io.WriteString(w, `{"type":"InvalidRequestException","message":"Process instance p1 not found"}`)
+	}))
+	defer srv.Close()
+	env := cfgpkg.Environment{URL: srv.URL}
+
+	_, err := Do(context.Background(), env, http.MethodGet, srv.URL+"/process-instance/p1", nil)
+	apiErr, ok := AsAPIError(err)
+	if !ok || apiErr.StatusCode != http.StatusNotFound || apiErr.Type != "InvalidRequestException" ||
+		apiErr.Message != "Process instance p1 not found" || gets != 3 {
+		t.Fatalf("expected a 404 APIError after two retries, got %v after %d GETs", err, gets)
+	}
+
+	if _, err := Do(context.Background(), env, http.MethodPost, srv.URL+"/message", []byte(`{}`)); err == nil || posts != 1 {
+		t.Errorf("expected a single POST attempt, got %v after %d POSTs", err, posts)
+	}
+}
+
+// roundTripFunc adapts a function to http.RoundTripper.
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(r *http.Request) (*http.Response, error) { return f(r) }
+
+func TestRetryResendsChangesOnlyWhenTheyWereNeverSent(t *testing.T) {
+	saved := RetryBackoff
+	RetryBackoff = time.Millisecond
+	t.Cleanup(func() { RetryBackoff = saved })
+
+	refused := &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")}
+	cases := []struct {
+		method string
+		err    error
+		status int
+		want   int32
+	}{
+		{http.MethodDelete, refused, 0, 3},
+		{http.MethodDelete, io.ErrUnexpectedEOF, 0, 1},
+		{http.MethodPut, nil, http.StatusServiceUnavailable, 1},
+		{http.MethodGet, io.ErrUnexpectedEOF, 0, 3},
+		{http.MethodGet, nil, http.StatusServiceUnavailable, 3},
+	}
+	for _, c := range cases {
+		var attempts int32
+		rt := &retryTransport{base: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+			atomic.AddInt32(&attempts, 1)
+			if c.err != nil {
+				return nil, c.err
+			}
+			return &http.Response{StatusCode: c.status, Body: http.NoBody, Header: http.Header{}}, nil
+		})}
+		req, _ := http.NewRequest(c.method, "http://engine/job/j1", nil)
+		resp, _ := rt.RoundTrip(req)
+		if resp != nil {
+			resp.Body.Close()
+		}
+		if attempts != c.want {
+			t.Errorf("%s after %v/%d: expected %d attempts, got %d", c.method, c.err, c.status, c.want, attempts)
+		}
+	}
+}
+
+func TestRequestsAreLimitedPerEnvironmentAndCancellable(t *testing.T) {
+	saved := MaxConcurrentRequests
+	MaxConcurrentRequests = 2
+	t.Cleanup(func() { MaxConcurrentRequests = saved })
+
+	var inFlight, peak int32
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&inFlight, 1)
+		for {
+			p := atomic.LoadInt32(&peak)
+			if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+				break
+			}
+		}
+		<-release
+		atomic.AddInt32(&inFlight, -1)
+		_, _ = io.WriteString(w, "[]")
+	}))
+	defer srv.Close()
+	env := cfgpkg.Environment{URL: srv.URL}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var out []map[string]any
+			if err := GetJSON(context.Background(), env, srv.URL+"/job", &out); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+
+	// A request waiting for a slot is aborted by cancelling its context
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		_, err := Do(ctx, env, http.MethodGet, srv.URL+"/job", nil)
+		done <- err
+	}()
+	time.Sleep(50 * time.Millisecond)
+	cancel()
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Errorf("expected a cancelled request, got %v", err)
+	}
+
+	close(release)
+	wg.Wait()
+	if peak != 2 {
+		t.Errorf("expected at most 2 concurrent requests, got %d", peak)
+	}
+}
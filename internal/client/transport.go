@@ -0,0 +1,193 @@
+package client
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	cfgpkg "github.com/kthoms/o6n/internal/config"
+)
+
+var (
+	sharedMu         sync.Mutex
+	sharedClients    = map[string]*http.Client{}
+	sharedTransports = map[string]*envTransport{}
+)
+
+// envTransport is the connection pool and request limit of one environment.
+type envTransport struct {
+	base http.RoundTripper
+	sem  chan struct{}
+}
+
+// HTTPClient returns the shared HTTP client of env: its timeout, TLS and proxy
+// settings, extra headers and authentication. Clients are built once per distinct
+// environment configuration so connections are pooled across commands.
+func HTTPClient(env cfgpkg.Environment) *http.Client {
+	return sharedHTTPClient(env, false, false)
+}
+
+// sharedHTTPClient is HTTPClient with optional request logging: logged writes
+// every request to the standard logger (o6n.log), debug additionally to
+// access.log. All variants of an environment share one connection pool and
+// concurrency limit.
+func sharedHTTPClient(env cfgpkg.Environment, logged, debug bool) *http.Client {
+	key := transportKey(env)
+	clientKey := fmt.Sprintf("%t|%t|%s", logged, debug, key)
+	sharedMu.Lock()
+	defer sharedMu.Unlock()
+	if c, ok := sharedClients[clientKey]; ok {
+		return c
+	}
+
+	et, ok := sharedTransports[key]
+	if !ok {
+		et = &envTransport{sem: make(chan struct{}, max(MaxConcurrentRequests, 1))}
+		if tr, err := newTransport(env); err != nil {
+			et.base = errTransport{err}
+		} else {
+			et.base = tr
+		}
+		sharedTransports[key] = et
+	}
+	base := et.base
+	rt := base
+	if logged {
+		logging := &loggingTransport{base: base}
+		if debug {
+			_ = os.MkdirAll("./debug", 0o755)
+			fpath := filepath.Join(".", "debug", "access.log")
+			if f, err := os.OpenFile(fpath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644); err == nil {
+				logging.writer = f
+			}
+		}
+		rt = logging
+	}
+	if _, broken := base.(errTransport); !broken {
+		rt = &retryTransport{base: &limitTransport{base: rt, sem: et.sem}}
+	}
+	if len(env.Headers) > 0 {
+		rt = &headerTransport{base: rt, headers: env.Headers}
+	}
+	c := &http.Client{
+		Timeout: env.RequestTimeout(),
+		Transport: &authTransport{
+			base:        rt,
+			env:         env,
+			tokenClient: &http.Client{Timeout: env.RequestTimeout(), Transport: base},
+		},
+	}
+	if env.ReadOnly() {
+		c.Transport = &readOnlyTransport{base: c.Transport}
+	}
+	c.Transport = &auditTransport{base: c.Transport, env: env}
+	sharedClients[clientKey] = c
+	return c
+}
+
+// transportKey identifies an environment configuration; environments that differ
+// in any setting (including credentials) get their own client.
+func transportKey(env cfgpkg.Environment) string {
+	data, _ := json.Marshal(env) // maps are marshalled in key order
+	return string(data)
+}
+
+// newTransport builds the TLS and proxy settings of env on a clone of the
+// default transport.
+func newTransport(env cfgpkg.Environment) (*http.Transport, error) {
+	tr := http.DefaultTransport.(*http.Transport).Clone()
+
+	switch strings.ToLower(strings.TrimSpace(env.Proxy)) {
+	case "":
+		// HTTP_PROXY / HTTPS_PROXY / NO_PROXY from the default transport
+	case "direct", "none":
+		tr.Proxy = nil
+	default:
+		u, err := url.Parse(env.Proxy)
+		if err != nil || u.Host == "" {
+			return nil, fmt.Errorf("invalid proxy %q", env.Proxy)
+		}
+		tr.Proxy = http.ProxyURL(u)
+	}
+
+	if env.CAFile == "" && env.CertFile == "" && env.KeyFile == "" && !env.InsecureSkipVerify {
+		return tr, nil
+	}
+	tlsCfg := &tls.Config{MinVersion: tls.VersionTLS12, InsecureSkipVerify: env.InsecureSkipVerify} // #nosec G402 -- explicit opt-in per environment
+	if env.CAFile != "" {
+		pem, err := os.ReadFile(env.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("ca_file: %w", err)
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil || pool == nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("ca_file %s: no PEM certificates found", env.CAFile)
+		}
+		tlsCfg.RootCAs = pool
+	}
+	if env.CertFile != "" || env.KeyFile != "" {
+		if env.CertFile == "" || env.KeyFile == "" {
+			return nil, fmt.Errorf("cert_file and key_file must be set together")
+		}
+		cert, err := tls.LoadX509KeyPair(env.CertFile, env.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("client certificate: %w", err)
+		}
+		tlsCfg.Certificates = []tls.Certificate{cert}
+	}
+	tr.TLSClientConfig = tlsCfg
+	return tr, nil
+}
+
+// errTransport fails every request with a configuration error, so a broken
+// ca_file or proxy surfaces where the request is made.
+type errTransport struct{ err error }
+
+func (t errTransport) RoundTrip(*http.Request) (*http.Response, error) {
+	return nil, fmt.Errorf("environment transport: %w", t.err)
+}
+
+// headerTransport adds the environment's extra headers to every request.
+type headerTransport struct {
+	base    http.RoundTripper
+	headers map[string]string
+}
+
+func (t *headerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	r := req.Clone(req.Context())
+	for k, v := range t.headers {
+		r.Header.Set(k, v)
+	}
+	return t.base.RoundTrip(r)
+}
+
+// ErrReadOnly is the error of a request that would change a read-only environment.
+var ErrReadOnly = errors.New("environment is read-only")
+
+// readOnlyTransport refuses every request except GET, HEAD and OPTIONS, as the
+// last line of defence behind the hidden and blocked actions of the UI.
+type readOnlyTransport struct {
+	base http.RoundTripper
+}
+
+func (t *readOnlyTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return t.base.RoundTrip(req)
+	}
+	if req.Body != nil {
+		req.Body.Close()
+	}
+	return nil, fmt.Errorf("%s %s: %w", req.Method, req.URL.Path, ErrReadOnly)
+}
@@ -0,0 +1,105 @@
+package client
+
+import (
+	"context"
+	"encoding/pem"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	cfgpkg "github.com/kthoms/o6n/internal/config"
+)
+
+func TestHTTPClientTrustsCAFileAndInsecureSkipVerify(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, "ok")
+	}))
+	defer srv.Close()
+
+	if _, err := HTTPClient(cfgpkg.Environment{URL: srv.URL}).Get(srv.URL); err == nil || !strings.Contains(err.Error(), "certificate") {
+		t.Fatalf("expected a certificate error without ca_file, got %v", err)
+	}
+
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	caPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: srv.Certificate().Raw})
+	if err := os.WriteFile(caFile, caPEM, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	for name, env := range map[string]cfgpkg.Environment{
+		"ca_file":              {URL: srv.URL, CAFile: caFile},
+		"insecure_skip_verify": {URL: srv.URL, InsecureSkipVerify: true},
+	} {
+		resp, err := HTTPClient(env).Get(srv.URL)
+		if err != nil {
+			t.Errorf("%s: %v", name, err)
+			continue
+		}
+		resp.Body.Close()
+	}
+
+	bad := cfgpkg.Environment{URL: srv.URL, CertFile: caFile}
+	if _, err := HTTPClient(bad).Get(srv.URL); err == nil || !strings.Contains(err.Error(), "cert_file and key_file") {
+		t.Errorf("expected a client certificate configuration error, got %v", err)
+	}
+}
+
+func TestHTTPClientHeadersProxyAndTimeout(t *testing.T) {
+	var proxied string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxied = r.URL.String()
+		_, _ = io.WriteString(w, r.Header.Get("X-Tenant"))
+	}))
+	defer proxy.Close()
+
+	env := cfgpkg.Environment{URL: "http://operaton.internal", Proxy: proxy.URL, Headers: map[string]string{"X-Tenant": "acme"}}
+	c := HTTPClient(env)
+	if HTTPClient(env) != c {
+		t.Error("expected one shared client per environment configuration")
+	}
+	resp, err := c.Get("http://operaton.internal/engine-rest/job")
+	if err != nil {
+		t.Fatalf("request via proxy failed: %v", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if proxied != "http://operaton.internal/engine-rest/job" || string(body) != "acme" {
+		t.Errorf("expected the request and header at the proxy, got %q %q", proxied, body)
+	}
+
+	slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(300 * time.Millisecond)
+	}))
+	defer slow.Close()
+	if _, err := HTTPClient(cfgpkg.Environment{URL: slow.URL, Timeout: 50 * time.Millisecond}).Get(slow.URL); err == nil {
+		t.Error("expected the environment timeout to apply")
+	}
+	if got := (cfgpkg.Environment{}).RequestTimeout(); got != cfgpkg.DefaultTimeout {
+		t.Errorf("expected default timeout, got %v", got)
+	}
+}
+
+func TestReadOnlyEnvironmentRefusesChanges(t *testing.T) {
+	var methods []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		methods = append(methods, r.Method)
+		_, _ = io.WriteString(w, "[]")
+	}))
+	defer srv.Close()
+	env := cfgpkg.Environment{URL: srv.URL, Mode: cfgpkg.ModeReadOnly}
+
+	if _, err := Do(context.Background(), env, http.MethodGet, srv.URL+"/job", nil); err != nil {
+		t.Fatalf("expected reads to pass, got %v", err)
+	}
+	if _, err := Do(context.Background(), env, http.MethodDelete, srv.URL+"/job/j1", nil); !errors.Is(err, ErrReadOnly) {
+		t.Errorf("expected ErrReadOnly, got %v", err)
+	}
+	if len(methods) != 1 {
+		t.Errorf("expected only the GET to reach the engine, got %v", methods)
+	}
+}
@@ -0,0 +1,117 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultAlertInterval is how often alert rules are evaluated when
+// alert_interval is not set.
+const DefaultAlertInterval = time.Minute
+
+// AlertNow is the param value replaced by the current time when a rule is
+// evaluated, e.g. dueBefore: now for overdue tasks.
+const AlertNow = "now"
+
+// AlertRule watches the number of rows of a table, filtered by query params,
+// in every environment (or those in Envs) and fires when When holds.
+type AlertRule struct {
+	Name   string            `yaml:"name"`
+	Table  string            `yaml:"table"`
+	Params map[string]string `yaml:"params,omitempty"`
+	// When is "count <op> <n>" with op one of > >= < <= == != (fires when it
+	// becomes true), or "increased" (fires whenever the count grows).
+	When string   `yaml:"when"`
+	Envs []string `yaml:"envs,omitempty"`
+}
+
+// AlertCondition is the parsed When of an AlertRule.
+type AlertCondition struct {
+	Increased bool
+	Op        string
+	Threshold int
+}
+
+// ParseAlertCondition parses the When of an alert rule.
+func ParseAlertCondition(when string) (AlertCondition, error) {
+	fields := strings.Fields(when)
+	if len(fields) == 1 && fields[0] == "increased" {
+		return AlertCondition{Increased: true}, nil
+	}
+	if len(fields) != 3 || fields[0] != "count" {
+		return AlertCondition{}, fmt.Errorf("invalid condition %q, expected \"count <op> <n>\" or \"increased\"", when)
+	}
+	switch fields[1] {
+	case ">", ">=", "<", "<=", "==", "!=":
+	default:
+		return AlertCondition{}, fmt.Errorf("invalid operator %q in %q", fields[1], when)
+	}
+	n, err := strconv.Atoi(fields[2])
+	if err != nil {
+		return AlertCondition{}, fmt.Errorf("invalid number %q in %q", fields[2], when)
+	}
+	return AlertCondition{Op: fields[1], Threshold: n}, nil
+}
+
+// Holds reports whether count satisfies a threshold condition.
+func (c AlertCondition) Holds(count int) bool {
+	switch c.Op {
+	case ">":
+		return count > c.Threshold
+	case ">=":
+		return count >= c.Threshold
+	case "<":
+		return count < c.Threshold
+	case "<=":
+		return count <= c.Threshold
+	case "==":
+		return count == c.Threshold
+	case "!=":
+		return count != c.Threshold
+	}
+	return false
+}
+
+// Fires reports whether the condition fires for count given the count of the
+// previous evaluation (hasPrev false on the first one): a threshold fires when
+// it starts to hold, increased whenever the count grew.
+func (c AlertCondition) Fires(prev, count int, hasPrev bool) bool {
+	if c.Increased {
+		return hasPrev && count > prev
+	}
+	return c.Holds(count) && (!hasPrev || !c.Holds(prev))
+}
+
+// WatchesEnv reports whether the rule applies to the environment name.
+func (r AlertRule) WatchesEnv(name string) bool {
+	if len(r.Envs) == 0 {
+		return true
+	}
+	for _, e := range r.Envs {
+		if e == name {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidateAlerts checks that every rule has a unique name, a table and a valid
+// condition.
+func ValidateAlerts(rules []AlertRule) error {
+	seen := map[string]bool{}
+	for i, r := range rules {
+		if r.Name == "" || r.Table == "" {
+			return fmt.Errorf("alert %d: name and table are required", i+1)
+		}
+		if seen[r.Name] {
+			return fmt.Errorf("alert %s: duplicate name", r.Name)
+		}
+		seen[r.Name] = true
+		if _, err := ParseAlertCondition(r.When); err != nil {
+			return fmt.Errorf("alert %s: %w", r.Name, err)
+		}
+	}
+	return nil
+}
@@ -4,8 +4,10 @@ package config
 import (
 	"fmt"
 	"os"
+	"path/filepath"
 	"sort"
 	"strings"
+	"time"
 
 	"gopkg.in/yaml.v3"
 )
@@ -14,9 +16,112 @@ import (
 // Note: For production use, consider storing sensitive credentials like passwords
 // in environment variables or a secure secrets manager rather than in config files
 type Environment struct {
-	URL      string `yaml:"url"`
-	Username string `yaml:"username"`
-	Password string `yaml:"password"` // Consider using environment variables for sensitive data
+	URL      string      `yaml:"url"`
+	Username string      `yaml:"username"`
+	Password string      `yaml:"password"`       // prefer ${VAR}, password_cmd or the credentials file
+	Auth     *AuthConfig `yaml:"auth,omitempty"` // nil = basic auth with Username/Password
+	// PasswordCmd is a shell command printing the password (or the client secret
+	// with client_credentials auth), e.g. "pass show operaton/prod".
+	PasswordCmd string `yaml:"password_cmd,omitempty"`
+	// Mode guards the environment against accidental changes: ModeNormal (default),
+	// ModeProtected or ModeReadOnly.
+	Mode string `yaml:"mode,omitempty"`
+
+	// Transport settings, applied by the shared client.HTTPClient of the environment.
+	Timeout            time.Duration     `yaml:"timeout,omitempty"`              // per request; 0 = DefaultTimeout
+	CAFile             string            `yaml:"ca_file,omitempty"`              // PEM bundle trusted in addition to the system roots
+	CertFile           string            `yaml:"cert_file,omitempty"`            // client certificate (PEM) for mTLS
+	KeyFile            string            `yaml:"key_file,omitempty"`             // private key (PEM) of cert_file
+	InsecureSkipVerify bool              `yaml:"insecure_skip_verify,omitempty"` // disables certificate verification
+	Proxy              string            `yaml:"proxy,omitempty"`                // proxy URL; "" = HTTP(S)_PROXY, "direct" = none
+	Headers            map[string]string `yaml:"headers,omitempty"`              // sent with every request
+}
+
+// Environment modes of Environment.Mode.
+const (
+	ModeNormal    = "normal"    // mutations run as configured
+	ModeProtected = "protected" // every mutation needs a typed confirmation
+	ModeReadOnly  = "read-only" // mutations are hidden and refused
+)
+
+// ReadOnly reports whether the environment refuses all changes.
+func (e Environment) ReadOnly() bool { return e.Mode == ModeReadOnly }
+
+// Protected reports whether every change must be confirmed by typing.
+func (e Environment) Protected() bool { return e.Mode == ModeProtected }
+
+// validateMode rejects unknown modes; a typo must not silently mean normal.
+func (e Environment) validateMode() error {
+	switch e.Mode {
+	case "", ModeNormal, ModeProtected, ModeReadOnly:
+		return nil
+	}
+	return fmt.Errorf("mode %q: must be %s, %s or %s", e.Mode, ModeNormal, ModeProtected, ModeReadOnly)
+}
+
+// DefaultTimeout is the request timeout of environments without timeout.
+const DefaultTimeout = 10 * time.Second
+
+// RequestTimeout returns the configured timeout or DefaultTimeout.
+func (e Environment) RequestTimeout() time.Duration {
+	if e.Timeout > 0 {
+		return e.Timeout
+	}
+	return DefaultTimeout
+}
+
+// Auth types of AuthConfig.Type.
+const (
+	AuthBasic             = "basic"
+	AuthBearer            = "bearer"
+	AuthClientCredentials = "client_credentials"
+)
+
+// AuthConfig selects how requests to an environment authenticate.
+//
+//   - basic (default): HTTP basic auth with the environment's Username/Password.
+//   - bearer: a static access Token; with RefreshToken and TokenURL it is refreshed
+//     through the refresh_token grant when the engine rejects it.
+//   - client_credentials: an OAuth2/OIDC client credentials grant at TokenURL
+//     (e.g. Keycloak's .../protocol/openid-connect/token).
+type AuthConfig struct {
+	Type         string   `yaml:"type"`
+	Token        string   `yaml:"token,omitempty"`
+	RefreshToken string   `yaml:"refresh_token,omitempty"`
+	TokenURL     string   `yaml:"token_url,omitempty"`
+	ClientID     string   `yaml:"client_id,omitempty"`
+	ClientSecret string   `yaml:"client_secret,omitempty"`
+	Scopes       []string `yaml:"scopes,omitempty"`
+	Audience     string   `yaml:"audience,omitempty"`
+}
+
+// IsBearer reports whether requests carry a bearer token instead of basic auth.
+func (a *AuthConfig) IsBearer() bool {
+	return a != nil && (a.Type == AuthBearer || a.Type == AuthClientCredentials)
+}
+
+// Validate checks that the fields required by the auth type are set.
+func (a *AuthConfig) Validate() error {
+	if a == nil {
+		return nil
+	}
+	switch a.Type {
+	case "", AuthBasic:
+		return nil
+	case AuthBearer:
+		// the token itself may come from the credentials file, so only check consistency
+		if a.RefreshToken != "" && a.TokenURL == "" {
+			return fmt.Errorf("bearer auth with refresh_token requires token_url")
+		}
+		return nil
+	case AuthClientCredentials:
+		if a.TokenURL == "" || a.ClientID == "" {
+			return fmt.Errorf("client_credentials auth requires token_url and client_id")
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown auth type %q (use basic, bearer or client_credentials)", a.Type)
+	}
 }
 
 // ColumnDef defines a table column in the UI config.
@@ -155,7 +260,7 @@ type EditActionDef struct {
 type ActionDef struct {
 	Key      string `yaml:"key"`                 // shortcut key (e.g. "s", "r", "ctrl+d")
 	Label    string `yaml:"label"`               // display label (e.g. "Suspend Instance")
-	Type     string `yaml:"type,omitempty"`      // "navigate" | "" (default: HTTP mutation)
+	Type     string `yaml:"type,omitempty"`      // "navigate" | "batch" | "" (default: HTTP mutation)
 	Target   string `yaml:"target,omitempty"`    // navigate: target resource key
 	Param    string `yaml:"param,omitempty"`     // navigate: query param name
 	Column   string `yaml:"column,omitempty"`    // navigate: source column for ID (default "id")
@@ -164,6 +269,7 @@ type ActionDef struct {
 	Body     string `yaml:"body,omitempty"`      // optional JSON body to send
 	Confirm  bool   `yaml:"confirm,omitempty"`   // require double-press confirmation
 	IDColumn string `yaml:"id_column,omitempty"` // column to read ID from (defaults to "id")
+	Batch    string `yaml:"batch,omitempty"`     // batch: server-side operation applied to the current filter (e.g. "job-retries")
 }
 
 // TableDef defines a named table and its columns
@@ -172,6 +278,9 @@ type TableDef struct {
 	ApiPath     string         `yaml:"api_path,omitempty"`     // REST collection path (defaults to /{name})
 	CountPath   string         `yaml:"count_path,omitempty"`   // count endpoint (defaults to {api_path}/count)
 	SearchParam string         `yaml:"search_param,omitempty"` // query param name for server-side text search
+	Aliases     []string       `yaml:"aliases,omitempty"`      // short names for the : command line (e.g. pi)
+	KeyParam    string         `yaml:"key_param,omitempty"`    // query param a bare `:table value` filters by
+	Refresh     time.Duration  `yaml:"refresh,omitempty"`      // auto-refresh interval; 0 = --refresh or 5s
 	Columns     []ColumnDef    `yaml:"columns"`
 	Drilldown   *DrillDownDef  `yaml:"drilldown,omitempty"`
 	Actions     []ActionDef    `yaml:"actions,omitempty"`
@@ -183,6 +292,27 @@ type EnvConfig struct {
 	Environments map[string]Environment `yaml:"environments"`
 	Active       string                 `yaml:"active,omitempty"`
 	Skin         string                 `yaml:"skin,omitempty"`
+	// CredentialsFile is a passphrase-encrypted file of per-environment secrets
+	// (see EncryptCredentials), applied by ResolveSecrets.
+	CredentialsFile string `yaml:"credentials_file,omitempty"`
+	// AuditFile is the append-only JSONL log of changing requests; see AuditPath.
+	AuditFile string `yaml:"audit_file,omitempty"`
+}
+
+// AuditPath returns the audit log file: AuditFile with ${VAR} references
+// expanded, by default audit.jsonl in the o6n user configuration directory.
+func (c *EnvConfig) AuditPath() string {
+	if c.AuditFile != "" {
+		if path, err := expandEnvVars(c.AuditFile); err == nil {
+			return path
+		}
+		return c.AuditFile
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "o6n-audit.jsonl"
+	}
+	return filepath.Join(dir, "o6n", "audit.jsonl")
 }
 
 // AppConfig holds application-level configuration (moved to o6n-cfg.yaml)
@@ -190,6 +320,9 @@ type AppConfig struct {
 	Tables  []TableDef `yaml:"tables,omitempty"`
 	UI      *UIConfig  `yaml:"ui,omitempty"`
 	VimMode bool       `yaml:"vim_mode,omitempty"`
+	// Alerts are evaluated every AlertInterval (DefaultAlertInterval if 0).
+	Alerts        []AlertRule   `yaml:"alerts,omitempty"`
+	AlertInterval time.Duration `yaml:"alert_interval,omitempty"`
 }
 
 // Config is a compatibility type combining environment and app config
@@ -234,6 +367,7 @@ type NavState struct {
 	SelectedDefinitionKey string            `yaml:"selected_definition_key,omitempty"`
 	SelectedInstanceID    string            `yaml:"selected_instance_id,omitempty"`
 	GenericParams         map[string]string `yaml:"generic_params,omitempty"`
+	QueryFilters          map[string]string `yaml:"query_filters,omitempty"`
 }
 
 // AppState holds mutable runtime state persisted to o6n-stat.yml.
@@ -243,6 +377,10 @@ type AppState struct {
 	Skin        string   `yaml:"skin,omitempty"`
 	ShowLatency bool     `yaml:"show_latency,omitempty"`
 	Navigation  NavState `yaml:"navigation,omitempty"`
+	// HomeDashboard opens the engine dashboard at startup (home context "dashboard").
+	HomeDashboard bool `yaml:"home_dashboard,omitempty"`
+	// CommandHistory holds the last commands of the : command line, oldest first.
+	CommandHistory []string `yaml:"command_history,omitempty"`
 }
 
 // LoadAppState loads runtime state from the given path (o6n-stat.yml).
@@ -303,6 +441,20 @@ func LoadEnvConfig(path string) (*EnvConfig, error) {
 	if err := yaml.Unmarshal(data, &cfg); err != nil {
 		return nil, fmt.Errorf("failed to parse env config file %s: %w", path, err)
 	}
+	names := make([]string, 0, len(cfg.Environments))
+	for name := range cfg.Environments {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		env := cfg.Environments[name]
+		if err := env.Auth.Validate(); err != nil {
+			return nil, fmt.Errorf("environment %s in %s: %w", name, path, err)
+		}
+		if err := env.validateMode(); err != nil {
+			return nil, fmt.Errorf("environment %s in %s: %w", name, path, err)
+		}
+	}
 	return &cfg, nil
 }
 
@@ -328,6 +480,9 @@ func LoadAppConfig(path string) (*AppConfig, error) {
 	if err := yaml.Unmarshal(data, &cfg); err != nil {
 		return nil, fmt.Errorf("failed to parse app config file %s: %w", path, err)
 	}
+	if err := ValidateAlerts(cfg.Alerts); err != nil {
+		return nil, fmt.Errorf("invalid app config file %s: %w", path, err)
+	}
 	return &cfg, nil
 }
 
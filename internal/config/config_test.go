@@ -4,6 +4,7 @@ import (
 	"os"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/kthoms/o6n/internal/config"
 	"gopkg.in/yaml.v3"
@@ -61,6 +62,64 @@ func TestLoadEnvConfig_NoWarnOnRestrictedPermissions(t *testing.T) {
 	}
 }
 
+func TestLoadEnvConfig_AuthBlock(t *testing.T) {
+	path := t.TempDir() + "/o6n-env.yaml"
+	data := `environments:
+  shared:
+    url: https://operaton.example.com/engine-rest
+    auth:
+      type: client_credentials
+      token_url: https://kc.example.com/realms/ops/protocol/openid-connect/token
+      client_id: o6n
+      client_secret: s3cret
+      scopes: [openid, engine]
+    timeout: 30s
+    headers:
+      X-Tenant: acme
+`
+	if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := config.LoadEnvConfig(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	auth := cfg.Environments["shared"].Auth
+	if auth == nil || !auth.IsBearer() || auth.ClientID != "o6n" || len(auth.Scopes) != 2 {
+		t.Fatalf("unexpected auth block: %+v", auth)
+	}
+	if env := cfg.Environments["shared"]; env.RequestTimeout() != 30*time.Second || env.Headers["X-Tenant"] != "acme" {
+		t.Errorf("unexpected transport settings: %v %v", env.Timeout, env.Headers)
+	}
+
+	bad := strings.Replace(data, "      client_id: o6n\n", "", 1)
+	if err := os.WriteFile(path, []byte(bad), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := config.LoadEnvConfig(path); err == nil || !strings.Contains(err.Error(), "client_id") {
+		t.Errorf("expected missing client_id error, got %v", err)
+	}
+}
+
+func TestLoadEnvConfig_Mode(t *testing.T) {
+	path := t.TempDir() + "/o6n-env.yaml"
+	write := func(mode string) {
+		data := "environments:\n  prod:\n    url: https://prod.example.com/engine-rest\n    mode: " + mode + "\n"
+		if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("read-only")
+	cfg, err := config.LoadEnvConfig(path)
+	if err != nil || !cfg.Environments["prod"].ReadOnly() || cfg.Environments["prod"].Protected() {
+		t.Fatalf("expected a read-only environment, got %+v %v", cfg, err)
+	}
+	write("readonly")
+	if _, err := config.LoadEnvConfig(path); err == nil || !strings.Contains(err.Error(), `mode "readonly"`) {
+		t.Errorf("expected an unknown mode error, got %v", err)
+	}
+}
+
 func TestTableDefNewFields_RoundTrip(t *testing.T) {
 	raw := `
 tables:
@@ -166,3 +225,62 @@ tables:
 		t.Errorf("Target: got %q", dd.Target)
 	}
 }
+
+func TestSavedViews_RoundTrip(t *testing.T) {
+	path := t.TempDir() + "/o6n-views.yaml"
+	if views, err := config.LoadViews(path); err != nil || views != nil {
+		t.Fatalf("expected no views for a missing file, got %v %v", views, err)
+	}
+	views := config.PutView(nil, config.SavedView{Name: "stuck-jobs", Root: "job", QueryFilters: map[string]string{"noRetriesLeft": "true"}, Sort: "DUE DATE"})
+	views = config.PutView(views, config.SavedView{Name: "active", Root: "process-instance"})
+	views = config.PutView(views, config.SavedView{Name: "stuck-jobs", Root: "job", SortDesc: true})
+	if len(views) != 2 {
+		t.Fatalf("expected a view of the same name to be replaced, got %d views", len(views))
+	}
+	if err := config.SaveViews(path, views); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := config.LoadViews(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(loaded) != 2 || loaded[0].Name != "active" {
+		t.Fatalf("expected views sorted by name, got %+v", loaded)
+	}
+	if v, ok := config.FindView(loaded, "stuck-jobs"); !ok || !v.SortDesc || v.Sort != "" {
+		t.Errorf("expected the replaced view, got %+v", v)
+	}
+	if _, err := config.ParseViews([]byte("views:\n  - name: broken\n")); err == nil {
+		t.Error("expected a view without root to be rejected")
+	}
+}
+
+func TestAlertConditions(t *testing.T) {
+	gt, err := config.ParseAlertCondition("count > 0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !gt.Fires(0, 2, false) || !gt.Fires(0, 1, true) || gt.Fires(1, 2, true) || gt.Fires(0, 0, true) {
+		t.Error("expected a threshold to fire only when it starts to hold")
+	}
+	inc, err := config.ParseAlertCondition("increased")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if inc.Fires(0, 5, false) || !inc.Fires(2, 3, true) || inc.Fires(3, 3, true) {
+		t.Error("expected increased to fire on every growth after the first count")
+	}
+	for _, bad := range []string{"", "count", "count ~ 1", "count > x", "rows > 1"} {
+		if _, err := config.ParseAlertCondition(bad); err == nil {
+			t.Errorf("expected %q to be rejected", bad)
+		}
+	}
+
+	path := t.TempDir() + "/o6n-cfg.yaml"
+	if err := os.WriteFile(path, []byte("alerts:\n  - name: x\n    table: job\n    when: count >> 1\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := config.LoadAppConfig(path); err == nil || !strings.Contains(err.Error(), "alert x") {
+		t.Errorf("expected an invalid rule to fail loading, got %v", err)
+	}
+}
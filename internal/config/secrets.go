@@ -0,0 +1,311 @@
+package config
+
+import (
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// envVarRef matches ${VAR} references in o6n-env.yaml values. A bare $ is left
+// alone so passwords containing $ need no escaping.
+var envVarRef = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+// expandEnvVars replaces ${VAR} references in s with the process environment.
+// Unset variables are an error rather than silently empty credentials.
+func expandEnvVars(s string) (string, error) {
+	var missing []string
+	out := envVarRef.ReplaceAllStringFunc(s, func(ref string) string {
+		name := envVarRef.FindStringSubmatch(ref)[1]
+		v, ok := os.LookupEnv(name)
+		if !ok {
+			missing = append(missing, name)
+		}
+		return v
+	})
+	if len(missing) > 0 {
+		return "", fmt.Errorf("environment variable %s is not set", strings.Join(missing, ", "))
+	}
+	return out, nil
+}
+
+// Credentials are the secrets of one environment in the encrypted credentials
+// file. Non-empty values fill the matching empty fields of the environment.
+type Credentials struct {
+	Username     string `yaml:"username,omitempty"`
+	Password     string `yaml:"password,omitempty"`
+	Token        string `yaml:"token,omitempty"`
+	RefreshToken string `yaml:"refresh_token,omitempty"`
+	ClientSecret string `yaml:"client_secret,omitempty"`
+}
+
+// ResolveSecrets expands ${VAR} references in every environment and fills empty
+// credentials from the encrypted CredentialsFile. passphrase is only called when
+// a credentials file is configured. password_cmd is left to
+// Environment.WithCommandSecrets so commands only run for environments in use.
+//
+// The result holds plain-text secrets: never save a resolved EnvConfig.
+func (c *EnvConfig) ResolveSecrets(passphrase func() (string, error)) error {
+	names := make([]string, 0, len(c.Environments))
+	for name := range c.Environments {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var store map[string]Credentials
+	if c.CredentialsFile != "" {
+		path, err := expandEnvVars(c.CredentialsFile)
+		if err != nil {
+			return fmt.Errorf("credentials_file: %w", err)
+		}
+		if strings.HasPrefix(path, "~/") {
+			if home, err := os.UserHomeDir(); err == nil {
+				path = filepath.Join(home, path[2:])
+			}
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("credentials file: %w", err)
+		}
+		pass, err := passphrase()
+		if err != nil {
+			return fmt.Errorf("credentials file %s: %w", path, err)
+		}
+		if store, err = DecryptCredentials(data, pass); err != nil {
+			return fmt.Errorf("credentials file %s: %w", path, err)
+		}
+	}
+
+	for _, name := range names {
+		env := c.Environments[name]
+		if env.Auth != nil {
+			a := *env.Auth
+			env.Auth = &a
+		}
+		fields := []*string{&env.URL, &env.Username, &env.Password, &env.PasswordCmd,
+			&env.CAFile, &env.CertFile, &env.KeyFile, &env.Proxy}
+		if env.Auth != nil {
+			fields = append(fields, &env.Auth.Token, &env.Auth.RefreshToken, &env.Auth.TokenURL,
+				&env.Auth.ClientID, &env.Auth.ClientSecret, &env.Auth.Audience)
+		}
+		for _, f := range fields {
+			v, err := expandEnvVars(*f)
+			if err != nil {
+				return fmt.Errorf("environment %s: %w", name, err)
+			}
+			*f = v
+		}
+		if cred, ok := store[name]; ok {
+			fill := func(dst *string, v string) {
+				if *dst == "" {
+					*dst = v
+				}
+			}
+			fill(&env.Username, cred.Username)
+			fill(&env.Password, cred.Password)
+			if env.Auth != nil {
+				fill(&env.Auth.Token, cred.Token)
+				fill(&env.Auth.RefreshToken, cred.RefreshToken)
+				fill(&env.Auth.ClientSecret, cred.ClientSecret)
+			}
+		}
+		c.Environments[name] = env
+	}
+	return nil
+}
+
+// secretCommandTimeout bounds password_cmd; password managers may wait for a
+// PIN entry or biometric confirmation.
+const secretCommandTimeout = 2 * time.Minute
+
+var (
+	secretCmdMu     sync.Mutex
+	secretCmdCache  = map[string]string{}
+	secretCmdErrs   = map[string]error{}
+	secretCmdFrozen bool
+)
+
+// FreezeSecretCommands stops (or, with false, resumes) running password_cmd.
+// The TUI freezes them once it owns the terminal, where a password manager
+// prompt would break the screen: from then on SecretFromCommand only returns
+// what was resolved before.
+func FreezeSecretCommands(frozen bool) {
+	secretCmdMu.Lock()
+	defer secretCmdMu.Unlock()
+	secretCmdFrozen = frozen
+}
+
+// SecretFromCommand runs a password_cmd through the shell and returns its first
+// output line. Results are cached per command for the lifetime of the process.
+func SecretFromCommand(command string) (string, error) {
+	secretCmdMu.Lock()
+	defer secretCmdMu.Unlock()
+	if v, ok := secretCmdCache[command]; ok {
+		return v, nil
+	}
+	if secretCmdFrozen {
+		if err, ok := secretCmdErrs[command]; ok {
+			return "", err
+		}
+		return "", fmt.Errorf("not run while the terminal UI is active; restart o6n to resolve it")
+	}
+	secret, err := runSecretCommand(command)
+	if err != nil {
+		secretCmdErrs[command] = err
+		return "", err
+	}
+	delete(secretCmdErrs, command)
+	secretCmdCache[command] = secret
+	return secret, nil
+}
+
+// runSecretCommand runs command through the shell and returns its first output line.
+func runSecretCommand(command string) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), secretCommandTimeout)
+	defer cancel()
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.CommandContext(ctx, "cmd", "/C", command)
+	} else {
+		cmd = exec.CommandContext(ctx, "sh", "-c", command)
+	}
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("%w: %s", err, msg)
+		}
+		return "", err
+	}
+	// pass and gopass print the password on the first line, metadata after it
+	secret, _, _ := strings.Cut(string(out), "\n")
+	secret = strings.TrimRight(secret, "\r")
+	if secret == "" {
+		return "", fmt.Errorf("command printed no secret")
+	}
+	return secret, nil
+}
+
+// WithCommandSecrets returns env with the output of its password_cmd as the
+// password, or as the client secret with client_credentials auth. Values set
+// directly (or resolved from the credentials file) take precedence.
+func (e Environment) WithCommandSecrets() (Environment, error) {
+	if e.PasswordCmd == "" {
+		return e, nil
+	}
+	if e.Auth != nil && e.Auth.Type == AuthClientCredentials {
+		if e.Auth.ClientSecret != "" {
+			return e, nil
+		}
+		secret, err := SecretFromCommand(e.PasswordCmd)
+		if err != nil {
+			return e, fmt.Errorf("password_cmd: %w", err)
+		}
+		a := *e.Auth
+		a.ClientSecret = secret
+		e.Auth = &a
+		return e, nil
+	}
+	if e.Password != "" {
+		return e, nil
+	}
+	secret, err := SecretFromCommand(e.PasswordCmd)
+	if err != nil {
+		return e, fmt.Errorf("password_cmd: %w", err)
+	}
+	e.Password = secret
+	return e, nil
+}
+
+// encryptedCredentials is the on-disk format of the credentials file: the YAML
+// map of Credentials sealed with AES-256-GCM under a PBKDF2-SHA256 key.
+type encryptedCredentials struct {
+	Format     string `json:"format"`
+	Iterations int    `json:"iterations"`
+	Salt       []byte `json:"salt"`
+	Nonce      []byte `json:"nonce"`
+	Data       []byte `json:"data"`
+}
+
+const (
+	credentialsFormat     = "o6n-credentials/v1"
+	credentialsIterations = 600000
+)
+
+func credentialsCipher(passphrase string, salt []byte, iterations int) (cipher.AEAD, error) {
+	key, err := pbkdf2.Key(sha256.New, passphrase, salt, iterations, 32)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// EncryptCredentials seals creds (keyed by environment name) with passphrase.
+func EncryptCredentials(creds map[string]Credentials, passphrase string) ([]byte, error) {
+	if passphrase == "" {
+		return nil, fmt.Errorf("empty passphrase")
+	}
+	plain, err := yaml.Marshal(creds)
+	if err != nil {
+		return nil, err
+	}
+	enc := encryptedCredentials{Format: credentialsFormat, Iterations: credentialsIterations, Salt: make([]byte, 16)}
+	if _, err := rand.Read(enc.Salt); err != nil {
+		return nil, err
+	}
+	aead, err := credentialsCipher(passphrase, enc.Salt, enc.Iterations)
+	if err != nil {
+		return nil, err
+	}
+	enc.Nonce = make([]byte, aead.NonceSize())
+	if _, err := rand.Read(enc.Nonce); err != nil {
+		return nil, err
+	}
+	enc.Data = aead.Seal(nil, enc.Nonce, plain, []byte(credentialsFormat))
+	return json.MarshalIndent(enc, "", "  ")
+}
+
+// DecryptCredentials opens a credentials file sealed by EncryptCredentials.
+func DecryptCredentials(data []byte, passphrase string) (map[string]Credentials, error) {
+	var enc encryptedCredentials
+	if err := json.Unmarshal(data, &enc); err != nil || enc.Format != credentialsFormat {
+		return nil, fmt.Errorf("not an o6n credentials file")
+	}
+	aead, err := credentialsCipher(passphrase, enc.Salt, enc.Iterations)
+	if err != nil {
+		return nil, err
+	}
+	if len(enc.Nonce) != aead.NonceSize() {
+		return nil, fmt.Errorf("not an o6n credentials file")
+	}
+	plain, err := aead.Open(nil, enc.Nonce, enc.Data, []byte(credentialsFormat))
+	if err != nil {
+		return nil, fmt.Errorf("wrong passphrase or corrupted file")
+	}
+	creds := map[string]Credentials{}
+	if err := yaml.Unmarshal(plain, &creds); err != nil {
+		return nil, err
+	}
+	return creds, nil
+}
@@ -0,0 +1,120 @@
+package config_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/kthoms/o6n/internal/config"
+)
+
+func TestResolveSecretsExpandsEnvVarsAndCredentialsFile(t *testing.T) {
+	t.Setenv("O6N_TEST_HOST", "operaton.example.com")
+	t.Setenv("O6N_TEST_USER", "jdoe")
+	sealed, err := config.EncryptCredentials(map[string]config.Credentials{
+		"prod":   {Password: "from-store", Username: "ignored"},
+		"shared": {ClientSecret: "cs"},
+	}, "correct horse")
+	if err != nil {
+		t.Fatal(err)
+	}
+	store := filepath.Join(t.TempDir(), "creds.enc")
+	if err := os.WriteFile(store, sealed, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(sealed), "from-store") {
+		t.Fatal("expected the credentials file to be encrypted")
+	}
+
+	newCfg := func() *config.EnvConfig {
+		return &config.EnvConfig{
+			CredentialsFile: store,
+			Environments: map[string]config.Environment{
+				"prod":   {URL: "https://${O6N_TEST_HOST}/engine-rest", Username: "${O6N_TEST_USER}", Password: "pa$$word"},
+				"shared": {URL: "https://x", Auth: &config.AuthConfig{Type: config.AuthClientCredentials, TokenURL: "https://idp", ClientID: "o6n"}},
+			},
+		}
+	}
+	cfg := newCfg()
+	cfg.Environments["prod"] = config.Environment{URL: "https://${O6N_TEST_HOST}/engine-rest", Username: "${O6N_TEST_USER}"}
+	if err := cfg.ResolveSecrets(func() (string, error) { return "correct horse", nil }); err != nil {
+		t.Fatalf("ResolveSecrets: %v", err)
+	}
+	prod := cfg.Environments["prod"]
+	if prod.URL != "https://operaton.example.com/engine-rest" || prod.Username != "jdoe" || prod.Password != "from-store" {
+		t.Errorf("unexpected resolved environment %+v", prod)
+	}
+	if cfg.Environments["shared"].Auth.ClientSecret != "cs" {
+		t.Errorf("expected client secret from the credentials file, got %+v", cfg.Environments["shared"].Auth)
+	}
+
+	// Values set in o6n-env.yaml win, and a bare $ is not a reference
+	cfg = newCfg()
+	if err := cfg.ResolveSecrets(func() (string, error) { return "correct horse", nil }); err != nil {
+		t.Fatalf("ResolveSecrets: %v", err)
+	}
+	if got := cfg.Environments["prod"].Password; got != "pa$$word" {
+		t.Errorf("expected the configured password, got %q", got)
+	}
+
+	if err := newCfg().ResolveSecrets(func() (string, error) { return "wrong", nil }); err == nil || !strings.Contains(err.Error(), "wrong passphrase") {
+		t.Errorf("expected wrong passphrase error, got %v", err)
+	}
+	cfg = newCfg()
+	cfg.CredentialsFile = ""
+	cfg.Environments["prod"] = config.Environment{Password: "${O6N_TEST_UNSET}"}
+	if err := cfg.ResolveSecrets(nil); err == nil || !strings.Contains(err.Error(), "O6N_TEST_UNSET is not set") {
+		t.Errorf("expected unset variable error, got %v", err)
+	}
+}
+
+func TestWithCommandSecrets(t *testing.T) {
+	env := config.Environment{Username: "demo", PasswordCmd: "printf 'from-cmd\\nurl: https://example.com\\n'"}
+	got, err := env.WithCommandSecrets()
+	if err != nil || got.Password != "from-cmd" {
+		t.Fatalf("expected the first output line as password, got %q %v", got.Password, err)
+	}
+	env.Password = "explicit"
+	if got, _ := env.WithCommandSecrets(); got.Password != "explicit" {
+		t.Errorf("expected an explicit password to win, got %q", got.Password)
+	}
+
+	auth := &config.AuthConfig{Type: config.AuthClientCredentials, TokenURL: "https://idp", ClientID: "o6n"}
+	cc := config.Environment{Auth: auth, PasswordCmd: "echo client-secret"}
+	if got, err := cc.WithCommandSecrets(); err != nil || got.Auth.ClientSecret != "client-secret" || auth.ClientSecret != "" {
+		t.Errorf("expected the client secret from the command on a copy, got %+v %v", got.Auth, err)
+	}
+
+	failing := config.Environment{PasswordCmd: "echo locked >&2; exit 3"}
+	if _, err := failing.WithCommandSecrets(); err == nil || !strings.Contains(err.Error(), "locked") {
+		t.Errorf("expected the command's stderr in the error, got %v", err)
+	}
+}
+
+func TestFreezeSecretCommandsOnlyReturnsResolvedSecrets(t *testing.T) {
+	resolved := config.Environment{PasswordCmd: "echo before-freeze"}
+	failed := config.Environment{PasswordCmd: "echo vault-locked >&2; exit 4"}
+	if _, err := resolved.WithCommandSecrets(); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	_, _ = failed.WithCommandSecrets()
+
+	config.FreezeSecretCommands(true)
+	defer config.FreezeSecretCommands(false)
+	if got, err := resolved.WithCommandSecrets(); err != nil || got.Password != "before-freeze" {
+		t.Errorf("expected the resolved secret while frozen, got %q %v", got.Password, err)
+	}
+	if _, err := failed.WithCommandSecrets(); err == nil || !strings.Contains(err.Error(), "vault-locked") {
+		t.Errorf("expected the startup failure while frozen, got %v", err)
+	}
+	dir := t.TempDir()
+	marker := filepath.Join(dir, "ran")
+	late := config.Environment{PasswordCmd: "touch " + marker + "; echo late"}
+	if _, err := late.WithCommandSecrets(); err == nil {
+		t.Error("expected an error for a command first needed while frozen")
+	}
+	if _, err := os.Stat(marker); err == nil {
+		t.Error("expected no command to run while frozen")
+	}
+}
@@ -0,0 +1,98 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"sort"
+
+	"gopkg.in/yaml.v3"
+)
+
+// SavedView is a named table view: the table with its drill-down params, query
+// filters, search term and sort. Views live in o6n-views.yaml, which holds no
+// credentials and can be shared with teammates.
+type SavedView struct {
+	Name string `yaml:"name"`
+	// Env is the environment the view opens in; empty keeps the current one.
+	Env                   string            `yaml:"env,omitempty"`
+	Root                  string            `yaml:"root"`
+	Title                 string            `yaml:"title,omitempty"`
+	Breadcrumb            []string          `yaml:"breadcrumb,omitempty"`
+	SelectedDefinitionKey string            `yaml:"selected_definition_key,omitempty"`
+	SelectedInstanceID    string            `yaml:"selected_instance_id,omitempty"`
+	GenericParams         map[string]string `yaml:"generic_params,omitempty"`
+	QueryFilters          map[string]string `yaml:"query_filters,omitempty"`
+	Search                string            `yaml:"search,omitempty"`
+	// Sort is the title of the sort column; SortDesc reverses its order.
+	Sort     string `yaml:"sort,omitempty"`
+	SortDesc bool   `yaml:"sort_desc,omitempty"`
+}
+
+// ViewsFile is the document stored in o6n-views.yaml.
+type ViewsFile struct {
+	Views []SavedView `yaml:"views"`
+}
+
+// LoadViews reads the saved views at path. A missing file yields no views.
+func LoadViews(path string) ([]SavedView, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read views file %s: %w", path, err)
+	}
+	return ParseViews(data)
+}
+
+// ParseViews parses a views document and checks that every view has a name and
+// a root.
+func ParseViews(data []byte) ([]SavedView, error) {
+	var f ViewsFile
+	if err := yaml.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("failed to parse views: %w", err)
+	}
+	for i, v := range f.Views {
+		if v.Name == "" || v.Root == "" {
+			return nil, fmt.Errorf("view %d: name and root are required", i+1)
+		}
+	}
+	return f.Views, nil
+}
+
+// SaveViews writes views to path sorted by name. The file is readable by others
+// so it can be checked in and shared.
+func SaveViews(path string, views []SavedView) error {
+	sorted := append([]SavedView(nil), views...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Name < sorted[j].Name })
+	data, err := yaml.Marshal(ViewsFile{Views: sorted})
+	if err != nil {
+		return fmt.Errorf("failed to marshal views: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil { // #nosec G306 -- shareable, no credentials
+		return fmt.Errorf("failed to write views file %s: %w", path, err)
+	}
+	return nil
+}
+
+// FindView returns the view called name.
+func FindView(views []SavedView, name string) (SavedView, bool) {
+	for _, v := range views {
+		if v.Name == name {
+			return v, true
+		}
+	}
+	return SavedView{}, false
+}
+
+// PutView adds v to views, replacing a view of the same name.
+func PutView(views []SavedView, v SavedView) []SavedView {
+	for i := range views {
+		if views[i].Name == v.Name {
+			out := append([]SavedView(nil), views...)
+			out[i] = v
+			return out
+		}
+	}
+	return append(append([]SavedView(nil), views...), v)
+}
@@ -2,13 +2,16 @@ package validation
 
 import (
 	"encoding/json"
+	"encoding/xml"
+	"errors"
 	"fmt"
+	"io"
 	"strconv"
 	"strings"
 )
 
 // ValidateAndParse validates input string according to inputType and returns parsed value
-// Supported inputType: bool,int,number,text,json
+// Supported inputType: bool,int,number,text,json,xml
 func ValidateAndParse(input string, inputType string) (interface{}, error) {
 	trimmed := strings.TrimSpace(input)
 	switch inputType {
@@ -38,6 +41,34 @@ func ValidateAndParse(input string, inputType string) (interface{}, error) {
 			return nil, fmt.Errorf("invalid json: %v", err)
 		}
 		return j, nil
+	case "xml":
+		// well-formedness only; the document itself is kept as entered
+		dec := xml.NewDecoder(strings.NewReader(trimmed))
+		root := false
+		depth := 0
+		for {
+			tok, err := dec.Token()
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			if err != nil {
+				return nil, fmt.Errorf("invalid xml: %v", err)
+			}
+			switch t := tok.(type) {
+			case xml.StartElement:
+				if depth == 0 && root {
+					return nil, fmt.Errorf("invalid xml: second root element <%s>", t.Name.Local)
+				}
+				root = true
+				depth++
+			case xml.EndElement:
+				depth--
+			}
+		}
+		if !root {
+			return nil, fmt.Errorf("invalid xml: no root element")
+		}
+		return input, nil
 	default:
 		// default to raw text
 		return input, nil
@@ -22,6 +22,10 @@ func TestValidateAndParse(t *testing.T) {
 		{"number valid", "3.14", "number", float64(3.14), false},
 		{"number invalid", "abc", "number", nil, true},
 		{"json valid", "{\"k\": 1}", "json", map[string]interface{}{"k": float64(1)}, false},
+		{"xml valid", "<a><b/></a>", "xml", "<a><b/></a>", false},
+		{"xml unclosed", "<a><b></a>", "xml", nil, true},
+		{"xml empty", "", "xml", nil, true},
+		{"xml two roots", "<a/><b/>", "xml", nil, true},
 		{"text default", "hello", "text", "hello", false},
 		{"empty text", "", "text", "", false},
 	}